@@ -0,0 +1,201 @@
+// Package bloom implements bloom filters, sets telling whether an element was probably added or surely not,
+// e.g. to skip looking up keys known to be missing from a cache or a database.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/dreamsxin/go-utils/hash/siphash"
+)
+
+// ErrInvalidData is returned by UnmarshalBinary for data not produced by MarshalBinary
+var ErrInvalidData = errors.New("bloom: invalid data")
+
+// keys of the hash, fixed so that serialized filters stay valid
+const (
+	k0 = 0x0706050403020100
+	k1 = 0x0f0e0d0c0b0a0908
+)
+
+// maxK is the maximum number of hash functions, enough for false positive rates down to 1e-19
+const maxK = 64
+
+// Estimate returns the number of bits m and of hash functions k of a filter
+// holding n elements with a false positive rate of p, it panics unless 0 < p < 1
+func Estimate(n uint64, p float64) (m uint64, k uint32) {
+	if !(p > 0 && p < 1) {
+		panic("bloom: false positive rate must be between 0 and 1")
+	}
+	n = max(n, 1)
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+	return max(m, 1), min(max(k, 1), maxK)
+}
+
+// locations calls f with the k positions of data among m, using double hashing
+func locations(data []byte, m uint64, k uint32, f func(i uint64) bool) bool {
+	h1, h2 := siphash.Hash128(k0, k1, data)
+	for i := uint32(0); i < k; i++ {
+		if !f((h1 + uint64(i)*h2) % m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter is a standard bloom filter, it is safe for concurrent use
+type Filter struct {
+	mutex sync.RWMutex
+	m     uint64
+	k     uint32
+	// n is the number of elements added
+	n    uint64
+	bits []uint64
+}
+
+// New creates a filter sized for n elements with a false positive rate of p, e.g. New(1_000_000, 0.01)
+func New(n uint64, p float64) *Filter {
+	return NewWithSize(Estimate(n, p))
+}
+
+// NewWithSize creates a filter of m bits using k hash functions, at most 64
+func NewWithSize(m uint64, k uint32) *Filter {
+	m, k = max(m, 1), min(max(k, 1), maxK)
+	return &Filter{m: m, k: k, bits: make([]uint64, (m+63)/64)}
+}
+
+// Cap returns the number of bits of the filter
+func (f *Filter) Cap() uint64 {
+	return f.m
+}
+
+// K returns the number of hash functions of the filter
+func (f *Filter) K() uint32 {
+	return f.k
+}
+
+// Len returns the number of elements added, elements added twice included
+func (f *Filter) Len() uint64 {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.n
+}
+
+// Add adds data to the filter
+func (f *Filter) Add(data []byte) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.add(data)
+}
+
+// AddString adds s to the filter
+func (f *Filter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+func (f *Filter) add(data []byte) {
+	locations(data, f.m, f.k, func(i uint64) bool {
+		f.bits[i/64] |= 1 << (i % 64)
+		return true
+	})
+	f.n++
+}
+
+// Test reports whether data was probably added, false when it surely was not
+func (f *Filter) Test(data []byte) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.test(data)
+}
+
+// TestString reports whether s was probably added
+func (f *Filter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+func (f *Filter) test(data []byte) bool {
+	return locations(data, f.m, f.k, func(i uint64) bool {
+		return f.bits[i/64]&(1<<(i%64)) != 0
+	})
+}
+
+// TestAndAdd reports whether data was probably added before adding it
+func (f *Filter) TestAndAdd(data []byte) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	found := f.test(data)
+	f.add(data)
+	return found
+}
+
+// FalsePositiveRate estimates the false positive rate from the elements added
+func (f *Filter) FalsePositiveRate() float64 {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return math.Pow(1-math.Exp(-float64(f.k)*float64(f.n)/float64(f.m)), float64(f.k))
+}
+
+// Clear removes all the elements
+func (f *Filter) Clear() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	clear(f.bits)
+	f.n = 0
+}
+
+// header is the serialized size of magic, k, m and n
+const header = 4 + 4 + 8 + 8
+
+// MarshalBinary encodes the filter, e.g. to persist it in a cache
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	data := appendHeader(make([]byte, 0, header+8*len(f.bits)), "BLM1", f.k, f.m, f.n)
+	for _, w := range f.bits {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter encoded by MarshalBinary, replacing the filter
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	k, m, n, data, err := parseHeader(data, "BLM1")
+	if err != nil {
+		return err
+	}
+	// compare the bit counts first, (m+63) overflows when m is close to the limit
+	if m > uint64(len(data))*8 || uint64(len(data)) != (m+63)/64*8 {
+		return ErrInvalidData
+	}
+	bits := make([]uint64, len(data)/8)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.k, f.m, f.n, f.bits = k, m, n, bits
+	return nil
+}
+
+func appendHeader(data []byte, magic string, k uint32, m, n uint64) []byte {
+	data = append(data, magic...)
+	data = binary.LittleEndian.AppendUint32(data, k)
+	data = binary.LittleEndian.AppendUint64(data, m)
+	return binary.LittleEndian.AppendUint64(data, n)
+}
+
+func parseHeader(data []byte, magic string) (k uint32, m, n uint64, rest []byte, err error) {
+	if len(data) < header || string(data[:4]) != magic {
+		return 0, 0, 0, nil, ErrInvalidData
+	}
+	k = binary.LittleEndian.Uint32(data[4:])
+	m = binary.LittleEndian.Uint64(data[8:])
+	n = binary.LittleEndian.Uint64(data[16:])
+	if k == 0 || k > maxK || m == 0 {
+		return 0, 0, 0, nil, ErrInvalidData
+	}
+	return k, m, n, data[header:], nil
+}
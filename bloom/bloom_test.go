@@ -0,0 +1,143 @@
+package bloom
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	m, k := Estimate(1000, 0.01)
+	if m != 9586 || k != 7 {
+		t.Errorf("expect 9586 bits and 7 hashes, get %d and %d", m, k)
+	}
+	if _, k := Estimate(1000, 1e-300); k != maxK {
+		t.Errorf("expect %d hashes at most, get %d", maxK, k)
+	}
+	for _, p := range []float64{0, 1, -1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expect a panic for a false positive rate of %v", p)
+				}
+			}()
+			New(1000, p)
+		}()
+	}
+}
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+	f := New(n, 0.01)
+	for i := 0; i < n; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("expect %d found", i)
+		}
+	}
+	falsePositives := 0
+	for i := n; i < 2*n; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / n; rate > 0.02 {
+		t.Errorf("expect a false positive rate about 1%%, get %v", rate)
+	}
+	if rate := f.FalsePositiveRate(); rate < 0.005 || rate > 0.015 {
+		t.Errorf("expect an estimated rate about 1%%, get %v", rate)
+	}
+
+	if f.TestAndAdd([]byte("new")) || !f.TestAndAdd([]byte("new")) {
+		t.Error("expect TestAndAdd to report the element added before")
+	}
+	f.Clear()
+	if f.TestString("0") || f.Len() != 0 {
+		t.Error("expect an empty filter after Clear")
+	}
+}
+
+func TestFilterMarshal(t *testing.T) {
+	f := New(100, 0.01)
+	f.AddString("a")
+	f.AddString("b")
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g Filter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !g.TestString("a") || !g.TestString("b") || g.TestString("c") || g.Len() != 2 || g.Cap() != f.Cap() || g.K() != f.K() {
+		t.Errorf("expect the same filter, get %d bits, %d hashes and %d elements", g.Cap(), g.K(), g.Len())
+	}
+	if err := g.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expect ErrInvalidData for truncated data, get %v", err)
+	}
+	var c CountingFilter
+	if err := c.UnmarshalBinary(data); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expect ErrInvalidData for another kind of filter, get %v", err)
+	}
+
+	huge := appendHeader(nil, "BLM1", 1, math.MaxUint64, 0)
+	if err := g.UnmarshalBinary(huge); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expect ErrInvalidData for an overflowing size, get %v", err)
+	}
+	tooMany := append(appendHeader(nil, "BLM1", maxK+1, 64, 0), make([]byte, 8)...)
+	if err := g.UnmarshalBinary(tooMany); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expect ErrInvalidData for too many hashes, get %v", err)
+	}
+}
+
+func TestCountingFilter(t *testing.T) {
+	f := NewCounting(1000, 0.01)
+	f.AddString("a")
+	f.AddString("b")
+	f.AddString("b")
+	if !f.RemoveString("b") || !f.TestString("b") {
+		t.Error("expect b found until removed as many times as added")
+	}
+	if !f.RemoveString("b") || f.TestString("b") {
+		t.Error("expect b removed")
+	}
+	if f.RemoveString("c") || f.Len() != 1 || !f.TestString("a") {
+		t.Errorf("expect only a left, get %d elements", f.Len())
+	}
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewCountingWithSize(1, 1)
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !g.RemoveString("a") || g.TestString("a") || g.Len() != 0 {
+		t.Error("expect the counters restored")
+	}
+}
+
+func TestCountingSaturation(t *testing.T) {
+	f := NewCountingWithSize(8, 2)
+	for i := 0; i < 300; i++ {
+		f.AddString("a")
+	}
+	for i := 0; i < 300; i++ {
+		f.RemoveString("a")
+	}
+	if !f.TestString("a") {
+		t.Error("expect saturated counters never decremented")
+	}
+}
+
+func BenchmarkFilterAdd(b *testing.B) {
+	f := New(uint64(b.N), 0.01)
+	data := []byte("benchmark")
+	for i := 0; i < b.N; i++ {
+		f.Add(data)
+	}
+}
@@ -0,0 +1,135 @@
+package bloom
+
+import (
+	"math"
+	"sync"
+)
+
+// CountingFilter is a bloom filter whose elements can be removed, at the cost of a byte per bit.
+// Counters saturate at 255, a saturated counter is never decremented.
+// It is safe for concurrent use.
+type CountingFilter struct {
+	mutex    sync.RWMutex
+	m        uint64
+	k        uint32
+	n        uint64
+	counters []uint8
+}
+
+// NewCounting creates a counting filter sized for n elements with a false positive rate of p
+func NewCounting(n uint64, p float64) *CountingFilter {
+	return NewCountingWithSize(Estimate(n, p))
+}
+
+// NewCountingWithSize creates a counting filter of m counters using k hash functions, at most 64
+func NewCountingWithSize(m uint64, k uint32) *CountingFilter {
+	m, k = max(m, 1), min(max(k, 1), maxK)
+	return &CountingFilter{m: m, k: k, counters: make([]uint8, m)}
+}
+
+// Cap returns the number of counters of the filter
+func (f *CountingFilter) Cap() uint64 {
+	return f.m
+}
+
+// K returns the number of hash functions of the filter
+func (f *CountingFilter) K() uint32 {
+	return f.k
+}
+
+// Len returns the number of elements added and not removed
+func (f *CountingFilter) Len() uint64 {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.n
+}
+
+// Add adds data to the filter
+func (f *CountingFilter) Add(data []byte) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	locations(data, f.m, f.k, func(i uint64) bool {
+		if f.counters[i] < math.MaxUint8 {
+			f.counters[i]++
+		}
+		return true
+	})
+	f.n++
+}
+
+// AddString adds s to the filter
+func (f *CountingFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Test reports whether data was probably added, false when it surely was not
+func (f *CountingFilter) Test(data []byte) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.test(data)
+}
+
+// TestString reports whether s was probably added
+func (f *CountingFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+func (f *CountingFilter) test(data []byte) bool {
+	return locations(data, f.m, f.k, func(i uint64) bool {
+		return f.counters[i] > 0
+	})
+}
+
+// Remove removes data from the filter and reports whether it was probably added.
+// Removing data which was not added, but tests positive, makes other elements test negative.
+func (f *CountingFilter) Remove(data []byte) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.test(data) {
+		return false
+	}
+	locations(data, f.m, f.k, func(i uint64) bool {
+		if f.counters[i] < math.MaxUint8 {
+			f.counters[i]--
+		}
+		return true
+	})
+	f.n--
+	return true
+}
+
+// RemoveString removes s from the filter
+func (f *CountingFilter) RemoveString(s string) bool {
+	return f.Remove([]byte(s))
+}
+
+// Clear removes all the elements
+func (f *CountingFilter) Clear() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	clear(f.counters)
+	f.n = 0
+}
+
+// MarshalBinary encodes the filter, e.g. to persist it in a cache
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	data := appendHeader(make([]byte, 0, header+len(f.counters)), "CBF1", f.k, f.m, f.n)
+	return append(data, f.counters...), nil
+}
+
+// UnmarshalBinary decodes a filter encoded by MarshalBinary, replacing the filter
+func (f *CountingFilter) UnmarshalBinary(data []byte) error {
+	k, m, n, data, err := parseHeader(data, "CBF1")
+	if err != nil {
+		return err
+	}
+	if uint64(len(data)) != m {
+		return ErrInvalidData
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.k, f.m, f.n, f.counters = k, m, n, append([]uint8(nil), data...)
+	return nil
+}
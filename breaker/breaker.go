@@ -0,0 +1,334 @@
+// Package breaker stops calling a failing dependency for a while so that it can recover,
+// instead of piling up requests doomed to fail.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dreamsxin/go-utils/bus"
+)
+
+var (
+	// ErrOpen is returned without calling the function while the breaker is open
+	ErrOpen = errors.New("breaker: circuit open")
+	// ErrTooManyProbes is returned without calling the function while the breaker is half-open
+	// and all the probes are in flight
+	ErrTooManyProbes = errors.New("breaker: too many probes")
+)
+
+const (
+	// DefaultConsecutiveFailures is the number of consecutive failures opening the breaker by default
+	DefaultConsecutiveFailures = 5
+	// DefaultOpenTimeout is how long the breaker stays open by default
+	DefaultOpenTimeout = 30 * time.Second
+	// DefaultProbes is the number of probes let through while half-open by default
+	DefaultProbes = 1
+)
+
+// State is the state of a breaker
+type State int
+
+const (
+	// StateClosed lets every request through, counting the failures
+	StateClosed State = iota
+	// StateOpen rejects every request until the open timeout elapsed
+	StateOpen
+	// StateHalfOpen lets a few probes through, closing the breaker when they all succeed
+	// and opening it again on the first failure
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// StateChange is passed to the state change callbacks and published on the bus
+type StateChange struct {
+	Name string
+	From State
+	To   State
+	At   time.Time
+}
+
+// Option represents an option that can be passed to New
+type Option func(*Breaker)
+
+// ConsecutiveFailures opens the breaker after n failures in a row, 0 disables the threshold
+func ConsecutiveFailures(n int) Option {
+	return func(b *Breaker) { b.consecutiveFailures = n }
+}
+
+// FailureRate opens the breaker when the failures reach rate, between 0 and 1, of the requests
+// completed during the last window, provided there were at least minRequests of them.
+// The failure rate is not checked by default.
+func FailureRate(rate float64, minRequests int, window time.Duration) Option {
+	return func(b *Breaker) {
+		b.failureRate = rate
+		b.minRequests = minRequests
+		b.window = newWindow(window)
+	}
+}
+
+// OpenTimeout sets how long the breaker stays open before letting probes through
+func OpenTimeout(timeout time.Duration) Option {
+	return func(b *Breaker) { b.openTimeout = timeout }
+}
+
+// Probes sets the number of requests let through while half-open, all of them must succeed to close the breaker
+func Probes(n int) Option {
+	return func(b *Breaker) { b.probes = n }
+}
+
+// IsFailure sets the function deciding which errors count as failures, e.g. to ignore context.Canceled.
+// Every error does by default.
+func IsFailure(isFailure func(error) bool) Option {
+	return func(b *Breaker) { b.isFailure = isFailure }
+}
+
+// OnStateChange adds a function called after each state change
+func OnStateChange(onStateChange func(StateChange)) Option {
+	return func(b *Breaker) { b.onStateChange = append(b.onStateChange, onStateChange) }
+}
+
+// Publish publishes each state change as a *StateChange on b, errors of the listeners are logged
+func Publish(b bus.Bus) Option {
+	return OnStateChange(func(change StateChange) {
+		if err := b.Publish(context.Background(), &change); err != nil {
+			log.Printf("breaker: publishing state change of %q: %v", change.Name, err)
+		}
+	})
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	name                string
+	consecutiveFailures int
+	failureRate         float64
+	minRequests         int
+	openTimeout         time.Duration
+	probes              int
+	isFailure           func(error) bool
+	onStateChange       []func(StateChange)
+	now                 func() time.Time
+
+	mutex sync.Mutex
+	state State
+	// generation changes with the state so that results of requests let through before are ignored
+	generation uint64
+	// openedAt is when the breaker opened
+	openedAt time.Time
+	// consecutive is the number of failures in a row
+	consecutive int
+	window      *window
+	// inFlight and succeeded count the probes while half-open
+	inFlight  int
+	succeeded int
+}
+
+// New creates a closed breaker, name identifies it in the state changes
+func New(name string, options ...Option) *Breaker {
+	b := &Breaker{
+		name:                name,
+		consecutiveFailures: DefaultConsecutiveFailures,
+		openTimeout:         DefaultOpenTimeout,
+		probes:              DefaultProbes,
+		isFailure:           func(err error) bool { return err != nil },
+		now:                 time.Now,
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	if b.probes < 1 {
+		b.probes = 1
+	}
+	return b
+}
+
+// Name returns the name of the breaker
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+	change := b.refresh()
+	state := b.state
+	b.mutex.Unlock()
+	b.notify(change)
+	return state
+}
+
+// Allow reports whether a request may go through. When it may, the caller must pass the result
+// of the request to done, otherwise err is ErrOpen or ErrTooManyProbes.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mutex.Lock()
+	change := b.refresh()
+	switch b.state {
+	case StateOpen:
+		err = ErrOpen
+	case StateHalfOpen:
+		if b.inFlight+b.succeeded >= b.probes {
+			err = ErrTooManyProbes
+		} else {
+			b.inFlight++
+		}
+	}
+	generation := b.generation
+	b.mutex.Unlock()
+	b.notify(change)
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() { b.done(generation, b.isFailure(err)) })
+	}, nil
+}
+
+// Do calls fn unless the breaker rejects it, a panic of fn counts as a failure
+func (b *Breaker) Do(fn func() error) (err error) {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	panicked := true
+	defer func() {
+		if panicked {
+			done(errPanic)
+		}
+	}()
+	err = fn()
+	panicked = false
+	done(err)
+	return err
+}
+
+var errPanic = errors.New("breaker: panic")
+
+// Call is Do for functions returning a value
+func Call[T any](b *Breaker, fn func() (T, error)) (T, error) {
+	var v T
+	err := b.Do(func() (err error) {
+		v, err = fn()
+		return err
+	})
+	return v, err
+}
+
+// Wrap returns fn guarded by the breaker, e.g. to submit it to a worker.TaskGroupWithContext
+// or to call it with retry.Do
+func (b *Breaker) Wrap(fn func() error) func() error {
+	return func() error {
+		return b.Do(fn)
+	}
+}
+
+// Batch returns fn guarded by the breaker as a batcher flush function,
+// rejected is called with the batches not flushed because of the breaker and may be nil
+func Batch[T any](b *Breaker, fn func([]T) error, rejected func([]T, error)) func([]T) {
+	return func(batch []T) {
+		if err := b.Do(func() error { return fn(batch) }); (err == ErrOpen || err == ErrTooManyProbes) && rejected != nil {
+			rejected(batch, err)
+		}
+	}
+}
+
+// Reset closes the breaker and clears its counts
+func (b *Breaker) Reset() {
+	b.mutex.Lock()
+	change := b.setState(StateClosed)
+	b.mutex.Unlock()
+	b.notify(change)
+}
+
+func (b *Breaker) done(generation uint64, failed bool) {
+	b.mutex.Lock()
+	var change *StateChange
+	if generation == b.generation {
+		change = b.record(failed)
+	}
+	b.mutex.Unlock()
+	b.notify(change)
+}
+
+// record counts the result of a request of the current state, the lock must be held
+func (b *Breaker) record(failed bool) *StateChange {
+	switch b.state {
+	case StateHalfOpen:
+		b.inFlight--
+		if failed {
+			return b.setState(StateOpen)
+		}
+		b.succeeded++
+		if b.succeeded >= b.probes {
+			return b.setState(StateClosed)
+		}
+	case StateClosed:
+		if failed {
+			b.consecutive++
+		} else {
+			b.consecutive = 0
+		}
+		if b.consecutiveFailures > 0 && b.consecutive >= b.consecutiveFailures {
+			return b.setState(StateOpen)
+		}
+		if b.window != nil {
+			requests, failures := b.window.add(b.now(), failed)
+			if requests >= b.minRequests && requests > 0 && float64(failures) >= b.failureRate*float64(requests) {
+				return b.setState(StateOpen)
+			}
+		}
+	}
+	return nil
+}
+
+// refresh moves an open breaker to half-open once the timeout elapsed, the lock must be held
+func (b *Breaker) refresh() *StateChange {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		return b.setState(StateHalfOpen)
+	}
+	return nil
+}
+
+// setState changes the state and resets the counts, the lock must be held
+func (b *Breaker) setState(state State) *StateChange {
+	from := b.state
+	now := b.now()
+	b.state = state
+	b.generation++
+	b.consecutive, b.inFlight, b.succeeded = 0, 0, 0
+	if b.window != nil {
+		b.window.reset()
+	}
+	if state == StateOpen {
+		b.openedAt = now
+	}
+	if from == state {
+		return nil
+	}
+	return &StateChange{Name: b.name, From: from, To: state, At: now}
+}
+
+// notify calls the state change callbacks out of the lock
+func (b *Breaker) notify(change *StateChange) {
+	if change == nil {
+		return
+	}
+	for _, f := range b.onStateChange {
+		f(*change)
+	}
+}
@@ -0,0 +1,209 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dreamsxin/go-utils/bus"
+)
+
+var errFailed = errors.New("failed")
+
+// clock is a fake time source advanced by hand
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(options ...Option) (*Breaker, *clock) {
+	c := &clock{now: time.Unix(1000, 0)}
+	b := New("test", options...)
+	b.now = c.Now
+	return b, c
+}
+
+func fail() error {
+	return errFailed
+}
+
+func succeed() error {
+	return nil
+}
+
+func TestConsecutiveFailures(t *testing.T) {
+	b, c := newTestBreaker(ConsecutiveFailures(3), OpenTimeout(time.Second))
+	b.Do(fail)
+	b.Do(fail)
+	b.Do(succeed)
+	b.Do(fail)
+	b.Do(fail)
+	if b.State() != StateClosed {
+		t.Fatalf("expect closed after a success, get %v", b.State())
+	}
+	if err := b.Do(fail); err != errFailed || b.State() != StateOpen {
+		t.Fatalf("expect open after 3 failures, get %v, %v", err, b.State())
+	}
+
+	called := false
+	if err := b.Do(func() error { called = true; return nil }); err != ErrOpen || called {
+		t.Errorf("expect ErrOpen without calling, get %v, %v", err, called)
+	}
+
+	c.now = c.now.Add(time.Second)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("expect half-open after the timeout, get %v", b.State())
+	}
+	if err := b.Do(succeed); err != nil || b.State() != StateClosed {
+		t.Errorf("expect closed after a probe succeeded, get %v, %v", err, b.State())
+	}
+}
+
+func TestProbes(t *testing.T) {
+	b, c := newTestBreaker(ConsecutiveFailures(1), OpenTimeout(time.Second), Probes(2))
+	b.Do(fail)
+	c.now = c.now.Add(time.Second)
+
+	done1, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done2, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Allow(); err != ErrTooManyProbes {
+		t.Errorf("expect ErrTooManyProbes, get %v", err)
+	}
+	done1(nil)
+	done1(errFailed)
+	if b.State() != StateHalfOpen {
+		t.Errorf("expect half-open until every probe succeeded, get %v", b.State())
+	}
+	if _, err := b.Allow(); err != ErrTooManyProbes {
+		t.Errorf("expect ErrTooManyProbes once the probes were let through, get %v", err)
+	}
+	done2(errFailed)
+	if b.State() != StateOpen {
+		t.Errorf("expect open after a probe failed, get %v", b.State())
+	}
+}
+
+func TestStaleResults(t *testing.T) {
+	b, c := newTestBreaker(ConsecutiveFailures(1), OpenTimeout(time.Second))
+	done, _ := b.Allow()
+	b.Do(fail)
+	c.now = c.now.Add(time.Second)
+	b.State()
+	// the request let through while closed does not count as a probe
+	done(nil)
+	if b.State() != StateHalfOpen {
+		t.Errorf("expect half-open, get %v", b.State())
+	}
+}
+
+func TestFailureRate(t *testing.T) {
+	b, c := newTestBreaker(ConsecutiveFailures(0), FailureRate(0.5, 4, 10*time.Second))
+	b.Do(fail)
+	b.Do(fail)
+	b.Do(succeed)
+	if b.State() != StateClosed {
+		t.Fatalf("expect closed below the minimum requests, get %v", b.State())
+	}
+	// the first failures are out of the window
+	c.now = c.now.Add(10 * time.Second)
+	b.Do(succeed)
+	b.Do(fail)
+	b.Do(succeed)
+	if b.State() != StateClosed {
+		t.Fatalf("expect closed with the old requests dropped, get %v", b.State())
+	}
+	c.now = c.now.Add(time.Second)
+	b.Do(fail)
+	if b.State() != StateOpen {
+		t.Errorf("expect open at 50%% failures, get %v", b.State())
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	b, _ := newTestBreaker(ConsecutiveFailures(1), IsFailure(func(err error) bool {
+		return err != nil && !errors.Is(err, context.Canceled)
+	}))
+	b.Do(func() error { return context.Canceled })
+	if b.State() != StateClosed {
+		t.Errorf("expect closed, get %v", b.State())
+	}
+}
+
+func TestPanic(t *testing.T) {
+	b, _ := newTestBreaker(ConsecutiveFailures(1))
+	func() {
+		defer func() { recover() }()
+		b.Do(func() error { panic("boom") })
+	}()
+	if b.State() != StateOpen {
+		t.Errorf("expect open after a panic, get %v", b.State())
+	}
+}
+
+func TestStateChange(t *testing.T) {
+	type event struct {
+		from, to State
+	}
+	var changes, published []event
+	eventBus := bus.ProvideBus()
+	eventBus.AddEventListener(func(ctx context.Context, change *StateChange) error {
+		published = append(published, event{change.From, change.To})
+		return nil
+	})
+	b, c := newTestBreaker(ConsecutiveFailures(1), OpenTimeout(time.Second), Publish(eventBus),
+		OnStateChange(func(change StateChange) {
+			if change.Name != "test" {
+				t.Errorf("expect the name of the breaker, get %q", change.Name)
+			}
+			changes = append(changes, event{change.From, change.To})
+		}))
+	b.Do(fail)
+	c.now = c.now.Add(time.Second)
+	b.Do(succeed)
+	b.Do(fail)
+	b.Reset()
+
+	expect := []event{{StateClosed, StateOpen}, {StateOpen, StateHalfOpen}, {StateHalfOpen, StateClosed}, {StateClosed, StateOpen}, {StateOpen, StateClosed}}
+	if len(changes) != len(expect) || len(published) != len(expect) {
+		t.Fatalf("expect %v, get %v and published %v", expect, changes, published)
+	}
+	for i := range expect {
+		if changes[i] != expect[i] || published[i] != expect[i] {
+			t.Errorf("expect %v, get %v and published %v", expect[i], changes[i], published[i])
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	b, _ := newTestBreaker(ConsecutiveFailures(1))
+	var flushed, rejected [][]int
+	flush := Batch(b, func(batch []int) error {
+		flushed = append(flushed, batch)
+		return errFailed
+	}, func(batch []int, err error) {
+		rejected = append(rejected, batch)
+	})
+	flush([]int{1, 2})
+	flush([]int{3})
+	if len(flushed) != 1 || len(rejected) != 1 || rejected[0][0] != 3 {
+		t.Errorf("expect the second batch rejected, get %v flushed and %v rejected", flushed, rejected)
+	}
+}
+
+func TestCall(t *testing.T) {
+	b := New("test")
+	v, err := Call(b, func() (int, error) { return 42, nil })
+	if v != 42 || err != nil {
+		t.Errorf("expect 42, get %d, %v", v, err)
+	}
+}
@@ -0,0 +1,57 @@
+package breaker
+
+import "time"
+
+// windowBuckets is the number of buckets a failure rate window is split into
+const windowBuckets = 10
+
+type bucket struct {
+	requests, failures int
+}
+
+// window counts the requests and failures of the last window, dropping a bucket at a time
+type window struct {
+	// span is the duration covered by a bucket
+	span    time.Duration
+	buckets [windowBuckets]bucket
+	// current is the index of the bucket of the current period
+	current int
+	period  int64
+}
+
+func newWindow(size time.Duration) *window {
+	return &window{span: max(size/windowBuckets, 1)}
+}
+
+// add counts a request done at now, it returns the totals of the window
+func (w *window) add(now time.Time, failed bool) (requests, failures int) {
+	w.advance(now)
+	w.buckets[w.current].requests++
+	if failed {
+		w.buckets[w.current].failures++
+	}
+	for _, b := range w.buckets {
+		requests += b.requests
+		failures += b.failures
+	}
+	return requests, failures
+}
+
+// advance clears the buckets of the periods elapsed since the last request
+func (w *window) advance(now time.Time) {
+	period := now.UnixNano() / int64(w.span)
+	elapsed := period - w.period
+	if elapsed >= windowBuckets || elapsed < 0 {
+		w.buckets = [windowBuckets]bucket{}
+	} else {
+		for ; elapsed > 0; elapsed-- {
+			w.current = (w.current + 1) % windowBuckets
+			w.buckets[w.current] = bucket{}
+		}
+	}
+	w.period = period
+}
+
+func (w *window) reset() {
+	w.buckets = [windowBuckets]bucket{}
+}
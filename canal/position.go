@@ -0,0 +1,118 @@
+package canal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/redis/go-redis/v9"
+)
+
+// PositionStore persists the binlog position a Runner resumes from
+type PositionStore interface {
+	// Load returns the saved position, ok is false when nothing was saved yet
+	Load(ctx context.Context) (pos mysql.Position, ok bool, err error)
+	Save(ctx context.Context, pos mysql.Position) error
+}
+
+type storedPosition struct {
+	Name string `json:"name"`
+	Pos  uint32 `json:"pos"`
+}
+
+func encodePosition(pos mysql.Position) ([]byte, error) {
+	return json.Marshal(storedPosition{Name: pos.Name, Pos: pos.Pos})
+}
+
+func decodePosition(data []byte) (mysql.Position, error) {
+	var p storedPosition
+	if err := json.Unmarshal(data, &p); err != nil {
+		return mysql.Position{}, err
+	}
+	return mysql.Position{Name: p.Name, Pos: p.Pos}, nil
+}
+
+// FilePositionStore saves the position as JSON in a local file
+type FilePositionStore struct {
+	Path string
+}
+
+func NewFilePositionStore(path string) *FilePositionStore {
+	return &FilePositionStore{Path: path}
+}
+
+func (s *FilePositionStore) Load(ctx context.Context) (mysql.Position, bool, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return mysql.Position{}, false, nil
+	}
+	if err != nil {
+		return mysql.Position{}, false, err
+	}
+	pos, err := decodePosition(data)
+	if err != nil {
+		return mysql.Position{}, false, err
+	}
+	return pos, true, nil
+}
+
+// Save writes a temporary file renamed over the previous one, a crash never leaves a truncated position
+func (s *FilePositionStore) Save(ctx context.Context, pos mysql.Position) error {
+	data, err := encodePosition(pos)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.Path)
+}
+
+// RedisPositionStore saves the position as JSON under a Redis key
+type RedisPositionStore struct {
+	db  redis.UniversalClient
+	Key string
+}
+
+func NewRedisPositionStore(db redis.UniversalClient, key string) *RedisPositionStore {
+	return &RedisPositionStore{db: db, Key: key}
+}
+
+func (s *RedisPositionStore) Load(ctx context.Context) (mysql.Position, bool, error) {
+	data, err := s.db.Get(ctx, s.Key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return mysql.Position{}, false, nil
+	}
+	if err != nil {
+		return mysql.Position{}, false, err
+	}
+	pos, err := decodePosition(data)
+	if err != nil {
+		return mysql.Position{}, false, err
+	}
+	return pos, true, nil
+}
+
+func (s *RedisPositionStore) Save(ctx context.Context, pos mysql.Position) error {
+	data, err := encodePosition(pos)
+	if err != nil {
+		return err
+	}
+	return s.db.Set(ctx, s.Key, data, 0).Err()
+}
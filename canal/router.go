@@ -0,0 +1,336 @@
+package canal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/replication"
+
+	"github.com/dreamsxin/go-utils/bus"
+	"github.com/dreamsxin/go-utils/pool/worker"
+)
+
+// Any matches every schema or table in Router registrations
+const Any = "*"
+
+// RowEvent is a decoded row dispatched by a Router.
+// For updates Before holds the row before the update and Changed the mapped columns that changed.
+type RowEvent[T any] struct {
+	Schema  string
+	Table   string
+	Action  string
+	Row     T
+	Before  T
+	Changed []string
+	Header  *replication.EventHeader
+}
+
+// Flusher is implemented by handlers delivering events asynchronously.
+// Flush waits until every event handled before the call was delivered and returns the delivery errors.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// RouterOption represents an option that can be passed when instantiating a router to customize it
+type RouterOption func(*Router)
+
+// RouterPool runs handlers on the given worker pool instead of the canal goroutine.
+// Events are then handled concurrently: OnRow returns before they are handled, so a Runner
+// no longer acknowledges them by the handler returning, and their errors go to the error handler
+// and to the next Flush. With a Runner, the position is only checkpointed after Flush waited for them.
+// OnRow fails with worker.ErrSubmitOnStoppedPool once the pool is stopped.
+func RouterPool(pool *worker.WorkerPool) RouterOption {
+	return func(r *Router) {
+		r.pool = pool
+	}
+}
+
+// RouterErrorHandler allows to change the function invoked when a handler running on the pool fails.
+// The error is also returned by the next Flush. By default errors are written to the standard logger.
+func RouterErrorHandler(handler func(error)) RouterOption {
+	return func(r *Router) {
+		r.errorHandler = handler
+	}
+}
+
+// RouterContext sets the context passed to handlers, context.Background by default
+func RouterContext(ctx context.Context) RouterOption {
+	return func(r *Router) {
+		r.ctx = ctx
+	}
+}
+
+type route struct {
+	schema  string
+	table   string
+	action  string
+	handler func(ctx context.Context, e *canal.RowsEvent) error
+}
+
+func (rt *route) match(e *canal.RowsEvent) bool {
+	return (rt.schema == Any || rt.schema == e.Table.Schema) &&
+		(rt.table == Any || rt.table == e.Table.Name) &&
+		(rt.action == "" || rt.action == e.Action)
+}
+
+// Router is a canal.EventHandler dispatching rows events to the handlers registered
+// for their schema, table and action, see OnRow, OnInsert, OnUpdate, OnDelete and PublishRows.
+type Router struct {
+	canal.DummyEventHandler
+
+	ctx          context.Context
+	pool         *worker.WorkerPool
+	errorHandler func(error)
+
+	mutex    sync.RWMutex
+	routes   []*route
+	flushers []Flusher
+
+	taskMutex sync.Mutex
+	taskCond  *sync.Cond
+	pending   int
+	err       error
+}
+
+// NewRouter creates a router, register it with canal.SetEventHandler
+func NewRouter(options ...RouterOption) *Router {
+	r := &Router{
+		ctx: context.Background(),
+		errorHandler: func(err error) {
+			log.Printf("canal: router handler failed: %v", err)
+		},
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+	r.taskCond = sync.NewCond(&r.taskMutex)
+	return r
+}
+
+// Handle registers a handler for the raw rows events of a table, an empty action matches every action
+func (r *Router) Handle(schema, table, action string, handler func(ctx context.Context, e *canal.RowsEvent) error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.routes = append(r.routes, &route{schema: schema, table: table, action: action, handler: handler})
+}
+
+func (r *Router) addFlusher(f Flusher) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, registered := range r.flushers {
+		if registered == f {
+			return
+		}
+	}
+	r.flushers = append(r.flushers, f)
+}
+
+// Flush waits for the handlers running on the pool then flushes the sinks registered with SinkRows, see Flusher
+func (r *Router) Flush(ctx context.Context) error {
+	var errs []error
+	if err := r.wait(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	r.mutex.RLock()
+	flushers := append([]Flusher(nil), r.flushers...)
+	r.mutex.RUnlock()
+
+	for _, f := range flushers {
+		if err := f.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnRow dispatches e to every matching handler in registration order
+func (r *Router) OnRow(e *canal.RowsEvent) error {
+	r.mutex.RLock()
+	routes := make([]*route, 0, len(r.routes))
+	for _, rt := range r.routes {
+		if rt.match(e) {
+			routes = append(routes, rt)
+		}
+	}
+	r.mutex.RUnlock()
+
+	for _, rt := range routes {
+		if r.pool == nil {
+			if err := rt.handler(r.ctx, e); err != nil {
+				return err
+			}
+			continue
+		}
+		handler := rt.handler
+		if err := r.submit(func() {
+			var err error
+			defer func() {
+				if p := recover(); p != nil {
+					err = fmt.Errorf("canal: router handler panic: %v", p)
+				}
+				r.done(err)
+				if err != nil && r.errorHandler != nil {
+					r.errorHandler(err)
+				}
+			}()
+			err = handler(r.ctx, e)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// submit hands task to the pool, it returns an error instead of panicking once the pool is stopped
+func (r *Router) submit(task func()) (err error) {
+	if r.pool.Stopped() {
+		return worker.ErrSubmitOnStoppedPool
+	}
+	r.taskMutex.Lock()
+	r.pending++
+	r.taskMutex.Unlock()
+
+	defer func() {
+		if p := recover(); p != nil {
+			if p != worker.ErrSubmitOnStoppedPool {
+				panic(p)
+			}
+			// the pool may still be stopped after the check
+			r.done(nil)
+			err = worker.ErrSubmitOnStoppedPool
+		}
+	}()
+	r.pool.Submit(task)
+	return nil
+}
+
+// done records the end of a task submitted to the pool
+func (r *Router) done(err error) {
+	r.taskMutex.Lock()
+	defer r.taskMutex.Unlock()
+	r.pending--
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	r.taskCond.Broadcast()
+}
+
+// wait waits for the tasks submitted to the pool and returns the first error since the last call
+func (r *Router) wait(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		r.taskMutex.Lock()
+		r.taskCond.Broadcast()
+		r.taskMutex.Unlock()
+	})
+	defer stop()
+
+	r.taskMutex.Lock()
+	defer r.taskMutex.Unlock()
+	for r.pending > 0 && ctx.Err() == nil {
+		r.taskCond.Wait()
+	}
+	if r.pending > 0 {
+		return ctx.Err()
+	}
+	err := r.err
+	r.err = nil
+	return err
+}
+
+// OnTableChanged drops the cached column indexes of the table
+func (r *Router) OnTableChanged(header *replication.EventHeader, schema string, table string) error {
+	InvalidateColumnCache(schema, table)
+	return nil
+}
+
+func (r *Router) String() string {
+	return "Router"
+}
+
+// OnRow registers a handler receiving every decoded row of a table
+func OnRow[T any](r *Router, schema, table string, handler func(ctx context.Context, ev *RowEvent[T]) error) {
+	handle(r, schema, table, "", handler)
+}
+
+// OnInsert registers a handler receiving the inserted rows of a table
+func OnInsert[T any](r *Router, schema, table string, handler func(ctx context.Context, ev *RowEvent[T]) error) {
+	handle(r, schema, table, canal.InsertAction, handler)
+}
+
+// OnUpdate registers a handler receiving the updated rows of a table
+func OnUpdate[T any](r *Router, schema, table string, handler func(ctx context.Context, ev *RowEvent[T]) error) {
+	handle(r, schema, table, canal.UpdateAction, handler)
+}
+
+// OnDelete registers a handler receiving the deleted rows of a table
+func OnDelete[T any](r *Router, schema, table string, handler func(ctx context.Context, ev *RowEvent[T]) error) {
+	handle(r, schema, table, canal.DeleteAction, handler)
+}
+
+// PublishRows publishes every decoded row of a table on b as a *RowEvent[T]
+func PublishRows[T any](r *Router, schema, table string, b bus.Bus) {
+	handle(r, schema, table, "", func(ctx context.Context, ev *RowEvent[T]) error {
+		return b.Publish(ctx, ev)
+	})
+}
+
+func handle[T any](r *Router, schema, table, action string, handler func(ctx context.Context, ev *RowEvent[T]) error) {
+	r.Handle(schema, table, action, func(ctx context.Context, e *canal.RowsEvent) error {
+		events, err := DecodeRowEvents[T](e)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			if err := handler(ctx, ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DecodeRowEvents decodes every row of e, pairing the rows of update events
+func DecodeRowEvents[T any](e *canal.RowsEvent) ([]*RowEvent[T], error) {
+	if e.Action != canal.UpdateAction {
+		rows, err := UnmarshalAll[T](e)
+		if err != nil {
+			return nil, err
+		}
+		events := make([]*RowEvent[T], len(rows))
+		for i, row := range rows {
+			events[i] = newRowEvent(e, row)
+		}
+		return events, nil
+	}
+
+	if len(e.Rows)%2 != 0 {
+		return nil, fmt.Errorf("%w: update event with %d rows", ErrRowIndex, len(e.Rows))
+	}
+	events := make([]*RowEvent[T], 0, len(e.Rows)/2)
+	for i := 0; i < len(e.Rows)/2; i++ {
+		before, after, changed, err := UnmarshalUpdate[T](e, i)
+		if err != nil {
+			return nil, err
+		}
+		ev := newRowEvent(e, after)
+		ev.Before = before
+		ev.Changed = changed
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func newRowEvent[T any](e *canal.RowsEvent, row T) *RowEvent[T] {
+	return &RowEvent[T]{
+		Schema: e.Table.Schema,
+		Table:  e.Table.Name,
+		Action: e.Action,
+		Row:    row,
+		Header: e.Header,
+	}
+}
@@ -0,0 +1,108 @@
+package canal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+
+	"github.com/dreamsxin/go-utils/bus"
+	"github.com/dreamsxin/go-utils/pool/worker"
+)
+
+type routedUser struct {
+	ID   int
+	Name string
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter()
+
+	var inserted, updated []string
+	OnInsert(r, "test", "user", func(ctx context.Context, ev *RowEvent[routedUser]) error {
+		inserted = append(inserted, ev.Row.Name)
+		return nil
+	})
+	OnUpdate(r, Any, "user", func(ctx context.Context, ev *RowEvent[routedUser]) error {
+		updated = append(updated, ev.Before.Name+">"+ev.Row.Name)
+		return nil
+	})
+	OnDelete(r, "other", Any, func(ctx context.Context, ev *RowEvent[routedUser]) error {
+		t.Error("unexpected delete")
+		return nil
+	})
+
+	var h canal.EventHandler = r
+	if err := h.OnRow(newTestEvent(canal.InsertAction, testColumns, []interface{}{int64(1), "a", nil, nil})); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.OnRow(newTestEvent(canal.UpdateAction, testColumns,
+		[]interface{}{int64(1), "a", nil, nil},
+		[]interface{}{int64(1), "b", nil, nil})); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.OnRow(newTestEvent(canal.DeleteAction, testColumns, []interface{}{int64(1), "b", nil, nil})); err != nil {
+		t.Fatal(err)
+	}
+	if len(inserted) != 1 || inserted[0] != "a" || len(updated) != 1 || updated[0] != "a>b" {
+		t.Errorf("unexpected dispatch %v %v", inserted, updated)
+	}
+
+	failure := errors.New("failure")
+	r.Handle(Any, Any, "", func(ctx context.Context, e *canal.RowsEvent) error {
+		return failure
+	})
+	if err := h.OnRow(newTestEvent(canal.DeleteAction, testColumns)); !errors.Is(err, failure) {
+		t.Errorf("expected the handler error, got %v", err)
+	}
+}
+
+func TestRouterPoolAndBus(t *testing.T) {
+	pool := worker.New(2, 10)
+	var (
+		mutex sync.Mutex
+		errs  []error
+	)
+	r := NewRouter(RouterPool(pool), RouterErrorHandler(func(err error) {
+		mutex.Lock()
+		errs = append(errs, err)
+		mutex.Unlock()
+	}))
+
+	b := bus.ProvideBus()
+	var names []string
+	b.AddEventListener(func(ctx context.Context, ev *RowEvent[routedUser]) error {
+		mutex.Lock()
+		names = append(names, ev.Row.Name)
+		mutex.Unlock()
+		return nil
+	})
+	PublishRows[routedUser](r, "test", "user", b)
+	OnRow(r, "test", "user", func(ctx context.Context, ev *RowEvent[routedUser]) error {
+		return errors.New("failure")
+	})
+
+	event := newTestEvent(canal.InsertAction, testColumns, []interface{}{int64(1), "a", nil, nil})
+	if err := r.OnRow(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Flush(context.Background()); err == nil || err.Error() != "failure" {
+		t.Errorf("expected the handler error from Flush, got %v", err)
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Errorf("expected the error to be reported once, got %v", err)
+	}
+	pool.StopAndWait()
+
+	mutex.Lock()
+	if len(names) != 1 || names[0] != "a" || len(errs) != 1 {
+		t.Errorf("unexpected dispatch %v %v", names, errs)
+	}
+	mutex.Unlock()
+
+	if err := r.OnRow(event); !errors.Is(err, worker.ErrSubmitOnStoppedPool) {
+		t.Errorf("expected ErrSubmitOnStoppedPool, got %v", err)
+	}
+}
@@ -1,19 +1,64 @@
 package canal
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-mysql-org/go-mysql/canal"
 	"github.com/go-mysql-org/go-mysql/schema"
 	jsoniter "github.com/json-iterator/go"
 	gormschema "gorm.io/gorm/schema"
+
+	"github.com/dreamsxin/go-utils/types"
 )
 
 var ns gormschema.NamingStrategy
 
+var timeType = reflect.TypeOf(time.Time{})
+
+// DefaultTagName is the struct tag read after the sql and gorm tags, e.g. `canal:"column:user_id;json"`,
+// so models not managed by GORM can be decoded too. A base64 setting stores binary columns in string fields as base64.
+var DefaultTagName = "canal"
+
+// ColumnNamer maps a field without a column setting to its column, snake_case as GORM does (UserID to user_id).
+// Set it to nil to only decode the fields with a column setting.
+var ColumnNamer = func(fieldName string) string {
+	return ns.ColumnName("", fieldName)
+}
+
+// JSON decodes JSON columns, replace it to change the jsoniter configuration
+var JSON = jsoniter.ConfigDefault
+
+// Layouts of the temporal columns, go-mysql appends up to 6 fractional digits
+const (
+	datetimeLayout = "2006-01-02 15:04:05.999999"
+	dateLayout     = "2006-01-02"
+	timeLayout     = "15:04:05.999999"
+)
+
+var (
+	// ErrColumnNotFound is returned when the table of the event has no column of the given name
+	ErrColumnNotFound = errors.New("canal: column not found")
+	// ErrColumnType is returned when a column can't be decoded into the requested type
+	ErrColumnType = errors.New("canal: unexpected column type")
+	// ErrRowIndex is returned when the row index is out of the rows of the event
+	ErrRowIndex = errors.New("canal: row index out of range")
+	// ErrInvalidTarget is returned when rows are decoded into anything but a pointer to a struct
+	ErrInvalidTarget = errors.New("canal: target must be a non-nil pointer to a struct")
+	// ErrNotUpdate is returned when update rows are requested from another kind of event
+	ErrNotUpdate = errors.New("canal: not an update event")
+)
+
 func init() {
 	ns = gormschema.NamingStrategy{
 		TablePrefix:   "public.",
@@ -21,105 +66,672 @@ func init() {
 	}
 }
 
+// Unmarshal decodes the n-th row of e into the struct pointed to by element.
+// It stops at the first column that is missing or can't be decoded.
 func Unmarshal(element interface{}, e *canal.RowsEvent, n int) error {
-	var columnName string
-	var ok bool
+	if n < 0 || n >= len(e.Rows) {
+		return fmt.Errorf("%w: %d of %d", ErrRowIndex, n, len(e.Rows))
+	}
 	v := reflect.ValueOf(element)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w, got %T", ErrInvalidTarget, element)
+	}
 	s := reflect.Indirect(v)
 	t := s.Type()
 	num := t.NumField()
 	for k := 0; k < num; k++ {
-		parsedTag := parseTagSetting(t.Field(k).Tag)
-		name := s.Field(k).Type().Name()
-
-		if columnName, ok = parsedTag["COLUMN"]; !ok {
-			columnName = ns.ColumnName("", t.Field(k).Name)
-		}
-
-		switch name {
-		case "bool":
-			s.Field(k).SetBool(HelperBool(e, n, columnName))
-		case "int":
-			s.Field(k).SetInt(HelperInt(e, n, columnName))
-		case "string":
-			s.Field(k).SetString(HelperString(e, n, columnName))
-		case "Time":
-			timeVal := HelperDateTime(e, n, columnName)
-			s.Field(k).Set(reflect.ValueOf(timeVal))
-		case "float64":
-			s.Field(k).SetFloat(HelperFloat(e, n, columnName))
-		default:
-			if _, ok := parsedTag["FROMJSON"]; ok {
+		columnName, parsedTag, ok := fieldColumn(t.Field(k))
+		if !ok {
+			continue
+		}
+
+		if err := setField(s.Field(k), parsedTag, e, n, columnName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-				newObject := reflect.New(s.Field(k).Type()).Interface()
-				json := HelperString(e, n, columnName)
+// fieldColumn returns the column of a struct field and its tag settings, ok is false for ignored fields
+func fieldColumn(field reflect.StructField) (columnName string, parsedTag map[string]string, ok bool) {
+	parsedTag = parseTagSetting(field.Tag)
+	if _, ignored := parsedTag["-"]; ignored || !field.IsExported() {
+		return "", nil, false
+	}
+	if columnName, ok = parsedTag["COLUMN"]; !ok {
+		if ColumnNamer == nil {
+			return "", nil, false
+		}
+		columnName = ColumnNamer(field.Name)
+	}
+	return columnName, parsedTag, true
+}
 
-				jsoniter.Unmarshal([]byte(json), &newObject)
+// setField decodes the column into an addressable field
+func setField(field reflect.Value, parsedTag map[string]string, e *canal.RowsEvent, n int, columnName string) error {
+	fieldType := field.Type()
+
+	// time.Time and types derived from it such as types.LocalTime or types.LocalDate
+	if fieldType.Kind() == reflect.Struct && fieldType.ConvertibleTo(timeType) {
+		timeVal, err := HelperDateTimeE(e, n, columnName)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(timeVal).Convert(fieldType))
+		return nil
+	}
+
+	// types.Decimal, sql.NullXxx and other scanners get the normalized column value
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		value, err := HelperValueE(e, n, columnName)
+		if err != nil {
+			return err
+		}
+		if err := scanner.Scan(value); err != nil {
+			return fmt.Errorf("canal: column %s: %w", columnName, err)
+		}
+		return nil
+	}
 
-				s.Field(k).Set(reflect.ValueOf(newObject).Elem().Convert(s.Field(k).Type()))
+	// NULL maps to nil pointers
+	if fieldType.Kind() == reflect.Pointer {
+		return setPointerField(field, parsedTag, e, n, columnName)
+	}
+
+	// BLOB, BINARY and SET columns, JSON columns still go to unmarshalJSONField
+	if _, ok := parsedTag["FROMJSON"]; !ok && fieldType.Kind() == reflect.Slice {
+		columnType := 0
+		if columnId, err := GetColumnIdByNameE(e, columnName); err == nil {
+			columnType = e.Table.Columns[columnId].Type
+		}
+		switch {
+		case fieldType.Elem().Kind() == reflect.Uint8 && columnType != schema.TYPE_JSON:
+			val, err := HelperBytesE(e, n, columnName)
+			if err != nil {
+				return err
+			}
+			field.SetBytes(val)
+			return nil
+		case fieldType.Elem().Kind() == reflect.String && columnType == schema.TYPE_SET:
+			val, err := HelperSetE(e, n, columnName)
+			if err != nil {
+				return err
 			}
+			field.Set(reflect.ValueOf(val).Convert(fieldType))
+			return nil
+		}
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		val, err := HelperBoolE(e, n, columnName)
+		if err != nil {
+			return err
+		}
+		field.SetBool(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := HelperIntE(e, n, columnName)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(val) {
+			return fmt.Errorf("%w: %s value %d overflows %s", ErrColumnType, columnName, val, fieldType)
+		}
+		field.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := HelperUintE(e, n, columnName)
+		if err != nil {
+			return err
+		}
+		if field.OverflowUint(val) {
+			return fmt.Errorf("%w: %s value %d overflows %s", ErrColumnType, columnName, val, fieldType)
+		}
+		field.SetUint(val)
+	case reflect.String:
+		// base64 keeps binary values printable
+		if _, ok := parsedTag["BASE64"]; ok {
+			val, err := HelperBytesE(e, n, columnName)
+			if err != nil {
+				return err
+			}
+			field.SetString(base64.StdEncoding.EncodeToString(val))
+			return nil
+		}
+		val, err := HelperStringE(e, n, columnName)
+		if err != nil {
+			return err
+		}
+		field.SetString(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := HelperFloatE(e, n, columnName)
+		if err != nil {
+			return err
 		}
+		field.SetFloat(val)
+	default:
+		return unmarshalJSONField(field, parsedTag, e, n, columnName)
 	}
 	return nil
 }
-func HelperDateTime(e *canal.RowsEvent, n int, columnName string) time.Time {
 
-	columnId := GetColumnIdByName(e, columnName)
+// setPointerField sets nil for NULL and otherwise decodes into a new value
+func setPointerField(field reflect.Value, parsedTag map[string]string, e *canal.RowsEvent, n int, columnName string) error {
+	elemType := field.Type().Elem()
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		// pointers to associations have no column
+		if elemType.Kind() == reflect.Struct && !elemType.ConvertibleTo(timeType) {
+			return nil
+		}
+		return err
+	}
+	if _, ok := parsedTag["FROMJSON"]; ok || e.Table.Columns[columnId].Type == schema.TYPE_JSON {
+		return unmarshalJSONField(field, parsedTag, e, n, columnName)
+	}
+
 	if e.Rows[n][columnId] == nil {
-		return time.Time{}
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	elem := reflect.New(elemType)
+	if err := setField(elem.Elem(), parsedTag, e, n, columnName); err != nil {
+		return err
 	}
-	if e.Table.Columns[columnId].Type != schema.TYPE_TIMESTAMP && e.Table.Columns[columnId].Type != schema.TYPE_DATETIME {
-		panic(fmt.Sprintf("Not dateTime type: %s - %d", columnName, e.Table.Columns[columnId].Type))
+	field.Set(elem)
+	return nil
+}
+
+// unmarshalJSONField decodes JSON columns, or any column tagged FROMJSON, into struct, slice, map or pointer fields
+func unmarshalJSONField(field reflect.Value, parsedTag map[string]string, e *canal.RowsEvent, n int, columnName string) error {
+	if _, ok := parsedTag["FROMJSON"]; !ok {
+		switch field.Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface:
+		default:
+			return nil
+		}
+		// associations and other fields without a column are left alone
+		columnId, err := GetColumnIdByNameE(e, columnName)
+		if err != nil || e.Table.Columns[columnId].Type != schema.TYPE_JSON {
+			return nil
+		}
 	}
-	t, _ := time.ParseInLocation("2006-01-02 15:04:05", e.Rows[n][columnId].(string), time.Local)
 
+	data, err := HelperStringE(e, n, columnName)
+	if err != nil {
+		return err
+	}
+	if data == "" || data == "null" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	ptr := reflect.New(field.Type())
+	if err := JSON.Unmarshal([]byte(data), ptr.Interface()); err != nil {
+		return fmt.Errorf("canal: column %s: %w", columnName, err)
+	}
+	field.Set(ptr.Elem())
+	return nil
+}
+
+// UnmarshalRow decodes the n-th row of e into a new T, which must be a struct type
+func UnmarshalRow[T any](e *canal.RowsEvent, n int) (T, error) {
+	var row T
+	err := Unmarshal(&row, e, n)
+	return row, err
+}
+
+// UnmarshalAll decodes every row of e into a T. For update events, whose rows alternate
+// between the row before and after the update, only the rows after the update are returned.
+func UnmarshalAll[T any](e *canal.RowsEvent) ([]T, error) {
+	start, stride := 0, 1
+	if e.Action == canal.UpdateAction {
+		if len(e.Rows)%2 != 0 {
+			return nil, fmt.Errorf("%w: update event with %d rows", ErrRowIndex, len(e.Rows))
+		}
+		start, stride = 1, 2
+	}
+
+	rows := make([]T, 0, len(e.Rows)/stride)
+	for n := start; n < len(e.Rows); n += stride {
+		row, err := UnmarshalRow[T](e, n)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// UnmarshalUpdate decodes the i-th update of an update event, made of rows 2i and 2i+1,
+// and reports the columns mapped by T whose value changed
+func UnmarshalUpdate[T any](e *canal.RowsEvent, i int) (before T, after T, changed []string, err error) {
+	if e.Action != canal.UpdateAction {
+		return before, after, nil, fmt.Errorf("%w: got a %s event", ErrNotUpdate, e.Action)
+	}
+	if before, err = UnmarshalRow[T](e, 2*i); err != nil {
+		return
+	}
+	if after, err = UnmarshalRow[T](e, 2*i+1); err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	t := reflect.TypeOf(before)
+	for k := 0; k < t.NumField(); k++ {
+		columnName, _, ok := fieldColumn(t.Field(k))
+		if !ok || seen[columnName] {
+			continue
+		}
+		seen[columnName] = true
+		columnId, err := GetColumnIdByNameE(e, columnName)
+		if err != nil {
+			// associations
+			continue
+		}
+		if !reflect.DeepEqual(e.Rows[2*i][columnId], e.Rows[2*i+1][columnId]) {
+			changed = append(changed, columnName)
+		}
+	}
+	return before, after, changed, nil
+}
+
+// HelperDateTime is like HelperDateTimeE but panics on error
+func HelperDateTime(e *canal.RowsEvent, n int, columnName string) time.Time {
+	t, err := HelperDateTimeE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
 	return t
 }
 
+// HelperDateTimeE decodes a TIMESTAMP, DATETIME, DATE or TIME column in types.Location(),
+// TIME values are returned on the zero date. NULL and zero dates become the zero time.
+func HelperDateTimeE(e *canal.RowsEvent, n int, columnName string) (time.Time, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var layout string
+	switch e.Table.Columns[columnId].Type {
+	case schema.TYPE_TIMESTAMP, schema.TYPE_DATETIME:
+		layout = datetimeLayout
+	case schema.TYPE_DATE:
+		layout = dateLayout
+	case schema.TYPE_TIME:
+		layout = timeLayout
+	default:
+		return time.Time{}, fmt.Errorf("%w: %s is not a datetime - %d", ErrColumnType, columnName, e.Table.Columns[columnId].Type)
+	}
+
+	switch value := e.Rows[n][columnId].(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		// go-mysql with ParseTime enabled
+		return value, nil
+	case string:
+		if strings.HasPrefix(value, "0000-00-00") {
+			return time.Time{}, nil
+		}
+		t, err := time.ParseInLocation(layout, value, types.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("canal: column %s: %w", columnName, err)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %s holds %T", ErrColumnType, columnName, e.Rows[n][columnId])
+}
+
+// formatTime formats t with the layout of the temporal column type
+func formatTime(t time.Time, columnType int) string {
+	switch columnType {
+	case schema.TYPE_DATE:
+		return t.Format(dateLayout)
+	case schema.TYPE_TIME:
+		return t.Format(timeLayout)
+	}
+	return t.Format(datetimeLayout)
+}
+
 func HelperInt(e *canal.RowsEvent, n int, columnName string) int64 {
+	v, err := HelperIntE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
 
-	columnId := GetColumnIdByName(e, columnName)
-	if e.Table.Columns[columnId].Type != schema.TYPE_NUMBER {
-		return 0
+// HelperIntE decodes a numeric, BIT or SET column, other column types become 0.
+// Unsigned values above math.MaxInt64 return an error.
+func HelperIntE(e *canal.RowsEvent, n int, columnName string) (int64, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return 0, err
+	}
+	column := &e.Table.Columns[columnId]
+	if column.Type == schema.TYPE_BIT || column.Type == schema.TYPE_SET {
+		bits, err := HelperBitsE(e, n, columnName)
+		if err != nil {
+			return 0, err
+		}
+		if bits > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %s value %d overflows int64", ErrColumnType, columnName, bits)
+		}
+		return int64(bits), nil
+	}
+	if column.Type != schema.TYPE_NUMBER && column.Type != schema.TYPE_MEDIUM_INT {
+		return 0, nil
 	}
 
-	switch e.Rows[n][columnId].(type) {
-	case int8:
-		return int64(e.Rows[n][columnId].(int8))
-	case int32:
-		return int64(e.Rows[n][columnId].(int32))
-	case int64:
-		return e.Rows[n][columnId].(int64)
-	case int:
-		return int64(e.Rows[n][columnId].(int))
+	i, u, unsigned := integerValue(column, e.Rows[n][columnId])
+	if unsigned {
+		if u > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %s value %d overflows int64", ErrColumnType, columnName, u)
+		}
+		return int64(u), nil
+	}
+	return i, nil
+}
+
+func HelperUint(e *canal.RowsEvent, n int, columnName string) uint64 {
+	v, err := HelperUintE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// HelperUintE decodes a numeric, BIT or SET column, other column types become 0.
+// Negative values return an error.
+func HelperUintE(e *canal.RowsEvent, n int, columnName string) (uint64, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return 0, err
+	}
+	column := &e.Table.Columns[columnId]
+	if column.Type == schema.TYPE_BIT || column.Type == schema.TYPE_SET {
+		return HelperBitsE(e, n, columnName)
+	}
+	if column.Type != schema.TYPE_NUMBER && column.Type != schema.TYPE_MEDIUM_INT {
+		return 0, nil
+	}
+
+	i, u, unsigned := integerValue(column, e.Rows[n][columnId])
+	if unsigned {
+		return u, nil
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("%w: %s value %d is negative", ErrColumnType, columnName, i)
+	}
+	return uint64(i), nil
+}
+
+// integerValue returns the value of an integer column, in u if it is unsigned.
+// Binlog rows hold signed integers, go-mysql converts unsigned columns it knows about
+// and the others are converted here so high-bit values don't become negative.
+func integerValue(column *schema.TableColumn, value interface{}) (i int64, u uint64, unsigned bool) {
+	switch value := value.(type) {
 	case uint8:
-		return int64(e.Rows[n][columnId].(uint8))
+		return 0, uint64(value), true
 	case uint16:
-		return int64(e.Rows[n][columnId].(uint16))
+		return 0, uint64(value), true
 	case uint32:
-		return int64(e.Rows[n][columnId].(uint32))
+		return 0, uint64(value), true
 	case uint64:
-		return int64(e.Rows[n][columnId].(uint64))
+		return 0, value, true
 	case uint:
-		return int64(e.Rows[n][columnId].(uint))
+		return 0, uint64(value), true
+	case int8:
+		if column.IsUnsigned {
+			return 0, uint64(uint8(value)), true
+		}
+		return int64(value), 0, false
+	case int16:
+		if column.IsUnsigned {
+			return 0, uint64(uint16(value)), true
+		}
+		return int64(value), 0, false
+	case int32:
+		if column.IsUnsigned {
+			if value < 0 && column.Type == schema.TYPE_MEDIUM_INT {
+				// mediumint is a 3-byte type
+				return 0, uint64(value) & 0xFFFFFF, true
+			}
+			return 0, uint64(uint32(value)), true
+		}
+		return int64(value), 0, false
+	case int64:
+		if column.IsUnsigned {
+			return 0, uint64(value), true
+		}
+		return value, 0, false
+	case int:
+		if column.IsUnsigned {
+			return 0, uint64(uint(value)), true
+		}
+		return int64(value), 0, false
 	}
-	return 0
+	return 0, 0, false
 }
 
+func HelperBits(e *canal.RowsEvent, n int, columnName string) uint64 {
+	v, err := HelperBitsE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// HelperBitsE decodes a BIT column, or the bitmask of a SET column whose first member is bit 0.
+// NULL becomes 0.
+func HelperBitsE(e *canal.RowsEvent, n int, columnName string) (uint64, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return 0, err
+	}
+	column := &e.Table.Columns[columnId]
+	if column.Type != schema.TYPE_BIT && column.Type != schema.TYPE_SET {
+		return 0, fmt.Errorf("%w: %s is not a bit or set - %d", ErrColumnType, columnName, column.Type)
+	}
+
+	switch value := e.Rows[n][columnId].(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		// BIT(64) values use the sign bit
+		return uint64(value), nil
+	case []byte:
+		// b'...' literals are big-endian
+		if len(value) > 8 {
+			break
+		}
+		var bits uint64
+		for _, b := range value {
+			bits = bits<<8 | uint64(b)
+		}
+		return bits, nil
+	case string:
+		// SET values of mysqldump are comma separated members
+		if column.Type != schema.TYPE_SET {
+			break
+		}
+		var bits uint64
+		for _, member := range splitSet(value) {
+			i := slices.Index(column.SetValues, member)
+			if i < 0 || i >= 64 {
+				return 0, fmt.Errorf("%w: %s holds unknown set member %q", ErrColumnType, columnName, member)
+			}
+			bits |= 1 << i
+		}
+		return bits, nil
+	default:
+		if i, u, unsigned := integerValue(column, value); unsigned {
+			return u, nil
+		} else if i >= 0 {
+			return uint64(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s holds %v", ErrColumnType, columnName, e.Rows[n][columnId])
+}
+
+func HelperSet(e *canal.RowsEvent, n int, columnName string) []string {
+	v, err := HelperSetE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// HelperSetE decodes the members of a SET column in their declaration order, NULL and the empty set become nil
+func HelperSetE(e *canal.RowsEvent, n int, columnName string) ([]string, error) {
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return nil, err
+	}
+	bits, err := HelperBitsE(e, n, columnName)
+	if err != nil {
+		return nil, err
+	}
+	column := &e.Table.Columns[columnId]
+	if column.Type != schema.TYPE_SET {
+		return nil, fmt.Errorf("%w: %s is not a set - %d", ErrColumnType, columnName, column.Type)
+	}
+
+	var members []string
+	for i, member := range column.SetValues {
+		if i < 64 && bits&(1<<i) != 0 {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func splitSet(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// RowToMap returns the n-th row of e keyed by column name. Values are normalized as by HelperValueE,
+// except SET columns which become []string and BIT columns uint64. []byte values are copied.
+func RowToMap(e *canal.RowsEvent, n int) (map[string]any, error) {
+	if n < 0 || n >= len(e.Rows) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrRowIndex, n, len(e.Rows))
+	}
+
+	row := make(map[string]any, len(e.Table.Columns))
+	for _, column := range e.Table.Columns {
+		var (
+			value any
+			err   error
+		)
+		switch column.Type {
+		case schema.TYPE_SET:
+			value, err = HelperSetE(e, n, column.Name)
+		case schema.TYPE_BIT:
+			value, err = HelperBitsE(e, n, column.Name)
+		default:
+			value, err = HelperValueE(e, n, column.Name)
+			if b, ok := value.([]byte); ok {
+				value = append([]byte{}, b...)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		row[column.Name] = value
+	}
+	return row, nil
+}
+
+// HelperFloat is like HelperFloatE but panics on error
 func HelperFloat(e *canal.RowsEvent, n int, columnName string) float64 {
+	v, err := HelperFloatE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
 
-	columnId := GetColumnIdByName(e, columnName)
+// HelperFloatE decodes a FLOAT, DOUBLE or DECIMAL column
+func HelperFloatE(e *canal.RowsEvent, n int, columnName string) (float64, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return 0, err
+	}
 	if e.Table.Columns[columnId].Type != schema.TYPE_FLOAT && e.Table.Columns[columnId].Type != schema.TYPE_DECIMAL {
-		panic(fmt.Sprintf("Not float type: %s - %d", columnName, e.Table.Columns[columnId].Type))
+		return 0, fmt.Errorf("%w: %s is not a float - %d", ErrColumnType, columnName, e.Table.Columns[columnId].Type)
 	}
 
-	switch e.Rows[n][columnId].(type) {
+	switch value := e.Rows[n][columnId].(type) {
 	case float32:
-		return float64(e.Rows[n][columnId].(float32))
+		return float64(value), nil
 	case float64:
-		return float64(e.Rows[n][columnId].(float64))
+		return value, nil
+	case string:
+		// DECIMAL columns are decoded as strings by go-mysql
+		return parseFloat(columnName, value)
+	case fmt.Stringer:
+		// or as decimal.Decimal with UseDecimal enabled
+		return parseFloat(columnName, value.String())
 	}
-	return float64(0)
+	return float64(0), nil
+}
+
+func parseFloat(columnName, s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("canal: column %s: %w", columnName, err)
+	}
+	return f, nil
+}
+
+// HelperValueE returns the value of a column as one of the driver.Value types:
+// nil, int64, float64, bool, []byte, string or time.Time.
+// Enums become their string value, decimals their exact string representation,
+// DATE, DATETIME and TIMESTAMP columns time.Time and zero dates nil.
+func HelperValueE(e *canal.RowsEvent, n int, columnName string) (driver.Value, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Table.Columns[columnId].Type {
+	case schema.TYPE_ENUM:
+		return HelperStringE(e, n, columnName)
+	case schema.TYPE_DATE, schema.TYPE_DATETIME, schema.TYPE_TIMESTAMP:
+		t, err := HelperDateTimeE(e, n, columnName)
+		if err != nil || t.IsZero() {
+			return nil, err
+		}
+		return t, nil
+	}
+
+	switch value := e.Rows[n][columnId].(type) {
+	case int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint:
+		i, u, unsigned := integerValue(&e.Table.Columns[columnId], value)
+		if !unsigned {
+			return i, nil
+		}
+		if u > math.MaxInt64 {
+			// too large for int64, scanners such as types.Suint64 parse the string
+			return strconv.FormatUint(u, 10), nil
+		}
+		return int64(u), nil
+	case nil, float64, bool, []byte, string, time.Time:
+		return value, nil
+	case float32:
+		return float64(value), nil
+	case fmt.Stringer:
+		return value.String(), nil
+	}
+	return nil, fmt.Errorf("%w: %s holds %T", ErrColumnType, columnName, e.Rows[n][columnId])
 }
 
 func HelperBool(e *canal.RowsEvent, n int, columnName string) bool {
@@ -128,46 +740,154 @@ func HelperBool(e *canal.RowsEvent, n int, columnName string) bool {
 	return val == 1
 }
 
+func HelperBoolE(e *canal.RowsEvent, n int, columnName string) (bool, error) {
+
+	val, err := HelperIntE(e, n, columnName)
+	return val == 1, err
+}
+
 func HelperString(e *canal.RowsEvent, n int, columnName string) string {
+	v, err := HelperStringE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
 
-	columnId := GetColumnIdByName(e, columnName)
+// HelperStringE decodes a string or enum column, other column types become ""
+func HelperStringE(e *canal.RowsEvent, n int, columnName string) (string, error) {
+
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return "", err
+	}
 	if e.Table.Columns[columnId].Type == schema.TYPE_ENUM {
 
 		values := e.Table.Columns[columnId].EnumValues
 		if len(values) == 0 {
-			return ""
+			return "", nil
 		}
 		if e.Rows[n][columnId] == nil {
 			//Если в енум лежит нуул ставим пустую строку
-			return ""
+			return "", nil
 		}
 
-		return values[e.Rows[n][columnId].(int64)-1]
+		index, ok := e.Rows[n][columnId].(int64)
+		if !ok || index < 1 || int(index) > len(values) {
+			return "", fmt.Errorf("%w: %s holds enum index %v", ErrColumnType, columnName, e.Rows[n][columnId])
+		}
+		return values[index-1], nil
 	}
 
 	value := e.Rows[n][columnId]
 
 	switch value := value.(type) {
 	case []byte:
-		return string(value)
+		return string(value), nil
 	case string:
-		return value
+		return value, nil
+	case time.Time:
+		return formatTime(value, e.Table.Columns[columnId].Type), nil
+	case fmt.Stringer:
+		// decimal.Decimal with UseDecimal enabled
+		return value.String(), nil
 	}
-	return ""
+	return "", nil
 }
 
+// HelperBytes is like HelperBytesE but panics on error
+func HelperBytes(e *canal.RowsEvent, n int, columnName string) []byte {
+	v, err := HelperBytesE(e, n, columnName)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// HelperBytesE decodes a BLOB, BINARY or string column into a copy of its bytes, NULL becomes nil
+func HelperBytesE(e *canal.RowsEvent, n int, columnName string) ([]byte, error) {
+	columnId, err := GetColumnIdByNameE(e, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := e.Rows[n][columnId].(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return append([]byte{}, value...), nil
+	case string:
+		return []byte(value), nil
+	}
+	return nil, fmt.Errorf("%w: %s is not binary - %T", ErrColumnType, columnName, e.Rows[n][columnId])
+}
+
+// GetColumnIdByName is like GetColumnIdByNameE but panics if the column doesn't exist
 func GetColumnIdByName(e *canal.RowsEvent, name string) int {
+	id, err := GetColumnIdByNameE(e, name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return id
+}
+
+func GetColumnIdByNameE(e *canal.RowsEvent, name string) (int, error) {
+	if id, ok := columnIndex(e.Table)[name]; ok && id < len(e.Table.Columns) && e.Table.Columns[id].Name == name {
+		return id, nil
+	}
 	for id, value := range e.Table.Columns {
 		if value.Name == name {
-			return id
+			// the table changed without InvalidateColumnCache being called
+			columnCache.Delete(newColumnCacheKey(e.Table))
+			return id, nil
 		}
 	}
-	panic(fmt.Sprintf("There is no column %s in table %s.%s", name, e.Table.Schema, e.Table.Name))
+	return -1, fmt.Errorf("%w: there is no column %s in table %s.%s", ErrColumnNotFound, name, e.Table.Schema, e.Table.Name)
 }
 
+type columnCacheKey struct {
+	schema  string
+	table   string
+	columns int
+}
+
+func newColumnCacheKey(table *schema.Table) columnCacheKey {
+	return columnCacheKey{schema: table.Schema, table: table.Name, columns: len(table.Columns)}
+}
+
+// columnCache maps a columnCacheKey to the index of each column name
+var columnCache sync.Map
+
+func columnIndex(table *schema.Table) map[string]int {
+	key := newColumnCacheKey(table)
+	if index, ok := columnCache.Load(key); ok {
+		return index.(map[string]int)
+	}
+	index := make(map[string]int, len(table.Columns))
+	for id, column := range table.Columns {
+		if _, ok := index[column.Name]; !ok {
+			index[column.Name] = id
+		}
+	}
+	columnCache.Store(key, index)
+	return index
+}
+
+// InvalidateColumnCache drops the cached column indexes of a table,
+// call it from EventHandler.OnTableChanged
+func InvalidateColumnCache(schema string, table string) {
+	columnCache.Range(func(key, value any) bool {
+		if k := key.(columnCacheKey); k.schema == schema && k.table == table {
+			columnCache.Delete(key)
+		}
+		return true
+	})
+}
+
+// parseTagSetting merges the sql, gorm and DefaultTagName tags, the latter taking precedence
 func parseTagSetting(tags reflect.StructTag) map[string]string {
 	settings := map[string]string{}
-	for _, str := range []string{tags.Get("sql"), tags.Get("gorm")} {
+	for _, str := range []string{tags.Get("sql"), tags.Get("gorm"), tags.Get(DefaultTagName)} {
 		tags := strings.Split(str, ";")
 		for _, value := range tags {
 			v := strings.Split(value, ":")
@@ -179,5 +899,12 @@ func parseTagSetting(tags reflect.StructTag) map[string]string {
 			}
 		}
 	}
+	// json is accepted as a shorter FROMJSON, as gorm does for its serializer
+	if _, ok := settings["JSON"]; ok {
+		settings["FROMJSON"] = "FROMJSON"
+	}
+	if strings.EqualFold(settings["SERIALIZER"], "json") {
+		settings["FROMJSON"] = "FROMJSON"
+	}
 	return settings
 }
@@ -0,0 +1,536 @@
+package canal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/schema"
+
+	"github.com/dreamsxin/go-utils/types"
+)
+
+func newTestEvent(action string, columns []schema.TableColumn, rows ...[]interface{}) *canal.RowsEvent {
+	return &canal.RowsEvent{
+		Table:  &schema.Table{Schema: "test", Name: "user", Columns: columns},
+		Action: action,
+		Rows:   rows,
+	}
+}
+
+var testColumns = []schema.TableColumn{
+	{Name: "id", Type: schema.TYPE_NUMBER},
+	{Name: "name", Type: schema.TYPE_STRING},
+	{Name: "score", Type: schema.TYPE_FLOAT},
+	{Name: "created_at", Type: schema.TYPE_DATETIME},
+}
+
+func TestUnmarshal(t *testing.T) {
+	type user struct {
+		ID        int
+		Name      string
+		Score     float64
+		CreatedAt time.Time
+	}
+	e := newTestEvent(canal.InsertAction, testColumns, []interface{}{int32(7), "john", 1.5, "2024-05-06 07:08:09"})
+
+	var u user
+	if err := Unmarshal(&u, e, 0); err != nil {
+		t.Fatal(err)
+	}
+	expected := user{7, "john", 1.5, time.Date(2024, 5, 6, 7, 8, 9, 0, types.Location())}
+	if u != expected {
+		t.Errorf("expected %+v, got %+v", expected, u)
+	}
+
+	if err := Unmarshal(&u, e, 1); !errors.Is(err, ErrRowIndex) {
+		t.Errorf("expected ErrRowIndex, got %v", err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	e := newTestEvent(canal.InsertAction, testColumns, []interface{}{int32(7), "john", 1.5, "2024-05-06 07:08:09"})
+
+	var missing struct {
+		Email string
+	}
+	if err := Unmarshal(&missing, e, 0); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+
+	var wrongType struct {
+		Name time.Time
+	}
+	if err := Unmarshal(&wrongType, e, 0); !errors.Is(err, ErrColumnType) {
+		t.Errorf("expected ErrColumnType, got %v", err)
+	}
+}
+
+func TestUnmarshalRow(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	e := newTestEvent(canal.InsertAction, testColumns, []interface{}{int32(7), "john", 1.5, nil})
+
+	u, err := UnmarshalRow[user](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 7 || u.Name != "john" {
+		t.Errorf("unexpected row %+v", u)
+	}
+
+	if _, err := UnmarshalRow[int](e, 0); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("expected ErrInvalidTarget, got %v", err)
+	}
+}
+
+func TestUnmarshalTemporalColumns(t *testing.T) {
+	type localDate time.Time
+	type event struct {
+		Day      localDate
+		At       time.Time
+		Opens    time.Time
+		DayText  string `gorm:"column:day"`
+		Occurred time.Time
+	}
+	columns := []schema.TableColumn{
+		{Name: "day", Type: schema.TYPE_DATE},
+		{Name: "at", Type: schema.TYPE_DATETIME},
+		{Name: "opens", Type: schema.TYPE_TIME},
+		{Name: "occurred", Type: schema.TYPE_TIMESTAMP},
+	}
+	occurred := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{"2024-05-06", "2024-05-06 07:08:09.123456", "09:30:00", occurred})
+
+	ev, err := UnmarshalRow[event](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(ev.Day).Equal(time.Date(2024, 5, 6, 0, 0, 0, 0, types.Location())) {
+		t.Errorf("unexpected day %v", time.Time(ev.Day))
+	}
+	if ev.At.Nanosecond() != 123456000 {
+		t.Errorf("expected fractional seconds, got %v", ev.At)
+	}
+	if ev.Opens.Hour() != 9 || ev.Opens.Minute() != 30 {
+		t.Errorf("unexpected time %v", ev.Opens)
+	}
+	if ev.DayText != "2024-05-06" || !ev.Occurred.Equal(occurred) {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	e.Rows[0][0] = "0000-00-00"
+	if ev, err = UnmarshalRow[event](e, 0); err != nil || !time.Time(ev.Day).IsZero() {
+		t.Errorf("expected a zero date, got %v %v", time.Time(ev.Day), err)
+	}
+}
+
+type testDecimal string
+
+func (d testDecimal) String() string { return string(d) }
+
+func TestUnmarshalDecimalColumns(t *testing.T) {
+	type order struct {
+		Amount      types.Decimal
+		AmountText  string  `gorm:"column:amount"`
+		AmountFloat float64 `gorm:"column:amount"`
+		Fee         types.Decimal
+	}
+	columns := []schema.TableColumn{
+		{Name: "amount", Type: schema.TYPE_DECIMAL},
+		{Name: "fee", Type: schema.TYPE_DECIMAL},
+	}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{"1234.50", testDecimal("0.07")})
+
+	o, err := UnmarshalRow[order](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Amount.String() != "1234.50" || o.AmountText != "1234.50" || o.AmountFloat != 1234.5 {
+		t.Errorf("unexpected amount %+v", o)
+	}
+	if o.Fee.String() != "0.07" {
+		t.Errorf("unexpected fee %s", o.Fee)
+	}
+}
+
+func TestUnmarshalJSONColumns(t *testing.T) {
+	type profile struct {
+		Tags     []string
+		Settings map[string]int
+		Address  *struct {
+			City string `json:"city"`
+		}
+		Raw   string `gorm:"column:tags"`
+		Extra []int  `gorm:"column:extra;fromjson"`
+		Owner struct{ ID int }
+	}
+	columns := []schema.TableColumn{
+		{Name: "tags", Type: schema.TYPE_JSON},
+		{Name: "settings", Type: schema.TYPE_JSON},
+		{Name: "address", Type: schema.TYPE_JSON},
+		{Name: "extra", Type: schema.TYPE_STRING},
+	}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{`["a","b"]`, `{"x":1}`, `{"city":"Paris"}`, `[1,2,3]`})
+
+	p, err := UnmarshalRow[profile](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Tags) != 2 || p.Settings["x"] != 1 || p.Address == nil || p.Address.City != "Paris" {
+		t.Errorf("unexpected profile %+v", p)
+	}
+	if p.Raw != `["a","b"]` || len(p.Extra) != 3 {
+		t.Errorf("unexpected values %q %v", p.Raw, p.Extra)
+	}
+
+	e.Rows[0][1] = `{"x":`
+	if _, err := UnmarshalRow[profile](e, 0); err == nil {
+		t.Error("expected an error for invalid json")
+	}
+}
+
+func TestUnmarshalNullColumns(t *testing.T) {
+	type user struct {
+		ID        *int64
+		Name      *string
+		Score     sql.NullFloat64
+		CreatedAt *time.Time
+		Nickname  sql.NullString `gorm:"column:name"`
+		Updated   sql.NullTime   `gorm:"column:created_at"`
+		Parent    *user
+		ignored   string
+		Skipped   string `gorm:"-"`
+	}
+
+	e := newTestEvent(canal.InsertAction, testColumns, []interface{}{nil, nil, nil, nil})
+	u, err := UnmarshalRow[user](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != nil || u.Name != nil || u.Score.Valid || u.CreatedAt != nil || u.Nickname.Valid || u.Updated.Valid {
+		t.Errorf("expected NULL values, got %+v", u)
+	}
+
+	e.Rows[0] = []interface{}{int64(0), "", 0.0, "2024-05-06 07:08:09"}
+	if u, err = UnmarshalRow[user](e, 0); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID == nil || *u.ID != 0 || u.Name == nil || *u.Name != "" || !u.Score.Valid || u.CreatedAt == nil || !u.Updated.Valid {
+		t.Errorf("expected zero but valid values, got %+v", u)
+	}
+}
+
+func TestUnmarshalUnsignedColumns(t *testing.T) {
+	type counters struct {
+		Tiny   uint8
+		Medium uint32
+		Big    uint64
+		BigID  types.Suint64 `gorm:"column:big"`
+		Signed int64         `gorm:"column:tiny"`
+	}
+	columns := []schema.TableColumn{
+		{Name: "tiny", Type: schema.TYPE_NUMBER, IsUnsigned: true},
+		{Name: "medium", Type: schema.TYPE_MEDIUM_INT, IsUnsigned: true},
+		{Name: "big", Type: schema.TYPE_NUMBER, IsUnsigned: true},
+	}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{int8(-1), int32(-1), int64(-1)})
+
+	c, err := UnmarshalRow[counters](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Tiny != 255 || c.Medium != 16777215 || c.Big != math.MaxUint64 || c.BigID != math.MaxUint64 || c.Signed != 255 {
+		t.Errorf("unexpected counters %+v", c)
+	}
+
+	var overflow struct {
+		Big int64
+	}
+	if err := Unmarshal(&overflow, e, 0); !errors.Is(err, ErrColumnType) {
+		t.Errorf("expected an overflow error, got %v", err)
+	}
+
+	columns[0].IsUnsigned = false
+	var negative struct {
+		Tiny uint8
+	}
+	if err := Unmarshal(&negative, e, 0); !errors.Is(err, ErrColumnType) {
+		t.Errorf("expected an error for a negative value, got %v", err)
+	}
+}
+
+func TestUnmarshalCanalTag(t *testing.T) {
+	type user struct {
+		UserID int      `canal:"column:id"`
+		Label  string   `gorm:"column:id" canal:"column:name"`
+		Tags   []string `canal:"column:tags;json"`
+		Note   string   `canal:"-"`
+	}
+	columns := []schema.TableColumn{
+		{Name: "id", Type: schema.TYPE_NUMBER},
+		{Name: "name", Type: schema.TYPE_STRING},
+		{Name: "tags", Type: schema.TYPE_STRING},
+	}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{int64(3), "john", `["a"]`})
+
+	u, err := UnmarshalRow[user](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.UserID != 3 || u.Label != "john" || len(u.Tags) != 1 {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	defer func(name string) { DefaultTagName = name }(DefaultTagName)
+	DefaultTagName = "binlog"
+	var renamed struct {
+		ID string `binlog:"column:name"`
+	}
+	if err := Unmarshal(&renamed, e, 0); err != nil || renamed.ID != "john" {
+		t.Errorf("expected the custom tag to be used, got %+v %v", renamed, err)
+	}
+}
+
+func TestColumnCache(t *testing.T) {
+	columns := []schema.TableColumn{
+		{Name: "id", Type: schema.TYPE_NUMBER},
+		{Name: "name", Type: schema.TYPE_STRING},
+	}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{int64(1), "john"})
+	e.Table.Name = "cached"
+	if id, err := GetColumnIdByNameE(e, "name"); err != nil || id != 1 {
+		t.Fatalf("expected 1, got %d %v", id, err)
+	}
+
+	// columns swapped by an ALTER TABLE
+	e.Table.Columns[0], e.Table.Columns[1] = e.Table.Columns[1], e.Table.Columns[0]
+	if id, err := GetColumnIdByNameE(e, "name"); err != nil || id != 0 {
+		t.Fatalf("expected a stale entry to be detected, got %d %v", id, err)
+	}
+
+	e.Table.Columns[1].Name = "title"
+	InvalidateColumnCache("test", "cached")
+	if _, err := GetColumnIdByNameE(e, "id"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if id, err := GetColumnIdByNameE(e, "title"); err != nil || id != 1 {
+		t.Errorf("expected 1, got %d %v", id, err)
+	}
+}
+
+func BenchmarkGetColumnIdByName(b *testing.B) {
+	columns := make([]schema.TableColumn, 100)
+	for i := range columns {
+		columns[i] = schema.TableColumn{Name: fmt.Sprintf("column_%d", i), Type: schema.TYPE_NUMBER}
+	}
+	e := newTestEvent(canal.InsertAction, columns)
+	for i := 0; i < b.N; i++ {
+		GetColumnIdByName(e, "column_99")
+	}
+}
+
+func TestUnmarshalAll(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	insert := newTestEvent(canal.InsertAction, testColumns,
+		[]interface{}{int64(1), "a", nil, nil},
+		[]interface{}{int64(2), "b", nil, nil})
+	users, err := UnmarshalAll[user](insert)
+	if err != nil || len(users) != 2 || users[1].Name != "b" {
+		t.Errorf("unexpected users %+v %v", users, err)
+	}
+
+	update := newTestEvent(canal.UpdateAction, testColumns,
+		[]interface{}{int64(1), "old", nil, nil},
+		[]interface{}{int64(1), "new", nil, nil})
+	users, err = UnmarshalAll[user](update)
+	if err != nil || len(users) != 1 || users[0].Name != "new" {
+		t.Errorf("unexpected users %+v %v", users, err)
+	}
+
+	update.Rows = update.Rows[:1]
+	if _, err := UnmarshalAll[user](update); !errors.Is(err, ErrRowIndex) {
+		t.Errorf("expected ErrRowIndex, got %v", err)
+	}
+}
+
+func TestUnmarshalUpdate(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string
+		Score float64
+	}
+	e := newTestEvent(canal.UpdateAction, testColumns,
+		[]interface{}{int64(1), "a", 1.0, "2024-05-06 07:08:09"},
+		[]interface{}{int64(1), "a", 2.0, "2024-05-06 07:08:10"},
+		[]interface{}{int64(2), "b", 1.0, nil},
+		[]interface{}{int64(2), "c", 1.0, nil})
+
+	old, new, changed, err := UnmarshalUpdate[user](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// created_at isn't mapped by user
+	if old.Score != 1 || new.Score != 2 || fmt.Sprint(changed) != "[score]" {
+		t.Errorf("unexpected update %+v %+v %v", old, new, changed)
+	}
+	if _, _, changed, _ = UnmarshalUpdate[user](e, 1); fmt.Sprint(changed) != "[name]" {
+		t.Errorf("expected name to change, got %v", changed)
+	}
+	if _, _, _, err = UnmarshalUpdate[user](e, 2); !errors.Is(err, ErrRowIndex) {
+		t.Errorf("expected ErrRowIndex, got %v", err)
+	}
+
+	e.Action = canal.InsertAction
+	if _, _, _, err = UnmarshalUpdate[user](e, 0); !errors.Is(err, ErrNotUpdate) {
+		t.Errorf("expected ErrNotUpdate, got %v", err)
+	}
+}
+
+func TestUnmarshalBytes(t *testing.T) {
+	type file struct {
+		ID      int
+		Data    []byte
+		Thumb   []byte
+		Encoded string `canal:"column:data;base64"`
+	}
+	columns := []schema.TableColumn{
+		{Name: "id", Type: schema.TYPE_NUMBER},
+		{Name: "data", Type: schema.TYPE_STRING},
+		{Name: "thumb", Type: schema.TYPE_BINARY},
+	}
+	raw := []byte{0, 1, 0xff}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{int64(1), raw, nil})
+
+	f, err := UnmarshalRow[file](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(f.Data) != string(raw) || f.Thumb != nil || f.Encoded != "AAH/" {
+		t.Errorf("unexpected row %+v", f)
+	}
+	raw[0] = 9
+	if f.Data[0] != 0 {
+		t.Error("expected the bytes to be copied")
+	}
+
+	e = newTestEvent(canal.InsertAction, columns, []interface{}{int64(1), "abc", "\x01"})
+	if f, err = UnmarshalRow[file](e, 0); err != nil || string(f.Data) != "abc" || string(f.Thumb) != "\x01" {
+		t.Errorf("unexpected row %+v %v", f, err)
+	}
+}
+
+func TestUnmarshalSetAndBit(t *testing.T) {
+	type permission struct {
+		Roles   []string
+		Mask    uint64 `canal:"column:roles"`
+		Flags   uint8
+		Enabled bool
+	}
+	columns := []schema.TableColumn{
+		{Name: "roles", Type: schema.TYPE_SET, SetValues: []string{"read", "write", "admin"}},
+		{Name: "flags", Type: schema.TYPE_BIT},
+		{Name: "enabled", Type: schema.TYPE_BIT},
+	}
+	e := newTestEvent(canal.InsertAction, columns,
+		[]interface{}{int64(5), int64(0x81), int64(1)},
+		[]interface{}{"read,write", []byte{0x02}, nil},
+		[]interface{}{nil, int64(0x1ff), int64(0)},
+		[]interface{}{"root", int64(0), int64(0)})
+
+	p, err := UnmarshalRow[permission](e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Roles) != 2 || p.Roles[0] != "read" || p.Roles[1] != "admin" || p.Mask != 5 || p.Flags != 0x81 || !p.Enabled {
+		t.Errorf("unexpected row %+v", p)
+	}
+
+	p, err = UnmarshalRow[permission](e, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Roles) != 2 || p.Mask != 3 || p.Flags != 2 || p.Enabled {
+		t.Errorf("unexpected row %+v", p)
+	}
+
+	if _, err := UnmarshalRow[permission](e, 2); !errors.Is(err, ErrColumnType) {
+		t.Errorf("expected an overflow error, got %v", err)
+	}
+	if _, err := HelperSetE(e, 3, "roles"); !errors.Is(err, ErrColumnType) {
+		t.Errorf("expected an unknown member error, got %v", err)
+	}
+	if _, err := HelperSetE(e, 0, "flags"); !errors.Is(err, ErrColumnType) {
+		t.Errorf("expected a column type error, got %v", err)
+	}
+	if _, err := HelperSetE(e, 0, "missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected a column not found error, got %v", err)
+	}
+}
+
+func TestColumnNamer(t *testing.T) {
+	type order struct {
+		UserID    int
+		OrderNo   string `canal:"column:no"`
+		HTTPRefer string
+	}
+	columns := []schema.TableColumn{
+		{Name: "user_id", Type: schema.TYPE_NUMBER},
+		{Name: "no", Type: schema.TYPE_STRING},
+		{Name: "http_refer", Type: schema.TYPE_STRING},
+	}
+	e := newTestEvent(canal.InsertAction, columns, []interface{}{int64(3), "A1", "ads"})
+
+	o, err := UnmarshalRow[order](e, 0)
+	if err != nil || o.UserID != 3 || o.OrderNo != "A1" || o.HTTPRefer != "ads" {
+		t.Errorf("unexpected row %+v %v", o, err)
+	}
+
+	namer := ColumnNamer
+	ColumnNamer = nil
+	defer func() { ColumnNamer = namer }()
+	o, err = UnmarshalRow[order](e, 0)
+	if err != nil || o.UserID != 0 || o.OrderNo != "A1" || o.HTTPRefer != "" {
+		t.Errorf("expected only tagged fields, got %+v %v", o, err)
+	}
+}
+
+func TestRowToMap(t *testing.T) {
+	columns := append([]schema.TableColumn{}, testColumns...)
+	columns = append(columns,
+		schema.TableColumn{Name: "status", Type: schema.TYPE_ENUM, EnumValues: []string{"on", "off"}},
+		schema.TableColumn{Name: "roles", Type: schema.TYPE_SET, SetValues: []string{"read", "write"}},
+		schema.TableColumn{Name: "flags", Type: schema.TYPE_BIT},
+		schema.TableColumn{Name: "data", Type: schema.TYPE_BINARY},
+	)
+	data := []byte{1, 2}
+	e := newTestEvent(canal.InsertAction, columns,
+		[]interface{}{int32(7), "john", 1.5, "2024-05-06 07:08:09", int64(2), int64(3), int64(-1), data})
+
+	row, err := RowToMap(e, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, ok := row["created_at"].(time.Time)
+	if row["id"] != int64(7) || row["name"] != "john" || row["score"] != 1.5 || !ok || created.Year() != 2024 ||
+		row["status"] != "off" || len(row["roles"].([]string)) != 2 || row["flags"] != uint64(math.MaxUint64) {
+		t.Errorf("unexpected map %v", row)
+	}
+	data[0] = 9
+	if row["data"].([]byte)[0] != 1 {
+		t.Error("expected the bytes to be copied")
+	}
+
+	if _, err := RowToMap(e, 1); !errors.Is(err, ErrRowIndex) {
+		t.Errorf("expected a row index error, got %v", err)
+	}
+}
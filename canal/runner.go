@@ -0,0 +1,318 @@
+package canal
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// Default reconnection backoff and checkpoint interval
+const (
+	DefaultMinBackoff   = time.Second
+	DefaultMaxBackoff   = time.Minute
+	DefaultSaveInterval = time.Second
+)
+
+// RunnerOption represents an option that can be passed when instantiating a runner to customize it
+type RunnerOption func(*Runner)
+
+// RunnerStore sets the store the position is loaded from and checkpointed to.
+// Without a store the runner only resumes across reconnects of the same process.
+func RunnerStore(store PositionStore) RunnerOption {
+	return func(r *Runner) {
+		r.store = store
+	}
+}
+
+// RunnerBackoff sets the delay before reconnecting, doubled after every failure up to maxBackoff
+func RunnerBackoff(minBackoff, maxBackoff time.Duration) RunnerOption {
+	return func(r *Runner) {
+		r.minBackoff = minBackoff
+		r.maxBackoff = maxBackoff
+	}
+}
+
+// RunnerSaveInterval sets how often synced positions are saved, forced syncs (rotate, DDL) are saved at once.
+// It is also how often a Flusher handler is flushed.
+func RunnerSaveInterval(interval time.Duration) RunnerOption {
+	return func(r *Runner) {
+		r.saveInterval = interval
+	}
+}
+
+// RunnerErrorHandler allows to change the function invoked when the canal stops or a position can not be saved.
+// By default errors are written to the standard logger.
+func RunnerErrorHandler(handler func(error)) RunnerOption {
+	return func(r *Runner) {
+		r.errorHandler = handler
+	}
+}
+
+// Runner runs a go-mysql canal until its context is done, reconnecting with backoff
+// and resuming from the last checkpointed binlog position.
+//
+// Positions are checkpointed once the handler returned, which is only safe for synchronous handlers.
+// A handler delivering events asynchronously must implement Flusher, as Router does for RouterPool
+// and SinkRows: its positions are then only checkpointed after a successful Flush, and a failed Flush
+// stops the canal so that the events are read again from the last checkpoint.
+type Runner struct {
+	cfg          *canal.Config
+	handler      canal.EventHandler
+	store        PositionStore
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	saveInterval time.Duration
+	errorHandler func(error)
+
+	saveMutex sync.Mutex
+	mutex     sync.Mutex
+	pos       mysql.Position
+	synced    mysql.Position
+	savedAt   time.Time
+	dirty     bool
+	failed    bool
+}
+
+// NewRunner creates a runner streaming the binlog described by cfg to handler, e.g. a Router
+func NewRunner(cfg *canal.Config, handler canal.EventHandler, options ...RunnerOption) *Runner {
+	r := &Runner{
+		cfg:          cfg,
+		handler:      handler,
+		minBackoff:   DefaultMinBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+		saveInterval: DefaultSaveInterval,
+		errorHandler: func(err error) {
+			log.Printf("canal: runner failed: %v", err)
+		},
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+	if r.maxBackoff < r.minBackoff {
+		r.maxBackoff = r.minBackoff
+	}
+	return r
+}
+
+// Position returns the last checkpointed position
+func (r *Runner) Position() mysql.Position {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.pos
+}
+
+// Run blocks until ctx is done, the last acknowledged position is saved before it returns ctx.Err()
+func (r *Runner) Run(ctx context.Context) error {
+	backoff := r.minBackoff
+	for {
+		start := r.Position()
+		err := r.run(ctx)
+		if ctx.Err() != nil {
+			r.stop()
+			return ctx.Err()
+		}
+		if err != nil && r.errorHandler != nil {
+			r.errorHandler(err)
+		}
+		r.flush()
+
+		// reset the backoff once the sync made progress
+		if r.Position() != start {
+			backoff = r.minBackoff
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, r.maxBackoff)
+	}
+}
+
+// binlogCanal is the part of *canal.Canal used by the runner
+type binlogCanal interface {
+	GetMasterPos() (mysql.Position, error)
+	SetEventHandler(h canal.EventHandler)
+	RunFrom(pos mysql.Position) error
+	Close()
+}
+
+// newCanal creates the canal of a run, replaced in tests
+var newCanal = func(cfg *canal.Config) (binlogCanal, error) {
+	c, err := canal.NewCanal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *Runner) run(ctx context.Context) error {
+	c, err := newCanal(r.cfg)
+	if err != nil {
+		return err
+	}
+	// Canal.Close must only be called once, it panics the second time
+	stop := context.AfterFunc(ctx, c.Close)
+	defer func() {
+		if stop() {
+			c.Close()
+		}
+	}()
+
+	pos, err := r.startPosition(ctx, c)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	r.synced = pos
+	r.failed = false
+	r.mutex.Unlock()
+	c.SetEventHandler(&checkpointHandler{EventHandler: r.handler, runner: r, ctx: ctx})
+	return c.RunFrom(pos)
+}
+
+// startPosition resumes from the last synced position, then the stored one, then the current master position
+func (r *Runner) startPosition(ctx context.Context, c binlogCanal) (mysql.Position, error) {
+	if pos := r.Position(); pos.Name != "" {
+		return pos, nil
+	}
+	if r.store != nil {
+		pos, ok, err := r.store.Load(ctx)
+		if err != nil {
+			return mysql.Position{}, err
+		}
+		if ok {
+			r.setPosition(pos)
+			return pos, nil
+		}
+	}
+	pos, err := c.GetMasterPos()
+	if err != nil {
+		return mysql.Position{}, err
+	}
+	r.setPosition(pos)
+	return pos, nil
+}
+
+func (r *Runner) setPosition(pos mysql.Position) {
+	r.mutex.Lock()
+	r.pos = pos
+	r.mutex.Unlock()
+}
+
+// checkpoint records pos and saves it when forced or when the save interval elapsed.
+// With a Flusher handler pos is only recorded when due and once the handler was flushed.
+func (r *Runner) checkpoint(ctx context.Context, pos mysql.Position, force bool) error {
+	r.mutex.Lock()
+	r.synced = pos
+	due := force || time.Since(r.savedAt) >= r.saveInterval
+	r.mutex.Unlock()
+
+	if _, ok := r.handler.(Flusher); ok {
+		if !due {
+			return nil
+		}
+		return r.acknowledge(ctx)
+	}
+
+	r.mutex.Lock()
+	r.pos = pos
+	r.dirty = true
+	r.mutex.Unlock()
+	if due {
+		r.flush()
+	}
+	return nil
+}
+
+// acknowledge flushes a Flusher handler then records and saves the last synced position
+func (r *Runner) acknowledge(ctx context.Context) error {
+	r.mutex.Lock()
+	synced := r.synced
+	r.mutex.Unlock()
+
+	if f, ok := r.handler.(Flusher); ok {
+		if err := f.Flush(ctx); err != nil {
+			r.mutex.Lock()
+			r.failed = true
+			r.mutex.Unlock()
+			return err
+		}
+	}
+
+	r.mutex.Lock()
+	if synced.Name != "" && synced != r.pos {
+		r.pos = synced
+		r.dirty = true
+	}
+	r.savedAt = time.Now()
+	r.mutex.Unlock()
+	r.flush()
+	return nil
+}
+
+// stop checkpoints the last synced position when the canal stopped with the context
+func (r *Runner) stop() {
+	r.mutex.Lock()
+	failed := r.failed
+	r.mutex.Unlock()
+
+	// after a failed delivery, never checkpoint past the unacknowledged events
+	if failed {
+		r.flush()
+		return
+	}
+	if err := r.acknowledge(context.Background()); err != nil {
+		if r.errorHandler != nil {
+			r.errorHandler(err)
+		}
+		r.flush()
+	}
+}
+
+// flush saves the last synced position if it was not saved yet
+func (r *Runner) flush() {
+	r.saveMutex.Lock()
+	defer r.saveMutex.Unlock()
+
+	r.mutex.Lock()
+	if r.store == nil || !r.dirty {
+		r.mutex.Unlock()
+		return
+	}
+	pos := r.pos
+	r.dirty = false
+	r.savedAt = time.Now()
+	r.mutex.Unlock()
+
+	// ctx is already canceled on exit, the last save must not be interrupted by it
+	if err := r.store.Save(context.Background(), pos); err != nil {
+		r.mutex.Lock()
+		r.dirty = true
+		r.mutex.Unlock()
+		if r.errorHandler != nil {
+			r.errorHandler(err)
+		}
+	}
+}
+
+// checkpointHandler forwards events to the runner handler and checkpoints synced positions
+type checkpointHandler struct {
+	canal.EventHandler
+	runner *Runner
+	ctx    context.Context
+}
+
+func (h *checkpointHandler) OnPosSynced(header *replication.EventHeader, pos mysql.Position, set mysql.GTIDSet, force bool) error {
+	if err := h.EventHandler.OnPosSynced(header, pos, set, force); err != nil {
+		return err
+	}
+	return h.runner.checkpoint(h.ctx, pos, force)
+}
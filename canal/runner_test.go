@@ -0,0 +1,203 @@
+package canal
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+type memoryStore struct {
+	mutex sync.Mutex
+	pos   mysql.Position
+	saves int
+}
+
+func (s *memoryStore) Load(ctx context.Context) (mysql.Position, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pos, s.pos.Name != "", nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, pos mysql.Position) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pos = pos
+	s.saves++
+	return nil
+}
+
+func TestFilePositionStore(t *testing.T) {
+	store := NewFilePositionStore(filepath.Join(t.TempDir(), "position.json"))
+	if _, ok, err := store.Load(context.Background()); ok || err != nil {
+		t.Fatalf("expected no position, got %v %v", ok, err)
+	}
+	pos := mysql.Position{Name: "mysql-bin.000003", Pos: 1024}
+	if err := store.Save(context.Background(), pos); err != nil {
+		t.Fatal(err)
+	}
+	loaded, ok, err := store.Load(context.Background())
+	if !ok || err != nil || loaded != pos {
+		t.Errorf("expected %v, got %v %v %v", pos, loaded, ok, err)
+	}
+}
+
+func TestRunnerCheckpoint(t *testing.T) {
+	store := &memoryStore{}
+	r := NewRunner(canal.NewDefaultConfig(), &canal.DummyEventHandler{},
+		RunnerStore(store), RunnerSaveInterval(time.Hour))
+	h := &checkpointHandler{EventHandler: &canal.DummyEventHandler{}, runner: r, ctx: context.Background()}
+
+	first := mysql.Position{Name: "mysql-bin.000001", Pos: 4}
+	if err := h.OnPosSynced(nil, first, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if store.saves != 1 || store.pos != first {
+		t.Errorf("expected the first position to be saved, got %v", store.pos)
+	}
+
+	second := mysql.Position{Name: "mysql-bin.000001", Pos: 100}
+	h.OnPosSynced(nil, second, nil, false)
+	if store.saves != 1 || r.Position() != second {
+		t.Errorf("expected the save to wait for the interval, got %d saves", store.saves)
+	}
+
+	third := mysql.Position{Name: "mysql-bin.000002", Pos: 4}
+	h.OnPosSynced(nil, third, nil, true)
+	if store.saves != 2 || store.pos != third {
+		t.Errorf("expected a forced save, got %v", store.pos)
+	}
+
+	r.flush()
+	if store.saves != 2 {
+		t.Errorf("expected no save of an unchanged position, got %d saves", store.saves)
+	}
+}
+
+type flushHandler struct {
+	canal.DummyEventHandler
+	err     error
+	flushes int
+}
+
+func (h *flushHandler) Flush(ctx context.Context) error {
+	h.flushes++
+	return h.err
+}
+
+func TestRunnerCheckpointAcknowledged(t *testing.T) {
+	store := &memoryStore{}
+	handler := &flushHandler{}
+	r := NewRunner(canal.NewDefaultConfig(), handler, RunnerStore(store), RunnerSaveInterval(time.Hour))
+	h := &checkpointHandler{EventHandler: handler, runner: r, ctx: context.Background()}
+
+	first := mysql.Position{Name: "mysql-bin.000001", Pos: 4}
+	if err := h.OnPosSynced(nil, first, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if handler.flushes != 1 || store.pos != first || r.Position() != first {
+		t.Errorf("expected the flushed position to be saved, got %v", store.pos)
+	}
+
+	second := mysql.Position{Name: "mysql-bin.000001", Pos: 100}
+	if err := h.OnPosSynced(nil, second, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if handler.flushes != 1 || r.Position() != first {
+		t.Errorf("expected the position to wait for a flush, got %v", r.Position())
+	}
+
+	failure := errors.New("failure")
+	handler.err = failure
+	third := mysql.Position{Name: "mysql-bin.000002", Pos: 4}
+	if err := h.OnPosSynced(nil, third, nil, true); !errors.Is(err, failure) {
+		t.Errorf("expected the flush error, got %v", err)
+	}
+	if r.Position() != first || store.pos != first {
+		t.Errorf("expected an unacknowledged position not to be checkpointed, got %v", r.Position())
+	}
+
+	// 失败后退出不越过未确认的位点
+	handler.err = nil
+	r.stop()
+	if r.Position() != first {
+		t.Errorf("expected the position to stay at %v, got %v", first, r.Position())
+	}
+}
+
+func TestRunnerStopsWithContext(t *testing.T) {
+	store := &memoryStore{pos: mysql.Position{Name: "mysql-bin.000001", Pos: 4}}
+	cfg := canal.NewDefaultConfig()
+	cfg.Addr = "127.0.0.1:1"
+
+	var (
+		mutex sync.Mutex
+		errs  []error
+	)
+	r := NewRunner(cfg, &canal.DummyEventHandler{}, RunnerStore(store),
+		RunnerBackoff(time.Millisecond, 5*time.Millisecond),
+		RunnerErrorHandler(func(err error) {
+			mutex.Lock()
+			errs = append(errs, err)
+			mutex.Unlock()
+		}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := r.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(errs) < 2 {
+		t.Errorf("expected the runner to retry, got %v", errs)
+	}
+}
+
+// fakeCanal blocks in RunFrom until closed and panics when closed twice, like go-mysql
+type fakeCanal struct {
+	closed chan struct{}
+	closes atomic.Int32
+}
+
+func (c *fakeCanal) GetMasterPos() (mysql.Position, error) {
+	return mysql.Position{Name: "mysql-bin.000001", Pos: 4}, nil
+}
+
+func (c *fakeCanal) SetEventHandler(h canal.EventHandler) {}
+
+func (c *fakeCanal) RunFrom(pos mysql.Position) error {
+	<-c.closed
+	return nil
+}
+
+func (c *fakeCanal) Close() {
+	if c.closes.Add(1) > 1 {
+		panic("canal closed twice")
+	}
+	close(c.closed)
+}
+
+func TestRunnerClosesCanalOnce(t *testing.T) {
+	c := &fakeCanal{closed: make(chan struct{})}
+	defer func(f func(*canal.Config) (binlogCanal, error)) { newCanal = f }(newCanal)
+	newCanal = func(*canal.Config) (binlogCanal, error) {
+		return c, nil
+	}
+
+	r := NewRunner(canal.NewDefaultConfig(), &canal.DummyEventHandler{})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+	if n := c.closes.Load(); n != 1 {
+		t.Errorf("expected the canal to be closed once, got %d", n)
+	}
+}
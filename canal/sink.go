@@ -0,0 +1,319 @@
+package canal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+
+	"github.com/dreamsxin/go-utils/batcher"
+)
+
+// ErrSinkClosed is returned when a message is sent to a closed sink
+var ErrSinkClosed = errors.New("canal: sink closed")
+
+// Debezium style operation types
+const (
+	OpCreate = "c"
+	OpUpdate = "u"
+	OpDelete = "d"
+)
+
+// Message is a serialized row ready to be sent to a broker
+type Message struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+// Producer sends a batch of messages to a broker, adapt a Kafka or NATS client to it
+type Producer interface {
+	Send(ctx context.Context, messages []Message) error
+}
+
+// ProducerFunc adapts a function to Producer
+type ProducerFunc func(ctx context.Context, messages []Message) error
+
+func (f ProducerFunc) Send(ctx context.Context, messages []Message) error {
+	return f(ctx, messages)
+}
+
+// Source describes where a change event comes from
+type Source struct {
+	Schema string `json:"db"`
+	Table  string `json:"table"`
+	Pos    uint32 `json:"pos,omitempty"`
+	TsMs   int64  `json:"ts_ms"`
+}
+
+// Envelope is a Debezium-like change event, Before is nil for inserts and After for deletes
+type Envelope[T any] struct {
+	Before *T     `json:"before"`
+	After  *T     `json:"after"`
+	Source Source `json:"source"`
+	Op     string `json:"op"`
+	TsMs   int64  `json:"ts_ms"`
+}
+
+// NewEnvelope wraps a decoded row event
+func NewEnvelope[T any](ev *RowEvent[T]) *Envelope[T] {
+	env := &Envelope[T]{
+		Source: Source{Schema: ev.Schema, Table: ev.Table},
+		TsMs:   time.Now().UnixMilli(),
+	}
+	if ev.Header != nil {
+		env.Source.Pos = ev.Header.LogPos
+		env.Source.TsMs = int64(ev.Header.Timestamp) * 1000
+	}
+	row, before := ev.Row, ev.Before
+	switch ev.Action {
+	case canal.InsertAction:
+		env.Op = OpCreate
+		env.After = &row
+	case canal.UpdateAction:
+		env.Op = OpUpdate
+		env.Before = &before
+		env.After = &row
+	case canal.DeleteAction:
+		env.Op = OpDelete
+		env.Before = &row
+	}
+	return env
+}
+
+// SinkOption represents an option that can be passed when instantiating a sink to customize it
+type SinkOption func(*Sink)
+
+// SinkTopic changes how topics are named, "schema.table" by default
+func SinkTopic(topic func(schema, table string) string) SinkOption {
+	return func(s *Sink) {
+		s.topic = topic
+	}
+}
+
+// SinkBatch sends up to size messages at once, waiting at most wait for a batch to fill
+func SinkBatch(size int, wait time.Duration) SinkOption {
+	return func(s *Sink) {
+		s.batchSize = size
+		s.wait = wait
+	}
+}
+
+// SinkBuffer sets the number of messages queued before Send blocks
+func SinkBuffer(size int) SinkOption {
+	return func(s *Sink) {
+		s.buffer = size
+	}
+}
+
+// SinkErrorHandler allows to change the function invoked when the producer fails.
+// The error is also returned by the next Flush. By default errors are written to the standard logger.
+func SinkErrorHandler(handler func(error)) SinkOption {
+	return func(s *Sink) {
+		s.errorHandler = handler
+	}
+}
+
+// Sink batches messages with a batcher.Batcher and sends them with a Producer, see SinkRows.
+// Delivery is asynchronous, Flush waits for the queued messages to be acknowledged by the producer.
+type Sink struct {
+	ctx          context.Context
+	producer     Producer
+	topic        func(schema, table string) string
+	batchSize    int
+	wait         time.Duration
+	buffer       int
+	errorHandler func(error)
+
+	ch     chan Message
+	done   chan struct{}
+	mutex  sync.RWMutex
+	closed bool
+
+	ackMutex sync.Mutex
+	ackCond  *sync.Cond
+	queued   uint64
+	sent     uint64
+	err      error
+}
+
+// NewSink starts sending messages with producer until ctx is done or Close is called
+func NewSink(ctx context.Context, producer Producer, options ...SinkOption) *Sink {
+	s := &Sink{
+		ctx:       ctx,
+		producer:  producer,
+		batchSize: 100,
+		wait:      time.Second,
+		buffer:    1024,
+		topic: func(schema, table string) string {
+			return schema + "." + table
+		},
+		errorHandler: func(err error) {
+			log.Printf("canal: sink producer failed: %v", err)
+		},
+		done: make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	s.ch = make(chan Message, s.buffer)
+	s.ackCond = sync.NewCond(&s.ackMutex)
+
+	b := batcher.New(ctx, s.batchSize, s.wait, s.send, s.ch)
+	go func() {
+		defer close(s.done)
+		b.RunLoop()
+
+		// RunLoop stops reading once ctx is canceled, reject new messages and send the buffered ones
+		s.mutex.Lock()
+		s.closed = true
+		s.mutex.Unlock()
+		s.drain()
+	}()
+	return s
+}
+
+func (s *Sink) send(messages []Message) {
+	if len(messages) == 0 {
+		return
+	}
+	// the batch must still be sent after ctx is canceled
+	err := s.producer.Send(context.WithoutCancel(s.ctx), messages)
+
+	s.ackMutex.Lock()
+	s.sent += uint64(len(messages))
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	s.ackCond.Broadcast()
+	s.ackMutex.Unlock()
+
+	if err != nil && s.errorHandler != nil {
+		s.errorHandler(err)
+	}
+}
+
+// drain sends the messages left in the buffer once the batcher stopped
+func (s *Sink) drain() {
+	batch := make([]Message, 0, s.batchSize)
+	for {
+		select {
+		case msg, ok := <-s.ch:
+			if !ok {
+				s.send(batch)
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= s.batchSize {
+				s.send(batch)
+				batch = make([]Message, 0, s.batchSize)
+			}
+		default:
+			s.send(batch)
+			return
+		}
+	}
+}
+
+// Send queues a message, it blocks while the buffer is full.
+// It returns ErrSinkClosed after Close and the context error once ctx is done.
+func (s *Sink) Send(msg Message) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+	select {
+	case s.ch <- msg:
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+
+	s.ackMutex.Lock()
+	s.queued++
+	s.ackMutex.Unlock()
+	return nil
+}
+
+// Flush waits until the producer handled every message queued before the call,
+// which takes up to the batch wait. It returns the first producer error since the last Flush,
+// the failed messages are not retried.
+func (s *Sink) Flush(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		s.ackMutex.Lock()
+		s.ackCond.Broadcast()
+		s.ackMutex.Unlock()
+	})
+	defer stop()
+
+	s.ackMutex.Lock()
+	defer s.ackMutex.Unlock()
+	target := s.queued
+	for s.sent < target && ctx.Err() == nil {
+		s.ackCond.Wait()
+	}
+	if s.sent < target {
+		return ctx.Err()
+	}
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// Close sends the queued messages and waits for the producer
+func (s *Sink) Close() {
+	s.mutex.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+	s.mutex.Unlock()
+	<-s.done
+}
+
+// SinkRows sends every decoded row of a table as a JSON Envelope keyed by the primary key.
+// The sink is flushed with the router, see Router.Flush.
+func SinkRows[T any](r *Router, schema, table string, s *Sink) {
+	r.addFlusher(s)
+	r.Handle(schema, table, "", func(ctx context.Context, e *canal.RowsEvent) error {
+		events, err := DecodeRowEvents[T](e)
+		if err != nil {
+			return err
+		}
+		for i, ev := range events {
+			value, err := JSON.Marshal(NewEnvelope(ev))
+			if err != nil {
+				return err
+			}
+			row := e.Rows[i]
+			if e.Action == canal.UpdateAction {
+				row = e.Rows[2*i+1]
+			}
+			key, err := primaryKey(e, row)
+			if err != nil {
+				return err
+			}
+			if err := s.Send(Message{Topic: s.topic(ev.Schema, ev.Table), Key: key, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// primaryKey encodes the primary key values of row as a JSON array, nil without a primary key
+func primaryKey(e *canal.RowsEvent, row []interface{}) ([]byte, error) {
+	if len(e.Table.PKColumns) == 0 {
+		return nil, nil
+	}
+	values := make([]interface{}, len(e.Table.PKColumns))
+	for i, columnId := range e.Table.PKColumns {
+		if columnId < len(row) {
+			values[i] = row[columnId]
+		}
+	}
+	return JSON.Marshal(values)
+}
@@ -0,0 +1,162 @@
+package canal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+)
+
+func TestSinkRows(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		messages []Message
+	)
+	producer := ProducerFunc(func(ctx context.Context, batch []Message) error {
+		mutex.Lock()
+		messages = append(messages, batch...)
+		mutex.Unlock()
+		return nil
+	})
+	s := NewSink(context.Background(), producer, SinkBatch(10, 10*time.Millisecond))
+
+	r := NewRouter()
+	SinkRows[routedUser](r, "test", "user", s)
+
+	insert := newTestEvent(canal.InsertAction, testColumns, []interface{}{int64(1), "a", nil, nil})
+	insert.Table.PKColumns = []int{0}
+	update := newTestEvent(canal.UpdateAction, testColumns,
+		[]interface{}{int64(1), "a", nil, nil},
+		[]interface{}{int64(2), "b", nil, nil})
+	update.Table.PKColumns = []int{0}
+	for _, e := range []*canal.RowsEvent{insert, update} {
+		if err := r.OnRow(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mutex.Lock()
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages after a flush, got %d", len(messages))
+	}
+	mutex.Unlock()
+	s.Close()
+
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(messages))
+	}
+	var insertEnv Envelope[routedUser]
+	if err := JSON.Unmarshal(messages[0].Value, &insertEnv); err != nil {
+		t.Fatal(err)
+	}
+	if messages[0].Topic != "test.user" || string(messages[0].Key) != "[1]" ||
+		insertEnv.Op != OpCreate || insertEnv.Before != nil || insertEnv.After.Name != "a" || insertEnv.Source.Table != "user" {
+		t.Errorf("unexpected insert message %s %s", messages[0].Key, messages[0].Value)
+	}
+	var env Envelope[routedUser]
+	if err := JSON.Unmarshal(messages[1].Value, &env); err != nil {
+		t.Fatal(err)
+	}
+	if string(messages[1].Key) != "[2]" || env.Op != OpUpdate || env.Before.Name != "a" || env.After.Name != "b" {
+		t.Errorf("unexpected update message %s %s", messages[1].Key, messages[1].Value)
+	}
+}
+
+func TestSinkFlushReturnsProducerErrors(t *testing.T) {
+	failure := errors.New("failure")
+	var (
+		mutex sync.Mutex
+		fail  = true
+		sent  []Message
+	)
+	producer := ProducerFunc(func(ctx context.Context, batch []Message) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if fail {
+			return failure
+		}
+		sent = append(sent, batch...)
+		return nil
+	})
+	s := NewSink(context.Background(), producer, SinkBatch(10, time.Millisecond), SinkErrorHandler(nil))
+	defer s.Close()
+
+	if err := s.Send(Message{Topic: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(context.Background()); !errors.Is(err, failure) {
+		t.Errorf("expected the producer error, got %v", err)
+	}
+
+	mutex.Lock()
+	fail = false
+	mutex.Unlock()
+	if err := s.Send(Message{Topic: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Errorf("expected the error to be reported once, got %v", err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(sent) != 1 || sent[0].Topic != "b" {
+		t.Errorf("expected the second message to be acknowledged, got %v", sent)
+	}
+}
+
+func TestSinkDrainsOnCancel(t *testing.T) {
+	var (
+		mutex sync.Mutex
+		sent  int
+	)
+	producer := ProducerFunc(func(ctx context.Context, batch []Message) error {
+		mutex.Lock()
+		sent += len(batch)
+		mutex.Unlock()
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewSink(ctx, producer, SinkBatch(2, time.Hour), SinkBuffer(10))
+	for i := 0; i < 5; i++ {
+		if err := s.Send(Message{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel()
+	s.Close()
+
+	if err := s.Send(Message{}); !errors.Is(err, ErrSinkClosed) {
+		t.Errorf("expected ErrSinkClosed, got %v", err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if sent != 5 {
+		t.Errorf("expected the buffered messages to be sent, got %d", sent)
+	}
+}
+
+func TestSinkSendDuringClose(t *testing.T) {
+	s := NewSink(context.Background(), ProducerFunc(func(ctx context.Context, batch []Message) error {
+		return nil
+	}), SinkBatch(10, time.Millisecond))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := s.Send(Message{}); err != nil && !errors.Is(err, ErrSinkClosed) {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	s.Close()
+	wg.Wait()
+}
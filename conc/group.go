@@ -0,0 +1,123 @@
+// Package conc runs goroutines safely: their errors are collected and their panics recovered.
+package conc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is the error of a function which panicked
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack of the goroutine which panicked
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the value passed to panic when it is an error
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// Group runs functions in goroutines and waits for them, like errgroup.Group, but it returns all their errors
+// and converts their panics into errors. The zero Group is ready for use and does not limit the goroutines.
+type Group struct {
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	// sem holds a token per running goroutine when limited
+	sem chan struct{}
+
+	mutex sync.Mutex
+	errs  []error
+}
+
+// WithContext returns a group and a context canceled when a function of the group first fails or Wait returns
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of goroutines running at once, n < 0 removes the limit.
+// It must not be called while goroutines of the group are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("conc: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls f in a new goroutine, blocking until the limit allows it
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(f)
+}
+
+// TryGo calls f in a new goroutine only when the limit allows it, it reports whether it did
+func (g *Group) TryGo(f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+func (g *Group) start(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := call(f); err != nil {
+			g.mutex.Lock()
+			g.errs = append(g.errs, err)
+			g.mutex.Unlock()
+			if g.cancel != nil {
+				g.cancel(err)
+			}
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+// call calls f, converting a panic into a PanicError
+func call(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// Wait waits for the functions of the group and returns their errors joined, nil when none failed
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return errors.Join(g.errs...)
+}
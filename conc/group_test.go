@@ -0,0 +1,90 @@
+package conc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	var n atomic.Int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n.Add(1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil || n.Load() != 10 {
+		t.Errorf("expect 10 calls without error, get %d, %v", n.Load(), err)
+	}
+}
+
+func TestGroupErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	g, ctx := WithContext(context.Background())
+	g.Go(func() error { return errA })
+	g.Go(func() error {
+		<-ctx.Done()
+		return errB
+	})
+	err := g.Wait()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("expect both errors, get %v", err)
+	}
+	if cause := context.Cause(ctx); cause != errA {
+		t.Errorf("expect the context canceled by the first error, get %v", cause)
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	errBoom := errors.New("boom")
+	var g Group
+	g.Go(func() error { panic(errBoom) })
+	err := g.Wait()
+	var p *PanicError
+	if !errors.As(err, &p) || !errors.Is(err, errBoom) {
+		t.Fatalf("expect a PanicError wrapping boom, get %v", err)
+	}
+	if !strings.Contains(string(p.Stack), "TestGroupPanic") {
+		t.Errorf("expect the stack of the panic, get %s", p.Stack)
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+	var running, peak atomic.Int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	g.Wait()
+	if peak.Load() != 2 {
+		t.Errorf("expect at most 2 goroutines at once, get %d", peak.Load())
+	}
+
+	release := make(chan struct{})
+	g.SetLimit(1)
+	if !g.TryGo(func() error { <-release; return nil }) {
+		t.Error("expect the first goroutine started")
+	}
+	if g.TryGo(func() error { return nil }) {
+		t.Error("expect the second goroutine rejected by the limit")
+	}
+	close(release)
+	g.Wait()
+}
@@ -0,0 +1,283 @@
+// Package config loads configuration structs from defaults, files and environment variables, in that order,
+// and reloads them when the files change.
+//
+// Fields may use the types of the types package, e.g. types.Duration accepts "90s" or "2d" in every source.
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dreamsxin/go-utils/bus"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrFormat is returned for files whose extension is neither .json, .yaml nor .yml
+var ErrFormat = errors.New("config: unknown file format")
+
+// Validator is implemented by configurations validating themselves after each load
+type Validator interface {
+	Validate() error
+}
+
+// Changed is published on the bus after a reload changed the configuration
+type Changed struct {
+	Name string
+	// Old and New are pointers to the configurations
+	Old, New any
+	At       time.Time
+}
+
+type file struct {
+	path     string
+	optional bool
+}
+
+type options struct {
+	name         string
+	files        []file
+	envPrefix    string
+	validators   []func(cfg any) error
+	onChange     []func(old, new any)
+	errorHandler func(err error)
+	watcher      Watcher
+}
+
+// Option represents an option that can be passed to New
+type Option func(*options)
+
+// Name names the configuration in the errors and the change events
+func Name(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// File overlays a JSON or YAML file, chosen by its extension. Later files override earlier ones.
+func File(path string) Option {
+	return func(o *options) { o.files = append(o.files, file{path: path}) }
+}
+
+// OptionalFile is File for a file which may be missing
+func OptionalFile(path string) Option {
+	return func(o *options) { o.files = append(o.files, file{path: path, optional: true}) }
+}
+
+// Env overlays environment variables named prefix_FIELD_SUBFIELD, e.g. APP_SERVER_PORT for Server.Port
+// with the prefix "APP". Field names are taken from the yaml or json tags, or the env tag overrides the whole name.
+func Env(prefix string) Option {
+	return func(o *options) { o.envPrefix = prefix }
+}
+
+// Validate adds a function validating the configuration after each load, a failed validation fails the load
+func Validate[T any](validate func(cfg *T) error) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, func(cfg any) error { return validate(cfg.(*T)) })
+	}
+}
+
+// OnChange adds a function called after a reload changed the configuration
+func OnChange[T any](onChange func(old, new *T)) Option {
+	return func(o *options) {
+		o.onChange = append(o.onChange, func(old, new any) { onChange(old.(*T), new.(*T)) })
+	}
+}
+
+// Publish publishes a *Changed on b after a reload changed the configuration, errors of the listeners are logged
+func Publish(b bus.Bus) Option {
+	return func(o *options) {
+		o.onChange = append(o.onChange, func(old, new any) {
+			if err := b.Publish(context.Background(), &Changed{Name: o.name, Old: old, New: new, At: time.Now()}); err != nil {
+				log.Printf("config: publishing change of %q: %v", o.name, err)
+			}
+		})
+	}
+}
+
+// ErrorHandler sets the function receiving the errors of the reloads done by Watch, they are logged by default
+func ErrorHandler(handler func(err error)) Option {
+	return func(o *options) { o.errorHandler = handler }
+}
+
+// WithWatcher sets how Watch detects the changes of the files, Poll(time.Second) by default
+func WithWatcher(w Watcher) Option {
+	return func(o *options) { o.watcher = w }
+}
+
+// Loader loads a configuration of type T, it is safe for concurrent use
+type Loader[T any] struct {
+	defaults T
+	options  options
+	mutex    sync.Mutex
+	current  atomic.Pointer[T]
+}
+
+// New creates a loader starting each load from defaults, call Load before Current
+func New[T any](defaults T, opts ...Option) *Loader[T] {
+	l := &Loader[T]{defaults: defaults}
+	l.options.name = "config"
+	for _, opt := range opts {
+		opt(&l.options)
+	}
+	return l
+}
+
+// Current returns the configuration last loaded, nil before Load. It must not be modified.
+func (l *Loader[T]) Current() *T {
+	return l.current.Load()
+}
+
+// Load loads the configuration and makes it current
+func (l *Loader[T]) Load() (*T, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	cfg, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	l.current.Store(cfg)
+	return cfg, nil
+}
+
+// Reload loads the configuration again, it reports whether it changed.
+// The current configuration is kept when the load fails.
+func (l *Loader[T]) Reload() (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	cfg, err := l.load()
+	if err != nil {
+		return false, err
+	}
+	old := l.current.Load()
+	if old != nil && reflect.DeepEqual(old, cfg) {
+		return false, nil
+	}
+	l.current.Store(cfg)
+	if old != nil {
+		for _, onChange := range l.options.onChange {
+			onChange(old, cfg)
+		}
+	}
+	return true, nil
+}
+
+// Watch reloads the configuration whenever its files change, until ctx is done
+func (l *Loader[T]) Watch(ctx context.Context) error {
+	paths := make([]string, len(l.options.files))
+	for i, f := range l.options.files {
+		paths[i] = f.path
+	}
+	w := l.options.watcher
+	if w == nil {
+		w = Poll(time.Second)
+	}
+	return w.Watch(ctx, paths, func() {
+		if _, err := l.Reload(); err != nil {
+			if l.options.errorHandler != nil {
+				l.options.errorHandler(err)
+			} else {
+				log.Printf("config: reloading %q failed: %v", l.options.name, err)
+			}
+		}
+	})
+}
+
+// load builds a configuration from the defaults, the files and the environment, the lock must be held
+func (l *Loader[T]) load() (*T, error) {
+	cfg := new(T)
+	reflect.ValueOf(cfg).Elem().Set(deepCopy(reflect.ValueOf(l.defaults)))
+	for _, f := range l.options.files {
+		if err := decodeFile(f, cfg); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", l.options.name, err)
+		}
+	}
+	if l.options.envPrefix != "" {
+		if err := applyEnv(reflect.ValueOf(cfg).Elem(), l.options.envPrefix, os.LookupEnv); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", l.options.name, err)
+		}
+	}
+	if v, ok := any(cfg).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", l.options.name, err)
+		}
+	}
+	for _, validate := range l.options.validators {
+		if err := validate(cfg); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", l.options.name, err)
+		}
+	}
+	return cfg, nil
+}
+
+func decodeFile(f file, cfg any) error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if f.optional && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(f.path)) {
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("%w %q", ErrFormat, f.path)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+	return nil
+}
+
+// deepCopy copies v with its maps, slices and pointers so that decoding into the copy leaves v unchanged
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dreamsxin/go-utils/bus"
+	"github.com/dreamsxin/go-utils/types"
+)
+
+type server struct {
+	Host    string         `json:"host" yaml:"host"`
+	Port    int            `json:"port" yaml:"port"`
+	Timeout types.Duration `json:"timeout" yaml:"timeout"`
+}
+
+type testConfig struct {
+	Server  server            `json:"server" yaml:"server"`
+	Debug   types.Bool        `json:"debug" yaml:"debug"`
+	Tags    []string          `json:"tags" yaml:"tags"`
+	Labels  map[string]string `json:"labels" yaml:"labels"`
+	Secret  string            `json:"-" yaml:"-" env:"TEST_SECRET"`
+	Retries int               `json:"retries" yaml:"retries"`
+}
+
+func (c *testConfig) Validate() error {
+	if c.Server.Port <= 0 {
+		return errors.New("invalid port")
+	}
+	return nil
+}
+
+func defaults() testConfig {
+	return testConfig{
+		Server:  server{Host: "localhost", Port: 80},
+		Labels:  map[string]string{"env": "dev"},
+		Retries: 3,
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadOverlays(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "config.yaml")
+	jsonPath := filepath.Join(dir, "config.json")
+	writeFile(t, yamlPath, "server:\n  host: example.com\n  timeout: 90s\nretries: 5\n")
+	writeFile(t, jsonPath, `{"server": {"port": 8080}, "labels": {"region": "eu"}}`)
+	t.Setenv("APP_SERVER_PORT", "9090")
+	t.Setenv("APP_DEBUG", "yes")
+	t.Setenv("APP_TAGS", "a, b")
+	t.Setenv("TEST_SECRET", "s3cret")
+
+	d := defaults()
+	l := New(d, File(yamlPath), File(jsonPath), OptionalFile(filepath.Join(dir, "missing.yaml")), Env("APP"))
+	cfg, err := l.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Host != "example.com" || cfg.Server.Port != 9090 || cfg.Server.Timeout.Duration() != 90*time.Second {
+		t.Errorf("expect the server overlaid by the files and the environment, get %+v", cfg.Server)
+	}
+	if !bool(cfg.Debug) || len(cfg.Tags) != 2 || cfg.Tags[1] != "b" || cfg.Secret != "s3cret" || cfg.Retries != 5 {
+		t.Errorf("expect the environment applied, get %+v", cfg)
+	}
+	if cfg.Labels["env"] != "dev" || cfg.Labels["region"] != "eu" {
+		t.Errorf("expect the labels merged, get %v", cfg.Labels)
+	}
+	if len(d.Labels) != 1 {
+		t.Errorf("expect the defaults unchanged, get %v", d.Labels)
+	}
+	if l.Current() != cfg {
+		t.Error("expect the configuration loaded current")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := New(defaults(), File(filepath.Join(dir, "missing.yaml"))).Load(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect a missing file error, get %v", err)
+	}
+	tomlPath := filepath.Join(dir, "config.toml")
+	writeFile(t, tomlPath, "")
+	if _, err := New(defaults(), File(tomlPath)).Load(); !errors.Is(err, ErrFormat) {
+		t.Errorf("expect ErrFormat, get %v", err)
+	}
+	t.Setenv("APP_SERVER_PORT", "0")
+	if _, err := New(defaults(), Env("APP")).Load(); err == nil {
+		t.Error("expect the Validate method to reject port 0")
+	}
+	t.Setenv("APP_SERVER_PORT", "80")
+	errRetries := errors.New("too many retries")
+	_, err := New(defaults(), Env("APP"), Validate(func(cfg *testConfig) error {
+		if cfg.Retries > 2 {
+			return errRetries
+		}
+		return nil
+	})).Load()
+	if !errors.Is(err, errRetries) {
+		t.Errorf("expect the validation hook error, get %v", err)
+	}
+	t.Setenv("APP_RETRIES", "many")
+	if _, err := New(defaults(), Env("APP")).Load(); err == nil {
+		t.Error("expect an invalid number rejected")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeFile(t, path, `{"server": {"port": 8080}}`)
+
+	var mutex sync.Mutex
+	var changes []int
+	var published []*Changed
+	eventBus := bus.ProvideBus()
+	eventBus.AddEventListener(func(ctx context.Context, change *Changed) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		published = append(published, change)
+		return nil
+	})
+	errs := make(chan error, 10)
+	l := New(defaults(), Name("test"), File(path), WithWatcher(Poll(5*time.Millisecond)), Publish(eventBus),
+		ErrorHandler(func(err error) { errs <- err }),
+		OnChange(func(old, new *testConfig) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			changes = append(changes, old.Server.Port, new.Server.Port)
+		}))
+	if _, err := l.Load(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- l.Watch(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	writeFile(t, path, `{"server": {"port": 9090, "host": "changed"}}`)
+	time.Sleep(50 * time.Millisecond)
+	if port := l.Current().Server.Port; port != 9090 {
+		t.Errorf("expect the configuration reloaded, get port %d", port)
+	}
+
+	writeFile(t, path, `{"server": {"port": -1}}`)
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Error("expect the invalid configuration reported")
+	}
+	if port := l.Current().Server.Port; port != 9090 {
+		t.Errorf("expect the configuration kept after an invalid reload, get port %d", port)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(changes) != 2 || changes[0] != 8080 || changes[1] != 9090 {
+		t.Errorf("expect a change from 8080 to 9090, get %v", changes)
+	}
+	if len(published) != 1 || published[0].Name != "test" || published[0].New.(*testConfig).Server.Host != "changed" {
+		t.Errorf("expect the change published, get %v", published)
+	}
+}
+
+func TestReloadUnchanged(t *testing.T) {
+	l := New(defaults())
+	if changed, err := l.Reload(); err != nil || !changed {
+		t.Errorf("expect the first load to change, get %v, %v", changed, err)
+	}
+	if changed, err := l.Reload(); err != nil || changed {
+		t.Errorf("expect no change, get %v, %v", changed, err)
+	}
+}
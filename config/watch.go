@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watcher calls changed whenever one of paths changes, until ctx is done
+type Watcher interface {
+	Watch(ctx context.Context, paths []string, changed func()) error
+}
+
+// WatcherFunc adapts a function to a Watcher
+type WatcherFunc func(ctx context.Context, paths []string, changed func()) error
+
+func (f WatcherFunc) Watch(ctx context.Context, paths []string, changed func()) error {
+	return f(ctx, paths, changed)
+}
+
+// Poll returns a watcher checking the modification time and size of the files every interval,
+// a file created or removed counts as a change
+func Poll(interval time.Duration) Watcher {
+	return WatcherFunc(func(ctx context.Context, paths []string, changed func()) error {
+		type state struct {
+			exists  bool
+			modTime time.Time
+			size    int64
+		}
+		stat := func(path string) state {
+			info, err := os.Stat(path)
+			if err != nil {
+				return state{}
+			}
+			return state{true, info.ModTime(), info.Size()}
+		}
+		states := make([]state, len(paths))
+		for i, path := range paths {
+			states[i] = stat(path)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				modified := false
+				for i, path := range paths {
+					if s := stat(path); s != states[i] {
+						states[i], modified = s, true
+					}
+				}
+				if modified {
+					changed()
+				}
+			}
+		}
+	})
+}
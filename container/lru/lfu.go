@@ -0,0 +1,168 @@
+package lru
+
+// LFU is a cache evicting the least frequently used entries, the least recently used first among them
+type LFU[K comparable, V any] struct {
+	size    int
+	onEvict func(key K, value V)
+	items   map[K]*element[K, V]
+	// freqs holds the entries used freq times, the most recent first
+	freqs   map[int]*list[K, V]
+	minFreq int
+}
+
+var _ Interface[string, any] = (*LFU[string, any])(nil)
+
+// NewLFU creates an LFU cache of size entries, onEvict is called with the entries evicted to make room and may be nil
+func NewLFU[K comparable, V any](size int, onEvict func(key K, value V)) *LFU[K, V] {
+	if size <= 0 {
+		panic("lru: size must be positive")
+	}
+	return &LFU[K, V]{size: size, onEvict: onEvict, items: make(map[K]*element[K, V]), freqs: make(map[int]*list[K, V])}
+}
+
+func (c *LFU[K, V]) Add(key K, value V) (evicted bool) {
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.touch(e)
+		return false
+	}
+	if len(c.items) >= c.size {
+		c.evict()
+		evicted = true
+	}
+	e := &element[K, V]{key: key, value: value, freq: 1}
+	c.items[key] = e
+	c.push(e)
+	c.minFreq = 1
+	return evicted
+}
+
+func (c *LFU[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	c.touch(e)
+	return e.value, true
+}
+
+func (c *LFU[K, V]) Peek(key K) (value V, ok bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	return e.value, true
+}
+
+func (c *LFU[K, V]) Contains(key K) bool {
+	_, ok := c.items[key]
+	return ok
+}
+
+func (c *LFU[K, V]) Remove(key K) bool {
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	delete(c.items, key)
+	c.unlink(e)
+	if e.freq == c.minFreq && c.freqs[e.freq] == nil {
+		c.updateMinFreq()
+	}
+	return true
+}
+
+// Keys returns the keys from the least frequently used to the most frequently used
+func (c *LFU[K, V]) Keys() []K {
+	keys := make([]K, 0, len(c.items))
+	for freq, n := c.minFreq, 0; n < len(c.freqs); freq++ {
+		l := c.freqs[freq]
+		if l == nil {
+			continue
+		}
+		for e := l.root.prev; e != &l.root; e = e.prev {
+			keys = append(keys, e.key)
+		}
+		n++
+	}
+	return keys
+}
+
+// Frequency returns the number of times key was added or got, 0 when it is missing
+func (c *LFU[K, V]) Frequency(key K) int {
+	if e, ok := c.items[key]; ok {
+		return e.freq
+	}
+	return 0
+}
+
+func (c *LFU[K, V]) Len() int {
+	return len(c.items)
+}
+
+func (c *LFU[K, V]) Resize(size int) (evicted int) {
+	if size <= 0 {
+		panic("lru: size must be positive")
+	}
+	c.size = size
+	for len(c.items) > size {
+		c.evict()
+		evicted++
+	}
+	return evicted
+}
+
+func (c *LFU[K, V]) Purge() {
+	clear(c.items)
+	clear(c.freqs)
+	c.minFreq = 0
+}
+
+// touch moves e to the next frequency
+func (c *LFU[K, V]) touch(e *element[K, V]) {
+	c.unlink(e)
+	if e.freq == c.minFreq && c.freqs[e.freq] == nil {
+		c.minFreq++
+	}
+	e.freq++
+	c.push(e)
+}
+
+func (c *LFU[K, V]) push(e *element[K, V]) {
+	l := c.freqs[e.freq]
+	if l == nil {
+		l = newList[K, V]()
+		c.freqs[e.freq] = l
+	}
+	l.pushFront(e)
+}
+
+// unlink removes e from the list of its frequency, dropping the list once empty
+func (c *LFU[K, V]) unlink(e *element[K, V]) {
+	l := c.freqs[e.freq]
+	l.remove(e)
+	if l.len == 0 {
+		delete(c.freqs, e.freq)
+	}
+}
+
+func (c *LFU[K, V]) updateMinFreq() {
+	c.minFreq = 0
+	for freq := range c.freqs {
+		if c.minFreq == 0 || freq < c.minFreq {
+			c.minFreq = freq
+		}
+	}
+}
+
+func (c *LFU[K, V]) evict() {
+	e := c.freqs[c.minFreq].back()
+	delete(c.items, e.key)
+	c.unlink(e)
+	if c.freqs[c.minFreq] == nil {
+		c.updateMinFreq()
+	}
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+}
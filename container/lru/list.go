@@ -0,0 +1,48 @@
+package lru
+
+// element is an entry of a cache linked in a list
+type element[K comparable, V any] struct {
+	key        K
+	value      V
+	freq       int
+	prev, next *element[K, V]
+}
+
+// list is a circular doubly linked list whose root is a sentinel, the front is the most recent element
+type list[K comparable, V any] struct {
+	root element[K, V]
+	len  int
+}
+
+func newList[K comparable, V any]() *list[K, V] {
+	l := &list[K, V]{}
+	l.root.prev, l.root.next = &l.root, &l.root
+	return l
+}
+
+func (l *list[K, V]) pushFront(e *element[K, V]) {
+	e.prev, e.next = &l.root, l.root.next
+	l.root.next.prev = e
+	l.root.next = e
+	l.len++
+}
+
+func (l *list[K, V]) remove(e *element[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+	l.len--
+}
+
+func (l *list[K, V]) moveToFront(e *element[K, V]) {
+	l.remove(e)
+	l.pushFront(e)
+}
+
+// back returns the least recent element, nil when the list is empty
+func (l *list[K, V]) back() *element[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
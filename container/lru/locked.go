@@ -0,0 +1,81 @@
+package lru
+
+import "sync"
+
+// Locked makes a cache safe for concurrent use, the eviction callback is called with the lock held
+type Locked[K comparable, V any] struct {
+	mutex sync.Mutex
+	cache Interface[K, V]
+}
+
+var _ Interface[string, any] = (*Locked[string, any])(nil)
+
+// NewLocked wraps cache, e.g. NewLocked(New[string, int](100, nil))
+func NewLocked[K comparable, V any](cache Interface[K, V]) *Locked[K, V] {
+	return &Locked[K, V]{cache: cache}
+}
+
+func (l *Locked[K, V]) Add(key K, value V) (evicted bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Add(key, value)
+}
+
+func (l *Locked[K, V]) Get(key K) (value V, ok bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Get(key)
+}
+
+func (l *Locked[K, V]) Peek(key K) (value V, ok bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Peek(key)
+}
+
+func (l *Locked[K, V]) Contains(key K) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Contains(key)
+}
+
+func (l *Locked[K, V]) Remove(key K) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Remove(key)
+}
+
+func (l *Locked[K, V]) Keys() []K {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Keys()
+}
+
+func (l *Locked[K, V]) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Len()
+}
+
+func (l *Locked[K, V]) Resize(size int) (evicted int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Resize(size)
+}
+
+func (l *Locked[K, V]) Purge() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.cache.Purge()
+}
+
+// GetOrAdd returns the value of key when present, otherwise it adds value, without releasing the lock in between
+func (l *Locked[K, V]) GetOrAdd(key K, value V) (actual V, loaded bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if v, ok := l.cache.Get(key); ok {
+		return v, true
+	}
+	l.cache.Add(key, value)
+	return value, false
+}
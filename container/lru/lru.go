@@ -0,0 +1,130 @@
+// Package lru implements fixed size caches evicting the least recently or the least frequently used entries.
+// The caches are not safe for concurrent use, see Locked.
+package lru
+
+// Interface is implemented by Cache and LFU
+type Interface[K comparable, V any] interface {
+	// Add adds or updates an entry, it reports whether an entry was evicted
+	Add(key K, value V) (evicted bool)
+	// Get returns the value of key and marks it used
+	Get(key K) (value V, ok bool)
+	// Peek returns the value of key without marking it used
+	Peek(key K) (value V, ok bool)
+	// Contains reports whether key is present without marking it used
+	Contains(key K) bool
+	// Remove removes key, it reports whether it was present
+	Remove(key K) bool
+	// Keys returns the keys from the first to the last to be evicted
+	Keys() []K
+	// Len returns the number of entries
+	Len() int
+	// Resize changes the maximum number of entries, it returns the number of entries evicted
+	Resize(size int) (evicted int)
+	// Purge removes all the entries
+	Purge()
+}
+
+// Cache is a cache evicting the least recently used entries
+type Cache[K comparable, V any] struct {
+	size    int
+	onEvict func(key K, value V)
+	items   map[K]*element[K, V]
+	list    *list[K, V]
+}
+
+var _ Interface[string, any] = (*Cache[string, any])(nil)
+
+// New creates an LRU cache of size entries, onEvict is called with the entries evicted to make room and may be nil
+func New[K comparable, V any](size int, onEvict func(key K, value V)) *Cache[K, V] {
+	if size <= 0 {
+		panic("lru: size must be positive")
+	}
+	return &Cache[K, V]{size: size, onEvict: onEvict, items: make(map[K]*element[K, V]), list: newList[K, V]()}
+}
+
+func (c *Cache[K, V]) Add(key K, value V) (evicted bool) {
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.list.moveToFront(e)
+		return false
+	}
+	e := &element[K, V]{key: key, value: value}
+	c.items[key] = e
+	c.list.pushFront(e)
+	if c.list.len > c.size {
+		c.evict()
+		return true
+	}
+	return false
+}
+
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	c.list.moveToFront(e)
+	return e.value, true
+}
+
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	return e.value, true
+}
+
+func (c *Cache[K, V]) Contains(key K) bool {
+	_, ok := c.items[key]
+	return ok
+}
+
+func (c *Cache[K, V]) Remove(key K) bool {
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	delete(c.items, key)
+	c.list.remove(e)
+	return true
+}
+
+// Keys returns the keys from the least recently used to the most recently used
+func (c *Cache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.list.len)
+	for e := c.list.root.prev; e != &c.list.root; e = e.prev {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+func (c *Cache[K, V]) Len() int {
+	return c.list.len
+}
+
+func (c *Cache[K, V]) Resize(size int) (evicted int) {
+	if size <= 0 {
+		panic("lru: size must be positive")
+	}
+	c.size = size
+	for c.list.len > size {
+		c.evict()
+		evicted++
+	}
+	return evicted
+}
+
+func (c *Cache[K, V]) Purge() {
+	clear(c.items)
+	c.list = newList[K, V]()
+}
+
+func (c *Cache[K, V]) evict() {
+	e := c.list.back()
+	c.list.remove(e)
+	delete(c.items, e.key)
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+}
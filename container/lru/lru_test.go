@@ -0,0 +1,102 @@
+package lru
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	var evicted []string
+	c := New(3, func(key string, value int) {
+		evicted = append(evicted, key)
+	})
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.Get("a")
+	if !c.Add("d", 4) || !slices.Equal(evicted, []string{"b"}) {
+		t.Errorf("expect b evicted, get %v", evicted)
+	}
+	if keys := c.Keys(); !slices.Equal(keys, []string{"c", "a", "d"}) {
+		t.Errorf("expect c, a, d, get %v", keys)
+	}
+	c.Peek("c")
+	if c.Add("a", 10) {
+		t.Error("expect no eviction on update")
+	}
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("expect 10, get %d", v)
+	}
+	if n := c.Resize(1); n != 2 || !slices.Equal(evicted, []string{"b", "c", "d"}) {
+		t.Errorf("expect c and d evicted, get %d, %v", n, evicted)
+	}
+	if !c.Remove("a") || c.Remove("a") || c.Len() != 0 {
+		t.Error("expect a removed")
+	}
+	c.Add("e", 5)
+	c.Purge()
+	if c.Contains("e") || c.Len() != 0 {
+		t.Error("expect an empty cache")
+	}
+}
+
+func TestLFU(t *testing.T) {
+	var evicted []string
+	c := NewLFU(3, func(key string, value int) {
+		evicted = append(evicted, key)
+	})
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	if !c.Add("d", 4) || !slices.Equal(evicted, []string{"c"}) {
+		t.Errorf("expect c evicted, get %v", evicted)
+	}
+	// d and then b are the least frequently used
+	c.Add("e", 5)
+	if !slices.Equal(evicted, []string{"c", "d"}) {
+		t.Errorf("expect d evicted, get %v", evicted)
+	}
+	if keys := c.Keys(); !slices.Equal(keys, []string{"e", "b", "a"}) {
+		t.Errorf("expect e, b, a, get %v", keys)
+	}
+	if c.Frequency("a") != 3 || c.Frequency("c") != 0 {
+		t.Errorf("expect the frequency of a 3, get %d", c.Frequency("a"))
+	}
+
+	if !c.Remove("e") || c.Len() != 2 {
+		t.Error("expect e removed")
+	}
+	if n := c.Resize(1); n != 1 || !c.Contains("a") {
+		t.Errorf("expect b evicted, get %d, %v", n, c.Keys())
+	}
+	c.Purge()
+	c.Add("f", 6)
+	if keys := c.Keys(); !slices.Equal(keys, []string{"f"}) {
+		t.Errorf("expect f, get %v", keys)
+	}
+}
+
+func TestLocked(t *testing.T) {
+	for _, cache := range []Interface[int, int]{New[int, int](100, nil), NewLFU[int, int](100, nil)} {
+		l := NewLocked(cache)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					l.GetOrAdd(j%200, i)
+					l.Get(j % 50)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if l.Len() != 100 {
+			t.Errorf("expect 100 entries, get %d", l.Len())
+		}
+	}
+}
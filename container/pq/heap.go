@@ -0,0 +1,118 @@
+// Package pq implements generic priority queues ordered by a comparator.
+package pq
+
+import "cmp"
+
+// Less orders the smallest values first
+func Less[T cmp.Ordered](a, b T) bool {
+	return a < b
+}
+
+// Greater orders the greatest values first
+func Greater[T cmp.Ordered](a, b T) bool {
+	return a > b
+}
+
+// Heap is a binary heap popping the value ordered first by less.
+// It is not safe for concurrent use, see PriorityQueue.
+type Heap[T any] struct {
+	less   func(a, b T) bool
+	values []T
+}
+
+// NewHeap creates a heap ordered by less, e.g. NewHeap(Less[int]) pops the smallest int first
+func NewHeap[T any](less func(a, b T) bool, values ...T) *Heap[T] {
+	h := &Heap[T]{less: less, values: values}
+	for i := len(values)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+// Len returns the number of values
+func (h *Heap[T]) Len() int {
+	return len(h.values)
+}
+
+// Push adds values
+func (h *Heap[T]) Push(values ...T) {
+	for _, v := range values {
+		h.values = append(h.values, v)
+		h.up(len(h.values) - 1)
+	}
+}
+
+// Peek returns the first value without removing it, false when the heap is empty
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.values[0], true
+}
+
+// Pop removes and returns the first value, false when the heap is empty
+func (h *Heap[T]) Pop() (T, bool) {
+	var zero T
+	n := len(h.values) - 1
+	if n < 0 {
+		return zero, false
+	}
+	v := h.values[0]
+	h.values[0] = h.values[n]
+	h.values[n] = zero
+	h.values = h.values[:n]
+	if n > 0 {
+		h.down(0)
+	}
+	return v, true
+}
+
+// PopBatch removes and returns up to n values in order
+func (h *Heap[T]) PopBatch(n int) []T {
+	n = min(n, len(h.values))
+	if n <= 0 {
+		return nil
+	}
+	values := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		v, _ := h.Pop()
+		values = append(values, v)
+	}
+	return values
+}
+
+// Clear removes all the values
+func (h *Heap[T]) Clear() {
+	clear(h.values)
+	h.values = h.values[:0]
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.values[i], h.values[parent]) {
+			return
+		}
+		h.values[i], h.values[parent] = h.values[parent], h.values[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.values)
+	for {
+		first := i
+		if left := 2*i + 1; left < n && h.less(h.values[left], h.values[first]) {
+			first = left
+		}
+		if right := 2*i + 2; right < n && h.less(h.values[right], h.values[first]) {
+			first = right
+		}
+		if first == i {
+			return
+		}
+		h.values[i], h.values[first] = h.values[first], h.values[i]
+		i = first
+	}
+}
@@ -0,0 +1,92 @@
+package pq
+
+import (
+	"context"
+	"sync"
+)
+
+// PriorityQueue is a Heap safe for concurrent use, whose consumers can wait for values
+type PriorityQueue[T any] struct {
+	mutex sync.Mutex
+	heap  *Heap[T]
+	// ready is signaled when values are pushed
+	ready chan struct{}
+}
+
+// New creates a priority queue ordered by less, e.g. New(Greater[int]) pops the greatest int first
+func New[T any](less func(a, b T) bool, values ...T) *PriorityQueue[T] {
+	return &PriorityQueue[T]{heap: NewHeap(less, values...), ready: make(chan struct{}, 1)}
+}
+
+// Len returns the number of values
+func (q *PriorityQueue[T]) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.heap.Len()
+}
+
+// Push adds values
+func (q *PriorityQueue[T]) Push(values ...T) {
+	q.mutex.Lock()
+	q.heap.Push(values...)
+	q.mutex.Unlock()
+	q.signal()
+}
+
+// Peek returns the first value without removing it, false when the queue is empty
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.heap.Peek()
+}
+
+// Pop removes and returns the first value, false when the queue is empty
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.heap.Pop()
+}
+
+// PopBatch removes and returns up to n values in order
+func (q *PriorityQueue[T]) PopBatch(n int) []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.heap.PopBatch(n)
+}
+
+// PopWait removes and returns the first value, waiting for one to be pushed until ctx is done
+func (q *PriorityQueue[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		q.mutex.Lock()
+		v, ok := q.heap.Pop()
+		more := q.heap.Len() > 0
+		q.mutex.Unlock()
+		if ok {
+			if more {
+				// pass the signal on to the next consumer
+				q.signal()
+			}
+			return v, nil
+		}
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Clear removes all the values
+func (q *PriorityQueue[T]) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.heap.Clear()
+}
+
+func (q *PriorityQueue[T]) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
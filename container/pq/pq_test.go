@@ -0,0 +1,98 @@
+package pq
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHeap(t *testing.T) {
+	values := rand.Perm(100)
+	h := NewHeap(Less[int], values[:50]...)
+	h.Push(values[50:]...)
+	if v, ok := h.Peek(); !ok || v != 0 {
+		t.Errorf("expect to peek 0, get %d, %v", v, ok)
+	}
+	if batch := h.PopBatch(3); !slices.Equal(batch, []int{0, 1, 2}) {
+		t.Errorf("expect 0, 1, 2, get %v", batch)
+	}
+	for i := 3; i < 100; i++ {
+		if v, ok := h.Pop(); !ok || v != i {
+			t.Fatalf("expect %d, get %d, %v", i, v, ok)
+		}
+	}
+	if _, ok := h.Pop(); ok || h.Len() != 0 {
+		t.Error("expect an empty heap")
+	}
+	if batch := h.PopBatch(3); batch != nil {
+		t.Errorf("expect no batch, get %v", batch)
+	}
+}
+
+type task struct {
+	name     string
+	priority int
+}
+
+func TestComparator(t *testing.T) {
+	h := NewHeap(func(a, b task) bool { return a.priority > b.priority })
+	h.Push(task{"low", 1}, task{"high", 10}, task{"medium", 5})
+	var names []string
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		names = append(names, v.name)
+	}
+	if !slices.Equal(names, []string{"high", "medium", "low"}) {
+		t.Errorf("expect the highest priority first, get %v", names)
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	q := New(Greater[int])
+	ctx := context.Background()
+
+	const consumers, n = 4, 1000
+	var mutex sync.Mutex
+	var popped []int
+	var wg sync.WaitGroup
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, err := q.PopWait(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if v < 0 {
+					return
+				}
+				mutex.Lock()
+				popped = append(popped, v)
+				mutex.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		q.Push(i)
+	}
+	// consumers stop once the values are done
+	for i := 0; i < consumers; i++ {
+		q.Push(-1)
+	}
+	wg.Wait()
+	slices.Sort(popped)
+	if len(popped) != n || popped[0] != 0 || popped[n-1] != n-1 {
+		t.Errorf("expect every value popped once, get %d", len(popped))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.PopWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expect the context error, get %v", err)
+	}
+}
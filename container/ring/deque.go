@@ -0,0 +1,134 @@
+package ring
+
+import "sync"
+
+// minDequeCap is the capacity of a deque after its first push
+const minDequeCap = 8
+
+// Deque is a double-ended queue growing as needed, e.g. a FIFO queue with PushBack and PopFront
+type Deque[T any] struct {
+	locker sync.Locker
+	values []T
+	// head is the index of the front value
+	head int
+	n    int
+}
+
+// NewDeque creates an empty deque
+func NewDeque[T any](opts ...Option) *Deque[T] {
+	return &Deque[T]{locker: newOptions(opts).locker}
+}
+
+// Len returns the number of values
+func (d *Deque[T]) Len() int {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	return d.n
+}
+
+// PushBack adds v at the back
+func (d *Deque[T]) PushBack(v T) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	d.grow()
+	d.values[(d.head+d.n)%len(d.values)] = v
+	d.n++
+}
+
+// PushFront adds v at the front
+func (d *Deque[T]) PushFront(v T) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	d.grow()
+	d.head = (d.head - 1 + len(d.values)) % len(d.values)
+	d.values[d.head] = v
+	d.n++
+}
+
+// PopFront removes and returns the front value, false when the deque is empty
+func (d *Deque[T]) PopFront() (T, bool) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	var zero T
+	if d.n == 0 {
+		return zero, false
+	}
+	v := d.values[d.head]
+	d.values[d.head] = zero
+	d.head = (d.head + 1) % len(d.values)
+	d.n--
+	return v, true
+}
+
+// PopBack removes and returns the back value, false when the deque is empty
+func (d *Deque[T]) PopBack() (T, bool) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	var zero T
+	if d.n == 0 {
+		return zero, false
+	}
+	i := (d.head + d.n - 1) % len(d.values)
+	v := d.values[i]
+	d.values[i] = zero
+	d.n--
+	return v, true
+}
+
+// Front returns the front value without removing it, false when the deque is empty
+func (d *Deque[T]) Front() (T, bool) {
+	return d.At(0)
+}
+
+// Back returns the back value without removing it, false when the deque is empty
+func (d *Deque[T]) Back() (T, bool) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	return d.at(d.n - 1)
+}
+
+// At returns the i-th value from the front, false when out of range
+func (d *Deque[T]) At(i int) (T, bool) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	return d.at(i)
+}
+
+func (d *Deque[T]) at(i int) (T, bool) {
+	if i < 0 || i >= d.n {
+		var zero T
+		return zero, false
+	}
+	return d.values[(d.head+i)%len(d.values)], true
+}
+
+// Values returns the values from the front to the back
+func (d *Deque[T]) Values() []T {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	values := make([]T, d.n)
+	for i := range values {
+		values[i] = d.values[(d.head+i)%len(d.values)]
+	}
+	return values
+}
+
+// Clear removes all the values, keeping the capacity
+func (d *Deque[T]) Clear() {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	clear(d.values)
+	d.head, d.n = 0, 0
+}
+
+// grow doubles the capacity when full, the lock must be held
+func (d *Deque[T]) grow() {
+	if d.n < len(d.values) {
+		return
+	}
+	values := make([]T, max(2*len(d.values), minDequeCap))
+	for i := 0; i < d.n; i++ {
+		values[i] = d.values[(d.head+i)%len(d.values)]
+	}
+	d.values, d.head = values, 0
+}
@@ -0,0 +1,131 @@
+// Package ring implements a fixed capacity ring buffer and a growable double-ended queue.
+package ring
+
+import "sync"
+
+type options struct {
+	locker sync.Locker
+}
+
+// Option represents an option that can be passed to NewBuffer and NewDeque
+type Option func(*options)
+
+// Synchronized makes the container safe for concurrent use, it is not by default
+func Synchronized() Option {
+	return func(o *options) { o.locker = &sync.Mutex{} }
+}
+
+func newOptions(opts []Option) options {
+	o := options{locker: noLocker{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+type noLocker struct{}
+
+func (noLocker) Lock()   {}
+func (noLocker) Unlock() {}
+
+// Policy determines what a full Buffer does with the values pushed
+type Policy int
+
+const (
+	// Overwrite drops the oldest value, it's the default policy
+	Overwrite Policy = iota
+	// Reject drops the value pushed
+	Reject
+)
+
+// Buffer is a ring buffer holding up to a fixed number of values, e.g. the most recent events
+type Buffer[T any] struct {
+	locker sync.Locker
+	policy Policy
+	values []T
+	// head is the index of the oldest value
+	head int
+	n    int
+}
+
+// NewBuffer creates a buffer of capacity values
+func NewBuffer[T any](capacity int, policy Policy, opts ...Option) *Buffer[T] {
+	if capacity <= 0 {
+		panic("ring: capacity must be positive")
+	}
+	return &Buffer[T]{locker: newOptions(opts).locker, policy: policy, values: make([]T, capacity)}
+}
+
+// Cap returns the capacity of the buffer
+func (b *Buffer[T]) Cap() int {
+	return len(b.values)
+}
+
+// Len returns the number of values
+func (b *Buffer[T]) Len() int {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return b.n
+}
+
+// Push adds v as the newest value, it returns false when the buffer is full and rejects it
+func (b *Buffer[T]) Push(v T) bool {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	if b.n == len(b.values) {
+		if b.policy == Reject {
+			return false
+		}
+		b.values[b.head] = v
+		b.head = (b.head + 1) % len(b.values)
+		return true
+	}
+	b.values[(b.head+b.n)%len(b.values)] = v
+	b.n++
+	return true
+}
+
+// Pop removes and returns the oldest value, false when the buffer is empty
+func (b *Buffer[T]) Pop() (T, bool) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	var zero T
+	if b.n == 0 {
+		return zero, false
+	}
+	v := b.values[b.head]
+	b.values[b.head] = zero
+	b.head = (b.head + 1) % len(b.values)
+	b.n--
+	return v, true
+}
+
+// Peek returns the oldest value without removing it, false when the buffer is empty
+func (b *Buffer[T]) Peek() (T, bool) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	if b.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return b.values[b.head], true
+}
+
+// Values returns the values from the oldest to the newest
+func (b *Buffer[T]) Values() []T {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	values := make([]T, b.n)
+	for i := range values {
+		values[i] = b.values[(b.head+i)%len(b.values)]
+	}
+	return values
+}
+
+// Clear removes all the values
+func (b *Buffer[T]) Clear() {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	clear(b.values)
+	b.head, b.n = 0, 0
+}
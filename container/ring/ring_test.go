@@ -0,0 +1,96 @@
+package ring
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	b := NewBuffer[int](3, Overwrite)
+	for i := 1; i <= 5; i++ {
+		b.Push(i)
+	}
+	if values := b.Values(); !slices.Equal(values, []int{3, 4, 5}) {
+		t.Errorf("expect the 3 newest values, get %v", values)
+	}
+	if v, ok := b.Pop(); !ok || v != 3 {
+		t.Errorf("expect the oldest value 3, get %d, %v", v, ok)
+	}
+	if v, ok := b.Peek(); !ok || v != 4 || b.Len() != 2 {
+		t.Errorf("expect to peek 4, get %d, %v", v, ok)
+	}
+	b.Clear()
+	if _, ok := b.Pop(); ok || b.Len() != 0 || b.Cap() != 3 {
+		t.Error("expect an empty buffer")
+	}
+}
+
+func TestBufferReject(t *testing.T) {
+	b := NewBuffer[int](2, Reject)
+	if !b.Push(1) || !b.Push(2) || b.Push(3) {
+		t.Error("expect the third value rejected")
+	}
+	b.Pop()
+	if !b.Push(3) || !slices.Equal(b.Values(), []int{2, 3}) {
+		t.Errorf("expect 2, 3, get %v", b.Values())
+	}
+}
+
+func TestDeque(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 20; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	if d.Len() != 40 {
+		t.Fatalf("expect 40 values, get %d", d.Len())
+	}
+	if v, _ := d.Front(); v != -20 {
+		t.Errorf("expect -20 at the front, get %d", v)
+	}
+	if v, _ := d.Back(); v != 19 {
+		t.Errorf("expect 19 at the back, get %d", v)
+	}
+	if v, ok := d.At(20); !ok || v != 0 {
+		t.Errorf("expect 0 in the middle, get %d, %v", v, ok)
+	}
+	values := d.Values()
+	if !slices.IsSorted(values) {
+		t.Errorf("expect sorted values, get %v", values)
+	}
+	for i := 19; i >= 0; i-- {
+		if v, ok := d.PopBack(); !ok || v != i {
+			t.Fatalf("expect %d, get %d, %v", i, v, ok)
+		}
+		if v, ok := d.PopFront(); !ok || v != -20+19-i {
+			t.Fatalf("expect %d, get %d, %v", -20+19-i, v, ok)
+		}
+	}
+	if _, ok := d.PopFront(); ok {
+		t.Error("expect an empty deque")
+	}
+	if _, ok := d.Back(); ok {
+		t.Error("expect no back value")
+	}
+}
+
+func TestSynchronized(t *testing.T) {
+	d := NewDeque[int](Synchronized())
+	b := NewBuffer[int](10, Overwrite, Synchronized())
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				d.PushBack(j)
+				b.Push(j)
+			}
+		}()
+	}
+	wg.Wait()
+	if d.Len() != 4000 || b.Len() != 10 {
+		t.Errorf("expect 4000 and 10 values, get %d and %d", d.Len(), b.Len())
+	}
+}
@@ -0,0 +1,209 @@
+// Package ttlmap implements a map whose entries expire, for cases where cache.Cache is more than needed.
+package ttlmap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dreamsxin/go-utils/container/pq"
+)
+
+// Option represents an option that can be passed to New
+type Option[K comparable, V any] func(*Map[K, V])
+
+// OnExpire sets a function called with the entries removed because they expired,
+// from the janitor goroutine and without holding the lock of the map
+func OnExpire[K comparable, V any](onExpire func(key K, value V)) Option[K, V] {
+	return func(m *Map[K, V]) { m.onExpire = onExpire }
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// Map is a map whose entries expire after a time to live. A janitor goroutine removes the expired entries
+// until Stop is called. It is safe for concurrent use.
+type Map[K comparable, V any] struct {
+	ttl      time.Duration
+	onExpire func(key K, value V)
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	entries map[K]*entry[K, V]
+	// expirations holds the entries which expire, the ones replaced or deleted since are skipped
+	expirations *pq.Heap[*entry[K, V]]
+	// wake is signaled when an entry expires before the ones the janitor waits for
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a map whose entries live for ttl by default, forever when ttl is 0
+func New[K comparable, V any](ttl time.Duration, options ...Option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[K]*entry[K, V]),
+		expirations: pq.NewHeap(func(a, b *entry[K, V]) bool {
+			return a.expires.Before(b.expires)
+		}),
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(m)
+	}
+	go m.janitor()
+	return m
+}
+
+// Set sets the value of key for the default time to live
+func (m *Map[K, V]) Set(key K, value V) {
+	m.SetTTL(key, value, m.ttl)
+}
+
+// SetTTL sets the value of key for ttl, forever when ttl is 0
+func (m *Map[K, V]) SetTTL(key K, value V, ttl time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.set(key, value, ttl)
+}
+
+// set stores an entry, the lock must be held
+func (m *Map[K, V]) set(key K, value V, ttl time.Duration) {
+	e := &entry[K, V]{key: key, value: value}
+	m.entries[key] = e
+	if ttl <= 0 {
+		return
+	}
+	e.expires = m.now().Add(ttl)
+	first, ok := m.expirations.Peek()
+	m.expirations.Push(e)
+	if !ok || e.expires.Before(first.expires) {
+		select {
+		case m.wake <- struct{}{}:
+		default:
+		}
+	}
+	// drop the entries replaced or deleted once they outnumber the live ones
+	if n := m.expirations.Len(); n > 64 && n > 2*len(m.entries) {
+		live := make([]*entry[K, V], 0, len(m.entries))
+		for _, e := range m.entries {
+			if !e.expires.IsZero() {
+				live = append(live, e)
+			}
+		}
+		m.expirations.Clear()
+		m.expirations.Push(live...)
+	}
+}
+
+// Get returns the value of key, false when it is missing or expired
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.get(key)
+}
+
+// get returns the value of a live entry, the lock must be held
+func (m *Map[K, V]) get(key K) (V, bool) {
+	e, ok := m.entries[key]
+	if !ok || m.expired(e, m.now()) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// GetOrSet returns the value of key when it is present, otherwise it sets value for the default time to live.
+// loaded reports whether the value was present.
+func (m *Map[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if v, ok := m.get(key); ok {
+		return v, true
+	}
+	m.set(key, value, m.ttl)
+	return value, false
+}
+
+// Delete removes key, the expiry callback is not called
+func (m *Map[K, V]) Delete(key K) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.entries, key)
+}
+
+// Len returns the number of entries, the expired ones not removed yet included
+func (m *Map[K, V]) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.entries)
+}
+
+// Stop stops the janitor, expired entries are no longer removed
+func (m *Map[K, V]) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
+
+func (m *Map[K, V]) expired(e *entry[K, V], now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+func (m *Map[K, V]) janitor() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		next, expired := m.removeExpired()
+		for _, e := range expired {
+			m.onExpire(e.key, e.value)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		var wait <-chan time.Time
+		if !next.IsZero() {
+			timer.Reset(next.Sub(m.now()))
+			wait = timer.C
+		}
+		select {
+		case <-m.stop:
+			return
+		case <-m.wake:
+		case <-wait:
+		}
+	}
+}
+
+// removeExpired removes the expired entries, it returns them when there is an expiry callback
+// and when the next entry expires
+func (m *Map[K, V]) removeExpired() (next time.Time, expired []*entry[K, V]) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := m.now()
+	for {
+		e, ok := m.expirations.Peek()
+		if !ok {
+			return time.Time{}, expired
+		}
+		if m.entries[e.key] != e {
+			// replaced or deleted
+			m.expirations.Pop()
+			continue
+		}
+		if !m.expired(e, now) {
+			return e.expires, expired
+		}
+		m.expirations.Pop()
+		delete(m.entries, e.key)
+		if m.onExpire != nil {
+			expired = append(expired, e)
+		}
+	}
+}
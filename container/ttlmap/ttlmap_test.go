@@ -0,0 +1,85 @@
+package ttlmap
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	var mutex sync.Mutex
+	expired := map[string]int{}
+	m := New(20*time.Millisecond, OnExpire(func(key string, value int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		expired[key] = value
+	}))
+	defer m.Stop()
+
+	m.Set("a", 1)
+	m.SetTTL("b", 2, 0)
+	m.SetTTL("c", 3, time.Hour)
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("expect 1, get %d, %v", v, ok)
+	}
+	if v, loaded := m.GetOrSet("a", 10); !loaded || v != 1 {
+		t.Errorf("expect the value present, get %d, %v", v, loaded)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := m.Get("a"); ok {
+		t.Error("expect a expired")
+	}
+	if v, ok := m.Get("b"); !ok || v != 2 {
+		t.Errorf("expect b never expiring, get %d, %v", v, ok)
+	}
+	if m.Len() != 2 {
+		t.Errorf("expect a removed, get %d entries", m.Len())
+	}
+	mutex.Lock()
+	if len(expired) != 1 || expired["a"] != 1 {
+		t.Errorf("expect the callback called for a, get %v", expired)
+	}
+	mutex.Unlock()
+
+	if v, loaded := m.GetOrSet("a", 10); loaded || v != 10 {
+		t.Errorf("expect the value set, get %d, %v", v, loaded)
+	}
+	m.Delete("a")
+	if _, ok := m.Get("a"); ok {
+		t.Error("expect a deleted")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	m := New[string, int](10 * time.Millisecond)
+	defer m.Stop()
+	m.Set("a", 1)
+	// the first expiration of a no longer applies
+	m.SetTTL("a", 2, time.Hour)
+	time.Sleep(30 * time.Millisecond)
+	if v, ok := m.Get("a"); !ok || v != 2 {
+		t.Errorf("expect the replaced value kept, get %d, %v", v, ok)
+	}
+
+	for i := 0; i < 1000; i++ {
+		m.SetTTL("b", i, time.Hour)
+	}
+	m.mutex.Lock()
+	n := m.expirations.Len()
+	m.mutex.Unlock()
+	if n > 100 {
+		t.Errorf("expect the replaced expirations dropped, get %d", n)
+	}
+}
+
+func TestStop(t *testing.T) {
+	m := New[string, int](10 * time.Millisecond)
+	m.Stop()
+	m.Stop()
+	m.Set("a", 1)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := m.Get("a"); ok || m.Len() != 1 {
+		t.Error("expect a expired but not removed once stopped")
+	}
+}
@@ -10,7 +10,9 @@ require (
 	github.com/json-iterator/go v1.1.12
 	github.com/redis/go-redis/v9 v9.4.0
 	github.com/stretchr/testify v1.9.0
+	go.mongodb.org/mongo-driver v1.14.0
 	golang.org/x/text v0.3.6
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/gorm v1.25.9
 )
 
@@ -37,5 +39,4 @@ require (
 	go.uber.org/zap v1.18.1 // indirect
 	golang.org/x/sys v0.22.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,205 @@
+// Package hashring maps keys to members with consistent hashing, e.g. to route keys to cache or lock shards.
+// Adding or removing a member only moves the keys it gains or loses.
+package hashring
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/dreamsxin/go-utils/hash/siphash"
+)
+
+// DefaultVirtualNodes is the number of points on the ring per unit of weight by default
+const DefaultVirtualNodes = 160
+
+// DefaultHash hashes keys and points with SipHash-2-4
+func DefaultHash(data []byte) uint64 {
+	return siphash.Hash(0x0706050403020100, 0x0f0e0d0c0b0a0908, data)
+}
+
+// Option represents an option that can be passed to New
+type Option func(*Ring)
+
+// VirtualNodes sets the number of points on the ring per unit of weight,
+// more points spread the keys more evenly at the cost of memory
+func VirtualNodes(n int) Option {
+	return func(r *Ring) { r.virtualNodes = max(n, 1) }
+}
+
+// Hash sets the hash function of keys and points
+func Hash(hash func(data []byte) uint64) Option {
+	return func(r *Ring) { r.hash = hash }
+}
+
+type point struct {
+	hash   uint64
+	member string
+}
+
+// Ring is a consistent hashing ring, it is safe for concurrent use
+type Ring struct {
+	virtualNodes int
+	hash         func(data []byte) uint64
+
+	mutex   sync.RWMutex
+	weights map[string]int
+	// points are sorted by hash
+	points []point
+}
+
+// New creates an empty ring
+func New(options ...Option) *Ring {
+	r := &Ring{
+		virtualNodes: DefaultVirtualNodes,
+		hash:         DefaultHash,
+		weights:      make(map[string]int),
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+	return r
+}
+
+// Add adds members of weight 1
+func (r *Ring) Add(members ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, member := range members {
+		r.add(member, 1)
+	}
+	r.sort()
+}
+
+// AddWeighted adds a member receiving about weight times the keys of a member of weight 1,
+// the weight of a member added before is changed
+func (r *Ring) AddWeighted(member string, weight int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.add(member, weight)
+	r.sort()
+}
+
+// add adds the points of member, they must be sorted afterwards. The lock must be held.
+func (r *Ring) add(member string, weight int) {
+	if weight <= 0 {
+		r.remove(member)
+		return
+	}
+	old := r.weights[member]
+	if old == weight {
+		return
+	}
+	if old > weight {
+		// the points of a member are the same whatever its weight, the first ones are kept
+		r.remove(member)
+		old = 0
+	}
+	r.weights[member] = weight
+	for i := old * r.virtualNodes; i < weight*r.virtualNodes; i++ {
+		r.points = append(r.points, point{r.hash([]byte(member + "#" + strconv.Itoa(i))), member})
+	}
+}
+
+func (r *Ring) sort() {
+	slices.SortFunc(r.points, func(a, b point) int {
+		if a.hash != b.hash {
+			if a.hash < b.hash {
+				return -1
+			}
+			return 1
+		}
+		// members colliding on a point are ordered the same way on every ring
+		if a.member < b.member {
+			return -1
+		} else if a.member > b.member {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Remove removes members, their keys move to the next members on the ring
+func (r *Ring) Remove(members ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, member := range members {
+		r.remove(member)
+	}
+}
+
+// remove removes the points of member, they stay sorted. The lock must be held.
+func (r *Ring) remove(member string) {
+	if _, ok := r.weights[member]; !ok {
+		return
+	}
+	delete(r.weights, member)
+	r.points = slices.DeleteFunc(r.points, func(p point) bool {
+		return p.member == member
+	})
+}
+
+// Members returns the members and their weights
+func (r *Ring) Members() map[string]int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	members := make(map[string]int, len(r.weights))
+	for member, weight := range r.weights {
+		members[member] = weight
+	}
+	return members
+}
+
+// Len returns the number of members
+func (r *Ring) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.weights)
+}
+
+// Get returns the member of key, false when the ring is empty
+func (r *Ring) Get(key string) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.points) == 0 {
+		return "", false
+	}
+	return r.points[r.search(key)].member, true
+}
+
+// GetN returns up to n distinct members for key in ring order, e.g. to place replicas.
+// The first one is the member returned by Get.
+func (r *Ring) GetN(key string, n int) []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	n = min(n, len(r.weights))
+	if n <= 0 {
+		return nil
+	}
+	members := make([]string, 0, n)
+	start := r.search(key)
+	for i := 0; i < len(r.points) && len(members) < n; i++ {
+		member := r.points[(start+i)%len(r.points)].member
+		if !slices.Contains(members, member) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// search returns the index of the first point at or after the hash of key, the lock must be held
+func (r *Ring) search(key string) int {
+	h := r.hash([]byte(key))
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		if p.hash < h {
+			return -1
+		} else if p.hash > h {
+			return 1
+		}
+		return 0
+	})
+	if i == len(r.points) {
+		return 0
+	}
+	return i
+}
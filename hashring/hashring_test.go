@@ -0,0 +1,117 @@
+package hashring
+
+import (
+	"strconv"
+	"testing"
+)
+
+func keys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func assignments(r *Ring, keys []string) map[string]string {
+	m := make(map[string]string, len(keys))
+	for _, key := range keys {
+		m[key], _ = r.Get(key)
+	}
+	return m
+}
+
+func TestGet(t *testing.T) {
+	r := New()
+	if _, ok := r.Get("a"); ok {
+		t.Error("expect no member on an empty ring")
+	}
+	r.Add("a", "b", "c")
+	counts := map[string]int{}
+	for _, member := range assignments(r, keys(30000)) {
+		counts[member]++
+	}
+	for member, n := range counts {
+		if n < 8000 || n > 12000 {
+			t.Errorf("expect about 10000 keys for %s, get %d", member, n)
+		}
+	}
+}
+
+func TestMinimalMovement(t *testing.T) {
+	r := New()
+	r.Add("a", "b", "c")
+	ks := keys(10000)
+	before := assignments(r, ks)
+
+	r.Add("d")
+	after := assignments(r, ks)
+	moved := 0
+	for _, key := range ks {
+		if before[key] != after[key] {
+			moved++
+			if after[key] != "d" {
+				t.Fatalf("expect keys to move only to the new member, %s moved to %s", key, after[key])
+			}
+		}
+	}
+	if moved < 1500 || moved > 3500 {
+		t.Errorf("expect about a quarter of the keys moved, get %d", moved)
+	}
+
+	r.Remove("d")
+	for key, member := range assignments(r, ks) {
+		if before[key] != member {
+			t.Fatalf("expect %s back to %s, get %s", key, before[key], member)
+		}
+	}
+}
+
+func TestWeights(t *testing.T) {
+	r := New()
+	r.Add("a")
+	r.AddWeighted("b", 3)
+	counts := map[string]int{}
+	for _, member := range assignments(r, keys(20000)) {
+		counts[member]++
+	}
+	if ratio := float64(counts["b"]) / float64(counts["a"]); ratio < 2.4 || ratio > 3.6 {
+		t.Errorf("expect b to get about 3 times the keys of a, get %v", ratio)
+	}
+
+	ks := keys(5000)
+	before := assignments(r, ks)
+	r.AddWeighted("b", 1)
+	for key, member := range assignments(r, ks) {
+		if before[key] == "a" && member != "a" {
+			t.Fatalf("expect the keys of a kept when b gets lighter, %s moved to %s", key, member)
+		}
+	}
+	if members := r.Members(); len(members) != 2 || members["b"] != 1 {
+		t.Errorf("expect the weight of b changed, get %v", members)
+	}
+	r.AddWeighted("b", 0)
+	if r.Len() != 1 {
+		t.Errorf("expect b removed with weight 0, get %d members", r.Len())
+	}
+}
+
+func TestGetN(t *testing.T) {
+	r := New()
+	r.Add("a", "b", "c")
+	for _, key := range keys(100) {
+		members := r.GetN(key, 5)
+		if len(members) != 3 {
+			t.Fatalf("expect the 3 members, get %v", members)
+		}
+		if first, _ := r.Get(key); members[0] != first {
+			t.Errorf("expect %s first, get %v", first, members)
+		}
+		if members[0] == members[1] || members[1] == members[2] || members[0] == members[2] {
+			t.Errorf("expect distinct members, get %v", members)
+		}
+	}
+	if members := r.GetN("key", 0); len(members) != 0 {
+		t.Errorf("expect no member, get %v", members)
+	}
+}
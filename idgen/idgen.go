@@ -0,0 +1,168 @@
+// Package idgen generates unique 64-bit IDs sorted by creation time, e.g. for database primary keys.
+//
+// An ID is made of, from the most significant bit:
+//
+//	1 bit unused, always 0 so that IDs are positive
+//	41 bits of milliseconds since the epoch, about 69 years
+//	10 bits of node ID, up to 1024 generators running at once
+//	12 bits of sequence, up to 4096 IDs per millisecond and node
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	NodeBits     = 10
+	SequenceBits = 12
+	TimeBits     = 63 - NodeBits - SequenceBits
+
+	MaxNode     = 1<<NodeBits - 1
+	maxSequence = 1<<SequenceBits - 1
+	maxTime     = 1<<TimeBits - 1
+)
+
+// DefaultEpoch is the time IDs are counted from, 2024-01-01 UTC
+var DefaultEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// DefaultMaxDrift is how far back the clock may move before Next fails instead of waiting
+const DefaultMaxDrift = 10 * time.Millisecond
+
+var (
+	// ErrInvalidNode is returned by New for node IDs out of [0, MaxNode]
+	ErrInvalidNode = errors.New("idgen: invalid node ID")
+	// ErrClockBackwards is returned when the clock moved back further than the maximum drift
+	ErrClockBackwards = errors.New("idgen: clock moved backwards")
+	// ErrTimeOverflow is returned once the milliseconds since the epoch no longer fit in an ID
+	ErrTimeOverflow = errors.New("idgen: time overflow")
+)
+
+// Option represents an option that can be passed to New
+type Option func(*Generator)
+
+// Epoch sets the time IDs are counted from, all the generators of a system must share it
+func Epoch(epoch time.Time) Option {
+	return func(g *Generator) { g.epoch = epoch }
+}
+
+// MaxDrift sets how far back the clock may move, e.g. after an NTP adjustment, before Next fails.
+// Next waits for the clock to catch up within the drift.
+func MaxDrift(drift time.Duration) Option {
+	return func(g *Generator) { g.maxDrift = drift }
+}
+
+// Generator generates the IDs of a node, it is safe for concurrent use.
+// Each running generator of a system must have its own node ID, see AllocateNode.
+type Generator struct {
+	node     int64
+	epoch    time.Time
+	maxDrift time.Duration
+	now      func() time.Time
+	sleep    func(time.Duration)
+
+	mutex sync.Mutex
+	// last is the millisecond of the last ID
+	last     int64
+	sequence int64
+}
+
+// New creates the generator of a node
+func New(node int64, options ...Option) (*Generator, error) {
+	if node < 0 || node > MaxNode {
+		return nil, fmt.Errorf("%w %d", ErrInvalidNode, node)
+	}
+	g := &Generator{
+		node:     node,
+		epoch:    DefaultEpoch,
+		maxDrift: DefaultMaxDrift,
+		now:      time.Now,
+		sleep:    time.Sleep,
+		last:     -1,
+	}
+	for _, opt := range options {
+		opt(g)
+	}
+	return g, nil
+}
+
+// Node returns the node ID of the generator
+func (g *Generator) Node() int64 {
+	return g.node
+}
+
+// Next returns a new ID
+func (g *Generator) Next() (int64, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.next()
+}
+
+// NextN returns n new IDs in increasing order, taking the lock once
+func (g *Generator) NextN(n int) ([]int64, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	ids := make([]int64, 0, n)
+	for i := 0; i < n; i++ {
+		id, err := g.next()
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// next returns a new ID, the lock must be held
+func (g *Generator) next() (int64, error) {
+	ms := g.millis()
+	if ms < g.last {
+		drift := time.Duration(g.last-ms) * time.Millisecond
+		if drift > g.maxDrift {
+			return 0, fmt.Errorf("%w by %v", ErrClockBackwards, drift)
+		}
+		g.sleep(drift)
+		if ms = g.millis(); ms < g.last {
+			return 0, fmt.Errorf("%w by %v", ErrClockBackwards, time.Duration(g.last-ms)*time.Millisecond)
+		}
+	}
+	if ms == g.last {
+		g.sequence = (g.sequence + 1) & maxSequence
+		if g.sequence == 0 {
+			// the sequence is exhausted, wait for the next millisecond
+			for ms <= g.last {
+				g.sleep(time.Millisecond - time.Duration(g.now().Sub(g.epoch)%time.Millisecond))
+				ms = g.millis()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	if ms > maxTime {
+		return 0, ErrTimeOverflow
+	}
+	g.last = ms
+	return ms<<(NodeBits+SequenceBits) | g.node<<SequenceBits | g.sequence, nil
+}
+
+func (g *Generator) millis() int64 {
+	return g.now().Sub(g.epoch).Milliseconds()
+}
+
+// Parts are the parts of an ID
+type Parts struct {
+	Time     time.Time
+	Node     int64
+	Sequence int64
+}
+
+// Parse splits an ID generated with epoch into its parts
+func Parse(id int64, epoch time.Time) Parts {
+	return Parts{
+		Time:     epoch.Add(time.Duration(id>>(NodeBits+SequenceBits)) * time.Millisecond),
+		Node:     id >> SequenceBits & MaxNode,
+		Sequence: id & maxSequence,
+	}
+}
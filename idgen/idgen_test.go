@@ -0,0 +1,129 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source advanced by hand and by sleep
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestGenerator(t *testing.T, node int64, options ...Option) (*Generator, *clock) {
+	g, err := New(node, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &clock{now: DefaultEpoch.Add(time.Hour)}
+	g.now, g.sleep = c.Now, c.Sleep
+	return g, c
+}
+
+func TestNext(t *testing.T) {
+	g, c := newTestGenerator(t, 42)
+	ids, err := g.NextN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range ids {
+		parts := Parse(id, DefaultEpoch)
+		if !parts.Time.Equal(c.now) || parts.Node != 42 || parts.Sequence != int64(i) {
+			t.Errorf("expect %v, node 42 and sequence %d, get %+v", c.now, i, parts)
+		}
+	}
+
+	c.now = c.now.Add(time.Millisecond)
+	id, _ := g.Next()
+	if id <= ids[2] || Parse(id, DefaultEpoch).Sequence != 0 {
+		t.Errorf("expect a greater ID with the sequence reset, get %d after %d", id, ids[2])
+	}
+}
+
+func TestSequenceExhausted(t *testing.T) {
+	g, c := newTestGenerator(t, 1)
+	start := c.now
+	ids, err := g.NextN(maxSequence + 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := Parse(ids[len(ids)-1], DefaultEpoch)
+	if !c.now.Equal(start.Add(time.Millisecond)) || !last.Time.Equal(c.now) || last.Sequence != 0 {
+		t.Errorf("expect the next millisecond once the sequence is exhausted, get %+v", last)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("expect increasing IDs, get %d after %d", ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestClockBackwards(t *testing.T) {
+	g, c := newTestGenerator(t, 1, MaxDrift(5*time.Millisecond))
+	first, _ := g.Next()
+	c.now = c.now.Add(-3 * time.Millisecond)
+	if id, err := g.Next(); err != nil || id <= first {
+		t.Errorf("expect to wait for a small drift, get %d, %v", id, err)
+	}
+	c.now = c.now.Add(-time.Second)
+	if _, err := g.Next(); !errors.Is(err, ErrClockBackwards) {
+		t.Errorf("expect ErrClockBackwards, get %v", err)
+	}
+}
+
+func TestInvalidNode(t *testing.T) {
+	for _, node := range []int64{-1, MaxNode + 1} {
+		if _, err := New(node); !errors.Is(err, ErrInvalidNode) {
+			t.Errorf("expect ErrInvalidNode for %d, get %v", node, err)
+		}
+	}
+}
+
+func TestConcurrentNext(t *testing.T) {
+	g, err := New(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mutex sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids, err := g.NextN(1000)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAllocateNodeInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second, time.Microsecond} {
+		if _, err := AllocateNode(context.Background(), nil, "test", ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("%v: expected ErrInvalidTTL, got %v", ttl, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package idgen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNoNodeAvailable is returned by AllocateNode when every node ID is leased
+	ErrNoNodeAvailable = errors.New("idgen: no node ID available")
+	// ErrInvalidTTL is returned by AllocateNode for a lease shorter than a millisecond
+	ErrInvalidTTL = errors.New("idgen: lease ttl must be at least a millisecond")
+)
+
+// renewScript extends the lease only when it is still held by the token
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lease only when it is still held by the token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// NodeLease is a node ID leased from Redis, renewed in the background until released
+type NodeLease struct {
+	db     *redis.Client
+	key    string
+	token  string
+	node   int64
+	ttl    time.Duration
+	cancel context.CancelFunc
+	done   chan struct{}
+	lost   chan struct{}
+}
+
+// AllocateNode leases the first node ID not leased by another instance under prefix,
+// e.g. AllocateNode(ctx, db, "orders", time.Minute), and renews it every ttl/3, ttl must be at least a millisecond.
+// The lease expires ttl after the instance stopped renewing it, e.g. when it crashed.
+func AllocateNode(ctx context.Context, db *redis.Client, prefix string, ttl time.Duration) (*NodeLease, error) {
+	if ttl < time.Millisecond {
+		return nil, ErrInvalidTTL
+	}
+	// A random token, instances started at the same time must not share it
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := "token:" + hex.EncodeToString(b)
+	for node := int64(0); node <= MaxNode; node++ {
+		key := fmt.Sprintf("IDGen:node:%s:%d", prefix, node)
+		created, err := db.SetNX(ctx, key, token, ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			continue
+		}
+		renewCtx, cancel := context.WithCancel(context.Background())
+		l := &NodeLease{
+			db:     db,
+			key:    key,
+			token:  token,
+			node:   node,
+			ttl:    ttl,
+			cancel: cancel,
+			done:   make(chan struct{}),
+			lost:   make(chan struct{}),
+		}
+		go l.autoRenew(renewCtx)
+		return l, nil
+	}
+	return nil, ErrNoNodeAvailable
+}
+
+// Node returns the node ID leased
+func (l *NodeLease) Node() int64 {
+	return l.node
+}
+
+// Lost is closed when the lease could not be renewed before it expired,
+// the node ID may then be leased by another instance and must no longer be used
+func (l *NodeLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release stops renewing the lease and frees the node ID
+func (l *NodeLease) Release(ctx context.Context) error {
+	l.cancel()
+	<-l.done
+	return releaseScript.Run(ctx, l.db, []string{l.key}, l.token).Err()
+}
+
+func (l *NodeLease) autoRenew(ctx context.Context) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := renewScript.Run(ctx, l.db, []string{l.key}, l.token, l.ttl.Milliseconds()).Bool()
+			switch {
+			case err == nil && ok:
+				renewed = time.Now()
+			case err == nil || time.Since(renewed) >= l.ttl:
+				log.Printf("idgen: lease of node %d lost: %v", l.node, err)
+				close(l.lost)
+				return
+			default:
+				log.Printf("idgen: renewing lease of node %d: %v", l.node, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/dreamsxin/go-utils/ratelimit"
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript implements the generic cell rate algorithm: the key holds the theoretical arrival time (TAT)
+// of the next event in microseconds of the Redis clock, so that every instance shares the same clock.
+// It returns whether the events are allowed and, when they are not, the microseconds to wait.
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + n * interval
+local delay = new_tat - burst * interval - now
+if delay > 0 then
+	return {0, delay}
+end
+redis.call("SET", KEYS[1], string.format("%d", new_tat), "PX", math.ceil((new_tat - now) / 1000) + 1)
+return {1, 0}
+`)
+
+// RedisLimiter is a ratelimit.Limiter shared by every instance using the same key,
+// e.g. to enforce the QPS of a third-party API across a fleet.
+// Events are spaced 1/rate second apart, up to burst of them may happen at once.
+type RedisLimiter struct {
+	ctx      context.Context
+	db       *redis.Client
+	key      string
+	interval time.Duration
+	burst    int
+}
+
+var _ ratelimit.Limiter = (*RedisLimiter)(nil)
+
+// NewRedisLimiter creates a limiter allowing rate events per second, see ratelimit.Every.
+// ctx is used by Allow and AllowN.
+func NewRedisLimiter(ctx context.Context, db *redis.Client, key string, rate float64, burst int) (*RedisLimiter, error) {
+	if rate <= 0 {
+		return nil, errors.New("RedisLimiter: rate must be positive")
+	}
+	_, err := db.Ping(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RedisLimiter{
+		ctx:      ctx,
+		db:       db,
+		key:      "RedisLimiter:" + key,
+		interval: time.Duration(float64(time.Second) / rate),
+		burst:    burst,
+	}, nil
+}
+
+// Allow reports whether an event may happen now, it is rejected when Redis fails
+func (l *RedisLimiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, they are rejected when Redis fails
+func (l *RedisLimiter) AllowN(n int) bool {
+	delay, err := l.Take(l.ctx, n)
+	if err != nil {
+		log.Println("RedisLimiter allow failed:", err)
+		return false
+	}
+	return delay == 0
+}
+
+func (l *RedisLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+func (l *RedisLimiter) WaitN(ctx context.Context, n int) error {
+	if n > l.burst {
+		return ratelimit.ErrExceedsBurst
+	}
+	for {
+		delay, err := l.Take(ctx, n)
+		if err != nil || delay == 0 {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return ratelimit.ErrExceedsDeadline
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Take counts n events when they are allowed and returns 0,
+// otherwise it returns how long to wait before they may be allowed
+func (l *RedisLimiter) Take(ctx context.Context, n int) (time.Duration, error) {
+	ret, err := gcraScript.Run(ctx, l.db, []string{l.key},
+		l.interval.Microseconds(), l.burst, n).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+	if ret[0] == 1 {
+		return 0, nil
+	}
+	return time.Duration(ret[1]) * time.Microsecond, nil
+}
+
+// Reset forgets the events counted so far
+func (l *RedisLimiter) Reset(ctx context.Context) error {
+	return l.db.Del(ctx, l.key).Err()
+}
@@ -26,6 +26,18 @@ func defaultPanicHandler(panic interface{}) {
 	fmt.Printf("Worker exits from a panic: %v\nStack trace: %s\n", panic, string(debug.Stack()))
 }
 
+// CancelPolicy determines what happens to queued tasks when the parent context of a pool is cancelled
+type CancelPolicy int
+
+const (
+	// DiscardQueuedTasks stops all workers as soon as the parent context is cancelled.
+	// Tasks in the queue will not be executed. It's the default policy.
+	DiscardQueuedTasks CancelPolicy = iota
+	// CompleteQueuedTasks stops accepting new tasks when the parent context is cancelled
+	// but lets workers execute all tasks in the queue before exiting.
+	CompleteQueuedTasks
+)
+
 // ResizingStrategy represents a pool resizing strategy
 type ResizingStrategy interface {
 	Resize(runningWorkers, minWorkers, maxWorkers int) bool
@@ -41,7 +53,8 @@ func IdleTimeout(idleTimeout time.Duration) Option {
 	}
 }
 
-// MinWorkers allows to change the minimum number of workers of a worker pool
+// MinWorkers allows to change the minimum number of workers of a worker pool.
+// They are started along with the pool and are never stopped by the idle timeout.
 func MinWorkers(minWorkers int) Option {
 	return func(pool *WorkerPool) {
 		pool.minWorkers = minWorkers
@@ -62,10 +75,45 @@ func PanicHandler(panicHandler func(interface{})) Option {
 	}
 }
 
-// Context configures a parent context on a worker pool to stop all workers when it is cancelled
+// Context configures a parent context on a worker pool to stop it when it is cancelled.
+// Once the parent context is cancelled the pool no longer accepts tasks and queued tasks
+// are handled according to the configured CancelPolicy.
 func Context(parentCtx context.Context) Option {
 	return func(pool *WorkerPool) {
-		pool.context, pool.contextCancel = context.WithCancel(parentCtx)
+		pool.parentContext = parentCtx
+	}
+}
+
+// OnCancel allows to change the policy applied to queued tasks when the parent context is cancelled
+func OnCancel(policy CancelPolicy) Option {
+	return func(pool *WorkerPool) {
+		pool.cancelPolicy = policy
+	}
+}
+
+// PrewarmRetention allows to keep workers started by Prewarm from being stopped by the idle timeout
+// for the given duration, so they remain available until the expected traffic spike arrives.
+// Afterwards idle workers are stopped again down to MinWorkers.
+func PrewarmRetention(retention time.Duration) Option {
+	return func(pool *WorkerPool) {
+		pool.prewarmRetention = retention
+	}
+}
+
+// BeforeTask allows to set a hook that is invoked right before each task is executed.
+// The hook receives the ID assigned to the task, which is unique within the pool.
+func BeforeTask(hook func(taskID uint64)) Option {
+	return func(pool *WorkerPool) {
+		pool.beforeTask = hook
+	}
+}
+
+// AfterTask allows to set a hook that is invoked right after each task has completed its execution,
+// either successfully or with panic. The hook receives the ID assigned to the task and
+// the time it took to execute.
+func AfterTask(hook func(taskID uint64, duration time.Duration)) Option {
+	return func(pool *WorkerPool) {
+		pool.afterTask = hook
 	}
 }
 
@@ -79,15 +127,22 @@ type WorkerPool struct {
 	submittedTaskCount  uint64
 	successfulTaskCount uint64
 	failedTaskCount     uint64
+	prewarmDeadline     int64
+	lastTaskID          uint64
 	// Configurable settings
-	maxWorkers    int
-	maxCapacity   int
-	minWorkers    int
-	idleTimeout   time.Duration
-	strategy      ResizingStrategy
-	panicHandler  func(interface{})
-	context       context.Context
-	contextCancel context.CancelFunc
+	maxWorkers       int
+	maxCapacity      int
+	minWorkers       int
+	idleTimeout      time.Duration
+	strategy         ResizingStrategy
+	panicHandler     func(interface{})
+	beforeTask       func(taskID uint64)
+	afterTask        func(taskID uint64, duration time.Duration)
+	cancelPolicy     CancelPolicy
+	prewarmRetention time.Duration
+	parentContext    context.Context
+	context          context.Context
+	contextCancel    context.CancelFunc
 	// Private properties
 	tasks            chan func()
 	tasksCloseOnce   sync.Once
@@ -130,24 +185,36 @@ func New(maxWorkers, maxCapacity int, options ...Option) *WorkerPool {
 	if pool.idleTimeout < 0 {
 		pool.idleTimeout = defaultIdleTimeout
 	}
+	if pool.prewarmRetention < 0 {
+		pool.prewarmRetention = 0
+	}
 
 	// Initialize base context (if not already set)
-	if pool.context == nil {
-		Context(context.Background())(pool)
+	if pool.parentContext == nil {
+		pool.parentContext = context.Background()
+	}
+	baseContext := pool.parentContext
+	if pool.cancelPolicy == CompleteQueuedTasks {
+		// Workers must outlive the parent context to complete queued tasks
+		baseContext = context.WithoutCancel(baseContext)
 	}
+	pool.context, pool.contextCancel = context.WithCancel(baseContext)
 
 	// Create tasks channel
 	pool.tasks = make(chan func(), pool.maxCapacity)
 
+	// Start parent context watcher goroutine (only if the parent context can be cancelled)
+	if pool.parentContext.Done() != nil {
+		go pool.watchParentContext()
+	}
+
 	// Start purger goroutine
 	pool.workersWaitGroup.Add(1)
 	go pool.purge()
 
-	// Start minWorkers workers
-	if pool.minWorkers > 0 {
-		for i := 0; i < pool.minWorkers; i++ {
-			pool.maybeStartWorker(nil)
-		}
+	// Start minWorkers workers, regardless of the resizing strategy
+	for i := 0; i < pool.minWorkers; i++ {
+		pool.startIdleWorker()
 	}
 
 	return pool
@@ -216,6 +283,29 @@ func (p *WorkerPool) Stopped() bool {
 	return atomic.LoadInt32(&p.stopped) == 1
 }
 
+// Prewarm starts up to n idle workers ahead of time, regardless of the resizing strategy,
+// without exceeding the maximum number of workers. If a PrewarmRetention was configured,
+// idle workers will not be stopped until the retention period has elapsed.
+// It returns the number of workers that were started.
+func (p *WorkerPool) Prewarm(n int) int {
+	if n <= 0 || p.Stopped() {
+		return 0
+	}
+
+	if p.prewarmRetention > 0 {
+		atomic.StoreInt64(&p.prewarmDeadline, time.Now().Add(p.prewarmRetention).UnixNano())
+	}
+
+	started := 0
+	for ; started < n; started++ {
+		if !p.startIdleWorker() {
+			break
+		}
+	}
+
+	return started
+}
+
 // Submit sends a task to this worker pool for execution. If the queue is full,
 // it will wait until the task is dispatched to a worker goroutine.
 func (p *WorkerPool) Submit(task func()) {
@@ -380,6 +470,21 @@ func (p *WorkerPool) stop(waitForQueuedTasksToComplete bool) {
 
 }
 
+// watchParentContext stops the pool when the parent context is cancelled
+func (p *WorkerPool) watchParentContext() {
+	select {
+	case <-p.parentContext.Done():
+	case <-p.context.Done():
+	}
+
+	// Pool was stopped explicitly before the parent context was cancelled
+	if p.parentContext.Err() == nil {
+		return
+	}
+
+	p.stop(p.cancelPolicy == CompleteQueuedTasks)
+}
+
 // purge represents the work done by the purger goroutine
 func (p *WorkerPool) purge() {
 	defer p.workersWaitGroup.Done()
@@ -435,6 +540,17 @@ func (p *WorkerPool) maybeStartWorker(firstTask func()) bool {
 // executeTask executes the given task and updates task-related counters
 func (p *WorkerPool) executeTask(task func(), isFirstTask bool) {
 
+	// Assign an ID to the task only if lifecycle hooks need it
+	var taskID uint64
+	if p.beforeTask != nil || p.afterTask != nil {
+		taskID = atomic.AddUint64(&p.lastTaskID, 1)
+	}
+	// Set before the hooks so that the duration is valid even if the before task hook panics
+	startedAt := time.Now()
+
+	// Mark the task as done last, whatever the hooks do
+	defer p.tasksWaitGroup.Done()
+
 	defer func() {
 		if panic := recover(); panic != nil {
 			// Increment failed task count
@@ -446,7 +562,13 @@ func (p *WorkerPool) executeTask(task func(), isFirstTask bool) {
 			// Increment idle count
 			atomic.AddInt32(&p.idleWorkerCount, 1)
 		}
-		p.tasksWaitGroup.Done()
+
+		// Invoke after task hook
+		if p.afterTask != nil {
+			p.invokeHook(func() {
+				p.afterTask(taskID, time.Since(startedAt))
+			})
+		}
 	}()
 
 	// Decrement idle count
@@ -457,7 +579,15 @@ func (p *WorkerPool) executeTask(task func(), isFirstTask bool) {
 	// Decrement waiting task count
 	atomic.AddUint64(&p.waitingTaskCount, ^uint64(0))
 
+	// Invoke before task hook
+	if p.beforeTask != nil {
+		p.invokeHook(func() {
+			p.beforeTask(taskID)
+		})
+	}
+
 	// Execute task
+	startedAt = time.Now()
 	task()
 
 	// Increment successful task count
@@ -467,6 +597,17 @@ func (p *WorkerPool) executeTask(task func(), isFirstTask bool) {
 	atomic.AddInt32(&p.idleWorkerCount, 1)
 }
 
+// invokeHook calls a lifecycle hook, passing its panic to the panic handler
+// instead of failing the task
+func (p *WorkerPool) invokeHook(hook func()) {
+	defer func() {
+		if panic := recover(); panic != nil {
+			p.panicHandler(panic)
+		}
+	}()
+	hook()
+}
+
 func (p *WorkerPool) incrementWorkerCount() bool {
 
 	p.mutex.Lock()
@@ -498,12 +639,44 @@ func (p *WorkerPool) incrementWorkerCount() bool {
 	return true
 }
 
+// startIdleWorker launches an idle worker goroutine as long as the maximum number of workers
+// has not been reached, bypassing the resizing strategy
+func (p *WorkerPool) startIdleWorker() bool {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.RunningWorkers() >= p.maxWorkers {
+		return false
+	}
+
+	// Increment worker count, idle count and wait group
+	atomic.AddInt32(&p.workerCount, 1)
+	atomic.AddInt32(&p.idleWorkerCount, 1)
+	p.workersWaitGroup.Add(1)
+
+	// Launch worker goroutine
+	go worker(p.context, &p.workersWaitGroup, nil, p.tasks, p.executeTask, &p.tasksWaitGroup)
+
+	return true
+}
+
 func (p *WorkerPool) decrementWorkerCount() bool {
 
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	if p.IdleWorkers() <= 0 || p.RunningWorkers() <= p.minWorkers || p.Stopped() {
+	if p.IdleWorkers() <= 0 || p.Stopped() {
+		return false
+	}
+
+	// Never go below the configured minimum
+	if p.RunningWorkers()-1 < p.minWorkers {
+		return false
+	}
+
+	// Prewarmed workers are retained until the retention period has elapsed
+	if time.Now().UnixNano() < atomic.LoadInt64(&p.prewarmDeadline) {
 		return false
 	}
 
@@ -1,6 +1,8 @@
 package worker
 
 import (
+	"context"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -78,3 +80,213 @@ func TestPurgeDuringSubmit(t *testing.T) {
 	assertEqual(t, 0, pool.RunningWorkers())
 
 }
+
+func TestStopWhenParentContextIsCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := New(1, 10, Context(ctx))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var doneCount int32
+
+	pool.Submit(func() {
+		close(started)
+		<-release
+		atomic.AddInt32(&doneCount, 1)
+	})
+	<-started
+	for i := 0; i < 5; i++ {
+		pool.Submit(func() {
+			atomic.AddInt32(&doneCount, 1)
+		})
+	}
+
+	cancel()
+	close(release)
+
+	// Wait for the pool to stop
+	for !pool.Stopped() {
+		time.Sleep(time.Millisecond)
+	}
+	pool.StopAndWait()
+
+	assertEqual(t, false, pool.TrySubmit(func() {}))
+	assertEqual(t, int32(1), atomic.LoadInt32(&doneCount))
+}
+
+func TestCompleteQueuedTasksWhenParentContextIsCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := New(1, 10, Context(ctx), OnCancel(CompleteQueuedTasks))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var doneCount int32
+
+	pool.Submit(func() {
+		close(started)
+		<-release
+		atomic.AddInt32(&doneCount, 1)
+	})
+	<-started
+	for i := 0; i < 5; i++ {
+		pool.Submit(func() {
+			atomic.AddInt32(&doneCount, 1)
+		})
+	}
+
+	cancel()
+
+	// Wait for the pool to stop accepting tasks
+	for !pool.Stopped() {
+		time.Sleep(time.Millisecond)
+	}
+	assertEqual(t, false, pool.TrySubmit(func() {}))
+
+	close(release)
+	pool.StopAndWait()
+
+	assertEqual(t, int32(6), atomic.LoadInt32(&doneCount))
+}
+
+func TestPrewarm(t *testing.T) {
+
+	pool := New(5, 10, IdleTimeout(10*time.Millisecond))
+
+	assertEqual(t, 3, pool.Prewarm(3))
+	assertEqual(t, 3, pool.RunningWorkers())
+	assertEqual(t, 3, pool.IdleWorkers())
+
+	// Cannot exceed max workers
+	assertEqual(t, 2, pool.Prewarm(10))
+	assertEqual(t, 5, pool.RunningWorkers())
+
+	// Prewarmed workers are stopped by the idle timeout
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, true, pool.RunningWorkers() < 5)
+
+	pool.StopAndWait()
+
+	assertEqual(t, 0, pool.Prewarm(1))
+}
+
+func TestPrewarmRetention(t *testing.T) {
+
+	pool := New(5, 10, IdleTimeout(10*time.Millisecond), PrewarmRetention(time.Hour))
+
+	assertEqual(t, 4, pool.Prewarm(4))
+
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, 4, pool.RunningWorkers())
+
+	var doneCount int32
+	for i := 0; i < 4; i++ {
+		pool.Submit(func() {
+			atomic.AddInt32(&doneCount, 1)
+		})
+	}
+	pool.StopAndWait()
+
+	assertEqual(t, int32(4), atomic.LoadInt32(&doneCount))
+}
+
+type noResize struct{}
+
+func (noResize) Resize(runningWorkers, minWorkers, maxWorkers int) bool {
+	return false
+}
+
+func TestPrewarmRetentionKeepsMinWorkers(t *testing.T) {
+
+	pool := New(5, 10, MinWorkers(2), Strategy(noResize{}),
+		IdleTimeout(5*time.Millisecond), PrewarmRetention(20*time.Millisecond))
+	assertEqual(t, 2, pool.RunningWorkers())
+
+	assertEqual(t, 3, pool.Prewarm(3))
+	time.Sleep(10 * time.Millisecond)
+	assertEqual(t, 5, pool.RunningWorkers())
+
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, 2, pool.RunningWorkers())
+
+	pool.StopAndWait()
+}
+
+func TestTaskHooks(t *testing.T) {
+
+	var mutex sync.Mutex
+	started := make(map[uint64]bool)
+	durations := make(map[uint64]time.Duration)
+
+	pool := New(3, 10,
+		PanicHandler(func(interface{}) {}),
+		BeforeTask(func(taskID uint64) {
+			mutex.Lock()
+			started[taskID] = true
+			mutex.Unlock()
+		}),
+		AfterTask(func(taskID uint64, duration time.Duration) {
+			mutex.Lock()
+			durations[taskID] = duration
+			mutex.Unlock()
+		}))
+
+	for i := 0; i < 5; i++ {
+		pool.Submit(func() {
+			time.Sleep(5 * time.Millisecond)
+		})
+	}
+	pool.Submit(func() {
+		panic("failed task")
+	})
+	pool.StopAndWait()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	assertEqual(t, 6, len(started))
+	assertEqual(t, 6, len(durations))
+	for taskID := uint64(1); taskID <= 6; taskID++ {
+		assertEqual(t, true, started[taskID])
+		if _, ok := durations[taskID]; !ok {
+			t.Errorf("Expected after task hook to be invoked for task %d", taskID)
+		}
+	}
+}
+
+func TestTaskHooksPanic(t *testing.T) {
+
+	var panics int32
+	var duration time.Duration
+	pool := New(1, 10,
+		PanicHandler(func(interface{}) {
+			atomic.AddInt32(&panics, 1)
+		}),
+		BeforeTask(func(taskID uint64) {
+			if taskID == 1 {
+				panic("failed before hook")
+			}
+		}),
+		AfterTask(func(taskID uint64, d time.Duration) {
+			if taskID == 1 {
+				duration = d
+			}
+			panic("failed after hook")
+		}))
+
+	var doneCount int32
+	for i := 0; i < 2; i++ {
+		pool.Submit(func() {
+			atomic.AddInt32(&doneCount, 1)
+		})
+	}
+	pool.StopAndWait()
+
+	assertEqual(t, int32(2), atomic.LoadInt32(&doneCount))
+	assertEqual(t, int32(3), atomic.LoadInt32(&panics))
+	assertEqual(t, uint64(2), pool.SuccessfulTasks())
+	if duration <= 0 || duration > time.Minute {
+		t.Errorf("Expected a valid duration for the task whose before hook failed, got %v", duration)
+	}
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token bucket holding up to burst tokens, refilled with rate tokens per second.
+// Each event takes a token, bursts are allowed as long as tokens are left.
+// It is safe for concurrent use.
+type TokenBucket struct {
+	rate  float64
+	burst int
+	now   func() time.Time
+
+	mutex  sync.Mutex
+	tokens float64
+	// last is when tokens was last updated
+	last time.Time
+}
+
+// NewTokenBucket creates a full bucket, see Every to set the rate from an interval
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{rate: rate, burst: burst, now: time.Now, tokens: float64(burst)}
+}
+
+// Rate returns the number of tokens added per second
+func (tb *TokenBucket) Rate() float64 {
+	return tb.rate
+}
+
+// Burst returns the maximum number of tokens
+func (tb *TokenBucket) Burst() int {
+	return tb.burst
+}
+
+// Tokens returns the number of tokens available now, negative when reserved in advance
+func (tb *TokenBucket) Tokens() float64 {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+	return tb.advance(tb.now())
+}
+
+func (tb *TokenBucket) Allow() bool {
+	return tb.AllowN(1)
+}
+
+func (tb *TokenBucket) AllowN(n int) bool {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+	now := tb.now()
+	tokens := tb.advance(now)
+	if tokens < float64(n) {
+		return false
+	}
+	tb.tokens, tb.last = tokens-float64(n), now
+	return true
+}
+
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	return tb.WaitN(ctx, 1)
+}
+
+func (tb *TokenBucket) WaitN(ctx context.Context, n int) error {
+	r := tb.ReserveN(n)
+	if !r.OK() {
+		return ErrExceedsBurst
+	}
+	if err := sleep(ctx, r.Delay()); err != nil {
+		r.Cancel()
+		return err
+	}
+	return nil
+}
+
+// Reserve takes a token, possibly in advance, see ReserveN
+func (tb *TokenBucket) Reserve() *Reservation {
+	return tb.ReserveN(1)
+}
+
+// ReserveN takes n tokens, possibly in advance. The event may happen after the delay of the reservation,
+// or the reservation canceled to give the tokens back. It is not OK when n exceeds the burst,
+// or when the tokens are missing and the rate is 0.
+func (tb *TokenBucket) ReserveN(n int) *Reservation {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+	now := tb.now()
+	if n > tb.burst {
+		return &Reservation{}
+	}
+	tokens := tb.advance(now) - float64(n)
+	r := &Reservation{ok: true, tb: tb, tokens: n, at: now}
+	if tokens < 0 {
+		if tb.rate <= 0 {
+			return &Reservation{}
+		}
+		r.at = now.Add(time.Duration(-tokens / tb.rate * float64(time.Second)))
+	}
+	tb.tokens, tb.last = tokens, now
+	return r
+}
+
+// advance returns the tokens available at now, the lock must be held
+func (tb *TokenBucket) advance(now time.Time) float64 {
+	elapsed := now.Sub(tb.last)
+	if elapsed <= 0 {
+		return tb.tokens
+	}
+	return min(tb.tokens+elapsed.Seconds()*tb.rate, float64(tb.burst))
+}
+
+// Reservation holds tokens taken from a TokenBucket for an event
+type Reservation struct {
+	ok     bool
+	tb     *TokenBucket
+	tokens int
+	// at is when the tokens are available
+	at time.Time
+}
+
+// OK reports whether the tokens were reserved
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns how long to wait before the event may happen
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return 0
+	}
+	return max(r.at.Sub(r.tb.now()), 0)
+}
+
+// Cancel gives back the tokens of a reservation whose event did not happen yet, it does nothing once the delay elapsed
+func (r *Reservation) Cancel() {
+	if !r.ok {
+		return
+	}
+	tb := r.tb
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+	now := tb.now()
+	if !r.at.After(now) {
+		return
+	}
+	tb.tokens, tb.last = min(tb.advance(now)+float64(r.tokens), float64(tb.burst)), now
+	r.ok = false
+}
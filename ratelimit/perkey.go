@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PerKey holds a limiter per key, e.g. per user or per downstream host.
+// Limiters not used for the idle duration are dropped, the next use of their key starts afresh.
+// It is safe for concurrent use.
+type PerKey[K comparable] struct {
+	newLimiter func(key K) Limiter
+	idle       time.Duration
+	now        func() time.Time
+
+	mutex    sync.Mutex
+	limiters map[K]*entry
+	// swept is when the idle limiters were last dropped
+	swept time.Time
+}
+
+type entry struct {
+	limiter Limiter
+	used    time.Time
+}
+
+// NewPerKey creates keyed limiters created on first use by newLimiter, idle limiters are kept forever when idle is 0
+func NewPerKey[K comparable](newLimiter func(key K) Limiter, idle time.Duration) *PerKey[K] {
+	return &PerKey[K]{newLimiter: newLimiter, idle: idle, now: time.Now, limiters: make(map[K]*entry)}
+}
+
+// Get returns the limiter of key
+func (p *PerKey[K]) Get(key K) Limiter {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	now := p.now()
+	p.sweep(now)
+	e, ok := p.limiters[key]
+	if !ok {
+		e = &entry{limiter: p.newLimiter(key)}
+		p.limiters[key] = e
+	}
+	e.used = now
+	return e.limiter
+}
+
+// Allow reports whether an event of key may happen now
+func (p *PerKey[K]) Allow(key K) bool {
+	return p.Get(key).Allow()
+}
+
+// Wait blocks until an event of key may happen or ctx is done
+func (p *PerKey[K]) Wait(ctx context.Context, key K) error {
+	return p.Get(key).Wait(ctx)
+}
+
+// Remove drops the limiter of key
+func (p *PerKey[K]) Remove(key K) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.limiters, key)
+}
+
+// Len returns the number of limiters held
+func (p *PerKey[K]) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.sweep(p.now())
+	return len(p.limiters)
+}
+
+// sweep drops the idle limiters at most once per idle duration, the lock must be held
+func (p *PerKey[K]) sweep(now time.Time) {
+	if p.idle <= 0 || now.Sub(p.swept) < p.idle {
+		return
+	}
+	for key, e := range p.limiters {
+		if now.Sub(e.used) >= p.idle {
+			delete(p.limiters, key)
+		}
+	}
+	p.swept = now
+}
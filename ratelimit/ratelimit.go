@@ -0,0 +1,53 @@
+// Package ratelimit limits how often events happen, e.g. calls to a downstream service from a worker pool or a batcher.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrExceedsBurst is returned by Wait when n exceeds the burst or the limit, it can never be allowed
+	ErrExceedsBurst = errors.New("ratelimit: n exceeds the limit")
+	// ErrExceedsDeadline is returned by Wait when the wait would last past the deadline of the context
+	ErrExceedsDeadline = errors.New("ratelimit: wait exceeds the context deadline")
+)
+
+// Limiter is implemented by TokenBucket and SlidingWindow
+type Limiter interface {
+	// Allow reports whether an event may happen now, it is counted when it may
+	Allow() bool
+	// AllowN reports whether n events may happen now, they are counted when they may
+	AllowN(n int) bool
+	// Wait blocks until an event may happen or ctx is done
+	Wait(ctx context.Context) error
+	// WaitN blocks until n events may happen or ctx is done
+	WaitN(ctx context.Context, n int) error
+}
+
+// Every converts the interval between events into a rate per second
+func Every(interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+// sleep waits for d unless ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return ErrExceedsDeadline
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source advanced by hand
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newClock() *clock {
+	return &clock{now: time.Unix(1000, 0)}
+}
+
+func TestTokenBucket(t *testing.T) {
+	c := newClock()
+	tb := NewTokenBucket(10, 3)
+	tb.now = c.Now
+	for i := 0; i < 3; i++ {
+		if !tb.Allow() {
+			t.Fatalf("expect the burst of 3 allowed, rejected %d", i)
+		}
+	}
+	if tb.Allow() {
+		t.Error("expect rejected once the bucket is empty")
+	}
+	c.now = c.now.Add(200 * time.Millisecond)
+	if !tb.AllowN(2) || tb.Allow() {
+		t.Error("expect 2 tokens after 200ms")
+	}
+	c.now = c.now.Add(time.Hour)
+	if tokens := tb.Tokens(); tokens != 3 {
+		t.Errorf("expect the tokens capped to the burst, get %v", tokens)
+	}
+}
+
+func TestReservation(t *testing.T) {
+	c := newClock()
+	tb := NewTokenBucket(10, 2)
+	tb.now = c.Now
+	tb.AllowN(2)
+
+	r := tb.ReserveN(2)
+	if !r.OK() || r.Delay() != 200*time.Millisecond {
+		t.Fatalf("expect a delay of 200ms, get %v, %v", r.OK(), r.Delay())
+	}
+	r.Cancel()
+	if tokens := tb.Tokens(); tokens != 0 {
+		t.Errorf("expect the tokens given back, get %v", tokens)
+	}
+	if r := tb.ReserveN(3); r.OK() {
+		t.Error("expect no reservation beyond the burst")
+	}
+	if err := tb.WaitN(context.Background(), 3); err != ErrExceedsBurst {
+		t.Errorf("expect ErrExceedsBurst, get %v", err)
+	}
+
+	// a reservation is not given back once due
+	r = tb.Reserve()
+	c.now = c.now.Add(100 * time.Millisecond)
+	r.Cancel()
+	if tokens := tb.Tokens(); tokens != 0 {
+		t.Errorf("expect the token used, get %v", tokens)
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	tb := NewTokenBucket(100, 1)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := tb.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expect about 20ms of wait, get %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	tb = NewTokenBucket(1, 1)
+	tb.Allow()
+	if err := tb.Wait(ctx); err != ErrExceedsDeadline {
+		t.Errorf("expect ErrExceedsDeadline, get %v", err)
+	}
+	if tokens := tb.Tokens(); tokens > 0.1 {
+		t.Errorf("expect the failed wait not to take a token, get %v", tokens)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	c := newClock()
+	sw := NewSlidingWindow(4, time.Second)
+	sw.now = c.Now
+	for i := 0; i < 4; i++ {
+		if !sw.Allow() {
+			t.Fatalf("expect 4 events allowed, rejected %d", i)
+		}
+	}
+	if sw.Allow() {
+		t.Error("expect rejected beyond the limit")
+	}
+	// half of the previous window is still covered, it weighs 2 events
+	c.now = c.now.Add(1500 * time.Millisecond)
+	if !sw.AllowN(2) || sw.Allow() {
+		t.Error("expect 2 events allowed half a window later")
+	}
+	c.now = c.now.Add(250 * time.Millisecond)
+	if !sw.Allow() || sw.Allow() {
+		t.Error("expect 1 event allowed 3 quarters of a window later")
+	}
+	if delay := sw.take(c.now, 1); delay != 250*time.Millisecond {
+		t.Errorf("expect to wait for the next window, get %v", delay)
+	}
+	c.now = c.now.Add(3 * time.Second)
+	if !sw.AllowN(4) {
+		t.Error("expect the full limit after an idle window")
+	}
+}
+
+func TestSlidingWindowWait(t *testing.T) {
+	sw := NewSlidingWindow(2, 20*time.Millisecond)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := sw.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("expect to wait for the next window, get %v", elapsed)
+	}
+	if err := sw.WaitN(ctx, 3); err != ErrExceedsBurst {
+		t.Errorf("expect ErrExceedsBurst, get %v", err)
+	}
+}
+
+func TestPerKey(t *testing.T) {
+	c := newClock()
+	created := 0
+	p := NewPerKey(func(key string) Limiter {
+		created++
+		tb := NewTokenBucket(1, 1)
+		tb.now = c.Now
+		return tb
+	}, time.Minute)
+	p.now = c.Now
+
+	if !p.Allow("a") || p.Allow("a") || !p.Allow("b") {
+		t.Error("expect a limiter per key")
+	}
+	if p.Len() != 2 {
+		t.Errorf("expect 2 limiters, get %d", p.Len())
+	}
+	c.now = c.now.Add(30 * time.Second)
+	p.Get("a")
+	c.now = c.now.Add(40 * time.Second)
+	if p.Len() != 1 {
+		t.Errorf("expect the idle limiter dropped, get %d", p.Len())
+	}
+	p.Remove("a")
+	p.Get("a")
+	if created != 3 {
+		t.Errorf("expect the limiter created again, get %d created", created)
+	}
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SlidingWindow allows up to limit events during any window.
+// It approximates the events of the sliding window by weighting the events of the previous fixed window
+// with the part of it still covered, which only needs two counters.
+// It is safe for concurrent use.
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mutex sync.Mutex
+	// start is the start of the current fixed window
+	start    time.Time
+	current  int
+	previous int
+}
+
+// NewSlidingWindow creates a limiter allowing limit events per window
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{limit: limit, window: window, now: time.Now}
+}
+
+// Limit returns the number of events allowed per window
+func (sw *SlidingWindow) Limit() int {
+	return sw.limit
+}
+
+// Window returns the duration of the window
+func (sw *SlidingWindow) Window() time.Duration {
+	return sw.window
+}
+
+func (sw *SlidingWindow) Allow() bool {
+	return sw.AllowN(1)
+}
+
+func (sw *SlidingWindow) AllowN(n int) bool {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	return sw.take(sw.now(), n) == 0
+}
+
+func (sw *SlidingWindow) Wait(ctx context.Context) error {
+	return sw.WaitN(ctx, 1)
+}
+
+func (sw *SlidingWindow) WaitN(ctx context.Context, n int) error {
+	if n > sw.limit {
+		return ErrExceedsBurst
+	}
+	for {
+		sw.mutex.Lock()
+		delay := sw.take(sw.now(), n)
+		sw.mutex.Unlock()
+		if delay == 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// take counts n events at now when they are allowed and returns 0,
+// otherwise it returns how long to wait before trying again. The lock must be held.
+func (sw *SlidingWindow) take(now time.Time, n int) time.Duration {
+	if n > sw.limit {
+		return sw.window
+	}
+	sw.advance(now)
+	elapsed := now.Sub(sw.start)
+	weight := 1 - float64(elapsed)/float64(sw.window)
+	if float64(sw.previous)*weight+float64(sw.current+n) <= float64(sw.limit) {
+		sw.current += n
+		return 0
+	}
+	next := sw.window - elapsed
+	if room := sw.limit - sw.current - n; room >= 0 {
+		// the previous events weigh little enough once weight is room/previous
+		next = time.Duration((1-float64(room)/float64(sw.previous))*float64(sw.window)) - elapsed
+	}
+	return max(next, time.Millisecond)
+}
+
+// advance moves the fixed windows to the one containing now, the lock must be held
+func (sw *SlidingWindow) advance(now time.Time) {
+	elapsed := now.Sub(sw.start)
+	if elapsed < sw.window {
+		return
+	}
+	if elapsed < 2*sw.window {
+		sw.previous = sw.current
+	} else {
+		sw.previous = 0
+	}
+	sw.current = 0
+	sw.start = now.Truncate(sw.window)
+}
@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Strategy returns the delay to wait after the failed attempt n, starting at 1
+type Strategy func(attempt int) time.Duration
+
+// DefaultBackoff doubles a delay of 100ms after each attempt, with 20% of jitter, up to 10s
+var DefaultBackoff = Capped(Exponential(100*time.Millisecond, 2, 0.2), 10*time.Second)
+
+// Fixed waits d between attempts
+func Fixed(d time.Duration) Strategy {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// Exponential waits initial after the first attempt and multiplies the delay by factor after each other one.
+// Each delay is randomized by up to jitter times its value in both directions, 0 for none, see Jitter.
+func Exponential(initial time.Duration, factor float64, jitter float64) Strategy {
+	s := func(attempt int) time.Duration {
+		d := float64(initial) * math.Pow(factor, float64(attempt-1))
+		if d >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(d)
+	}
+	if jitter > 0 {
+		return Jitter(s, jitter)
+	}
+	return s
+}
+
+// Jitter randomizes the delays of s by up to fraction times their value in both directions,
+// so that clients failing together do not retry together
+func Jitter(s Strategy, fraction float64) Strategy {
+	return func(attempt int) time.Duration {
+		d := float64(s(attempt))
+		d += d * fraction * (2*rand.Float64() - 1)
+		if d <= 0 {
+			return 0
+		}
+		if d >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(d)
+	}
+}
+
+// Capped limits the delays of s to maxDelay
+func Capped(s Strategy, maxDelay time.Duration) Strategy {
+	return func(attempt int) time.Duration {
+		return min(s(attempt), maxDelay)
+	}
+}
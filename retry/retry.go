@@ -0,0 +1,128 @@
+// Package retry calls functions again after they failed, waiting between attempts.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultAttempts is the number of attempts made by default
+const DefaultAttempts = 3
+
+type config struct {
+	attempts int
+	backoff  Strategy
+	retryIf  func(error) bool
+	onRetry  func(attempt int, err error, delay time.Duration)
+}
+
+// Option represents an option that can be passed to Do
+type Option func(*config)
+
+// Attempts sets the maximum number of attempts, the first one included.
+// Attempts are only limited by the context when n is 0 or less.
+func Attempts(n int) Option {
+	return func(c *config) { c.attempts = n }
+}
+
+// Backoff sets the strategy computing the delays between attempts, DefaultBackoff by default
+func Backoff(s Strategy) Option {
+	return func(c *config) { c.backoff = s }
+}
+
+// RetryIf sets the predicate deciding whether an error is worth another attempt, e.g.
+//
+//	retry.RetryIf(func(err error) bool { return errors.Is(err, redis.TxFailedErr) })
+//
+// Every error but the ones marked Unrecoverable is retried by default.
+func RetryIf(retryIf func(error) bool) Option {
+	return func(c *config) { c.retryIf = retryIf }
+}
+
+// OnRetry sets a function called after each failed attempt followed by another one,
+// with the number of the attempt, its error and the delay before the next one
+func OnRetry(onRetry func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *config) { c.onRetry = onRetry }
+}
+
+type unrecoverable struct {
+	err error
+}
+
+func (u unrecoverable) Error() string {
+	return u.err.Error()
+}
+
+func (u unrecoverable) Unwrap() error {
+	return u.err
+}
+
+// Unrecoverable marks err so that it stops the attempts whatever the RetryIf predicate,
+// Do returns err itself unless the mark was wrapped in another error
+func Unrecoverable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return unrecoverable{err}
+}
+
+// Do calls fn until it succeeds, the attempts are exhausted, its error is not retried or ctx is done.
+// It returns the error of the last attempt, wrapped with the error of the context when done.
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	c := config{attempts: DefaultAttempts, backoff: DefaultBackoff}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+
+	var timer *time.Timer
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if u, ok := err.(unrecoverable); ok {
+			return u.err
+		}
+		var u unrecoverable
+		if errors.As(err, &u) {
+			return err
+		}
+		if c.retryIf != nil && !c.retryIf(err) {
+			return err
+		}
+		if c.attempts > 0 && attempt >= c.attempts {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		if c.onRetry != nil {
+			c.onRetry(attempt, err, delay)
+		}
+		if timer == nil {
+			timer = time.NewTimer(delay)
+			defer timer.Stop()
+		} else {
+			timer.Reset(delay)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: last attempt: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// DoValue is Do for functions returning a value, it returns the value of the successful attempt
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	var v T
+	err := Do(ctx, func(ctx context.Context) error {
+		var err error
+		v, err = fn(ctx)
+		return err
+	}, opts...)
+	return v, err
+}
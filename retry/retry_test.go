@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+var errTemporary = errors.New("temporary")
+
+func TestDo(t *testing.T) {
+	calls := 0
+	var delays []time.Duration
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTemporary
+		}
+		return nil
+	}, Attempts(5), Backoff(Fixed(time.Millisecond)), OnRetry(func(attempt int, err error, delay time.Duration) {
+		if attempt != len(delays)+1 || err != errTemporary {
+			t.Errorf("unexpected retry %d: %v", attempt, err)
+		}
+		delays = append(delays, delay)
+	}))
+	if err != nil || calls != 3 || len(delays) != 2 {
+		t.Errorf("expect success after 3 calls and 2 retries, get %v, %d, %v", err, calls, delays)
+	}
+}
+
+func TestDoAttempts(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return errTemporary
+	}, Attempts(4), Backoff(Fixed(0)))
+	if err != errTemporary || calls != 4 {
+		t.Errorf("expect the last error after 4 calls, get %v after %d", err, calls)
+	}
+}
+
+func TestDoRetryIf(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errTemporary
+		}
+		return io.ErrUnexpectedEOF
+	}, Attempts(0), Backoff(Fixed(0)), RetryIf(func(err error) bool { return errors.Is(err, errTemporary) }))
+	if err != io.ErrUnexpectedEOF || calls != 2 {
+		t.Errorf("expect to stop on the error not retried, get %v after %d", err, calls)
+	}
+
+	calls = 0
+	err = Do(context.Background(), func(context.Context) error {
+		calls++
+		return Unrecoverable(io.EOF)
+	}, Backoff(Fixed(0)))
+	if err != io.EOF || calls != 1 {
+		t.Errorf("expect to stop on an unrecoverable error, get %v after %d", err, calls)
+	}
+	err = Do(context.Background(), func(context.Context) error {
+		return fmt.Errorf("reading: %w", Unrecoverable(io.EOF))
+	}, Backoff(Fixed(0)))
+	if !errors.Is(err, io.EOF) || err.Error() != "reading: EOF" {
+		t.Errorf("expect the wrapped unrecoverable error, get %v", err)
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := Do(ctx, func(context.Context) error {
+		return errTemporary
+	}, Attempts(0), Backoff(Fixed(time.Hour)))
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errTemporary) {
+		t.Errorf("expect the context and last errors, get %v", err)
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	v, err := DoValue(context.Background(), func(context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errTemporary
+		}
+		return 42, nil
+	}, Backoff(Fixed(0)))
+	if v != 42 || err != nil {
+		t.Errorf("expect 42, get %d, %v", v, err)
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	exp := Exponential(100*time.Millisecond, 2, 0)
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond} {
+		if got := exp(attempt); got != want {
+			t.Errorf("Exponential(%d) expect %v, get %v", attempt, want, got)
+		}
+	}
+	if got := exp(1000); got <= 0 {
+		t.Errorf("Exponential expect no overflow, get %v", got)
+	}
+	if got := Capped(exp, time.Second)(10); got != time.Second {
+		t.Errorf("Capped expect 1s, get %v", got)
+	}
+
+	jitter := Exponential(100*time.Millisecond, 2, 0.5)
+	for i := 0; i < 100; i++ {
+		if got := jitter(2); got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("Jitter expect a delay within 50%% of 200ms, get %v", got)
+		}
+	}
+	if got := Fixed(time.Second)(7); got != time.Second {
+		t.Errorf("Fixed expect 1s, get %v", got)
+	}
+}
@@ -0,0 +1,224 @@
+package sched
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job runs
+type Schedule interface {
+	// Next returns the first time strictly after t, the zero time when there is none
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule running every interval, aligned on multiples of interval since the zero time
+// so that all instances of a fleet agree on the occurrences, see SingleRun.
+// E.g. Every(time.Minute) runs at the start of every minute, Every(24*time.Hour) at midnight UTC.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("sched: interval must be positive")
+	}
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(e)).Add(time.Duration(e))
+}
+
+// Cron is a schedule parsed from a cron expression
+type Cron struct {
+	second, minute, hour, dom, month, dow uint64
+	location                              *time.Location
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	seconds = field{"second", 0, 59, nil}
+	minutes = field{"minute", 0, 59, nil}
+	hours   = field{"hour", 0, 23, nil}
+	doms    = field{"day of month", 1, 31, nil}
+	months  = field{"month", 1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is also Sunday
+	dows = field{"day of week", 0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ErrCron is wrapped by the errors of ParseCron
+var ErrCron = errors.New("sched: invalid cron expression")
+
+// ParseCron parses a cron expression of 5 fields, minute hour day-of-month month day-of-week,
+// or of 6 fields starting with the second. Fields accept *, ?, lists, ranges, steps such as */15 or 1-30/5,
+// and the names of months and days, e.g. "0 9 * * mon-fri".
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are accepted as well.
+// The expression may be prefixed with TZ=<location>, times are in the location of the time passed to Next otherwise.
+// As with cron, a job runs when either the day of month or the day of week matches if both are restricted.
+func ParseCron(expr string) (*Cron, error) {
+	c := &Cron{}
+	expr = strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(expr, "TZ="); ok {
+		name, rest, _ := strings.Cut(rest, " ")
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrCron, expr, err)
+		}
+		c.location = loc
+		expr = strings.TrimSpace(rest)
+	}
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w %q: expect 5 or 6 fields", ErrCron, expr)
+	}
+	var err error
+	for i, f := range []struct {
+		bits  *uint64
+		field field
+	}{{&c.second, seconds}, {&c.minute, minutes}, {&c.hour, hours}, {&c.dom, doms}, {&c.month, months}, {&c.dow, dows}} {
+		if *f.bits, err = f.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrCron, expr, err)
+		}
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	return c, nil
+}
+
+// MustParseCron is ParseCron panicking on errors, for expressions known to be valid
+func MustParseCron(expr string) *Cron {
+	c, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// star marks a field left unrestricted
+const star = 1 << 63
+
+// parse returns the values of a field as bits
+func (f field) parse(s string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(s, ",") {
+		expr, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q of %s", stepText, f.name)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case expr == "*" || expr == "?":
+			lo, hi = f.min, f.max
+			if !hasStep {
+				set |= star
+			}
+		default:
+			loText, hiText, isRange := strings.Cut(expr, "-")
+			var err error
+			if lo, err = f.value(loText); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(hiText); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q of %s", expr, f.name)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+// Next returns the first time matching the expression strictly after t, the zero time when there is none
+// within 5 years, e.g. for February 30
+func (c *Cron) Next(t time.Time) time.Time {
+	orig := t.Location()
+	if c.location != nil {
+		t = t.In(c.location)
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + 5
+
+	// each mismatch moves to the start of the next unit and checks again from the largest unit
+	for t.Year() <= limit {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		case c.second&(1<<uint(t.Second())) == 0:
+			t = t.Add(time.Second)
+		default:
+			return t.In(orig)
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.dom&star != 0 || c.dow&star != 0 {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,63 @@
+package sched
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Thursday
+	from := time.Date(2024, 2, 29, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, 2, 29, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * * *", time.Date(2024, 2, 29, 10, 30, 30, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"5,45 10 * * *", time.Date(2024, 2, 29, 10, 45, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan ?", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// either the day of month or the day of week
+		{"0 0 15 * fri", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"10-20/5 * * * *", time.Date(2024, 2, 29, 11, 10, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 2, 29, 11, 0, 0, 0, time.UTC)},
+		{"30 0 * * sat", time.Date(2024, 3, 2, 0, 30, 0, 0, time.UTC)},
+		{"TZ=Asia/Shanghai 0 8 * * *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		c, err := ParseCron(test.expr)
+		if err != nil {
+			if errors.Is(err, ErrCron) && test.expr[:3] == "TZ=" {
+				t.Logf("skip %q without tzdata: %v", test.expr, err)
+				continue
+			}
+			t.Fatalf("%q: %v", test.expr, err)
+		}
+		if next := c.Next(from); !next.Equal(test.next) {
+			t.Errorf("%q expect %v, get %v", test.expr, test.next, next)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * foo *", "TZ=Nowhere/Nothing * * * * *"} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrCron) {
+			t.Errorf("%q expect ErrCron, get %v", expr, err)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	from := time.Unix(1000, 0)
+	if next := Every(time.Minute).Next(from); !next.Equal(time.Unix(1020, 0)) {
+		t.Errorf("expect the next minute, get %v", next)
+	}
+	if next := Every(time.Minute).Next(time.Unix(1020, 0)); !next.Equal(time.Unix(1080, 0)) {
+		t.Errorf("expect a minute later, get %v", next)
+	}
+}
@@ -0,0 +1,306 @@
+// Package sched runs jobs on cron expressions or fixed intervals.
+package sched
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDuplicateJob is returned by Add when a job of the same name exists
+	ErrDuplicateJob = errors.New("sched: duplicate job")
+	// ErrJobNotFound is returned for names of jobs not added
+	ErrJobNotFound = errors.New("sched: job not found")
+)
+
+// OverlapPolicy determines what happens when a job is due while its previous run is not done
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run, it's the default policy
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue runs the job again once the previous run is done, as many times as it was due meanwhile
+	OverlapQueue
+	// OverlapConcurrent runs the job alongside the previous run
+	OverlapConcurrent
+)
+
+// Locker is implemented by lock.RedisMutex, see SingleRun
+type Locker interface {
+	TryLock(key string) bool
+}
+
+// Job is a function run by the scheduler, ctx is canceled when the scheduler stops or the job times out
+type Job func(ctx context.Context) error
+
+// JobOption represents an option that can be passed to Add
+type JobOption func(*job)
+
+// Overlap sets the overlap policy of the job
+func Overlap(policy OverlapPolicy) JobOption {
+	return func(j *job) { j.overlap = policy }
+}
+
+// Jitter delays each run by a random duration up to max, e.g. to spread the jobs of a fleet
+func Jitter(max time.Duration) JobOption {
+	return func(j *job) { j.jitter = max }
+}
+
+// Timeout cancels the context of each run after timeout
+func Timeout(timeout time.Duration) JobOption {
+	return func(j *job) { j.timeout = timeout }
+}
+
+// SingleRun runs each occurrence of the job on a single instance of a fleet. Before running, the instance
+// must lock the key "sched:<name>:<scheduled unix time in nanoseconds>", which is never unlocked: the locker must let its keys expire,
+// e.g. a lock.RedisMutex created with a lock time longer than the clock skew between instances.
+// A panic of TryLock is reported to the error handler and the occurrence is skipped.
+// Jitter is added after locking, instances racing for the same occurrence agree on its time.
+func SingleRun(locker Locker) JobOption {
+	return func(j *job) { j.locker = locker }
+}
+
+// Option represents an option that can be passed to New
+type Option func(*Scheduler)
+
+// ErrorHandler sets the function receiving the errors and panics of the jobs, they are logged by default
+func ErrorHandler(handler func(name string, err error)) Option {
+	return func(s *Scheduler) { s.errorHandler = handler }
+}
+
+// Scheduler runs jobs, it is safe for concurrent use
+type Scheduler struct {
+	errorHandler func(name string, err error)
+	now          func() time.Time
+
+	mutex   sync.Mutex
+	jobs    map[string]*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+// New creates a scheduler, jobs start running once Start is called
+func New(options ...Option) *Scheduler {
+	s := &Scheduler{
+		errorHandler: func(name string, err error) {
+			log.Printf("sched: job %q: %v", name, err)
+		},
+		now:  time.Now,
+		jobs: make(map[string]*job),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       Job
+	overlap  OverlapPolicy
+	jitter   time.Duration
+	timeout  time.Duration
+	locker   Locker
+
+	stop chan struct{}
+
+	mutex   sync.Mutex
+	next    time.Time
+	active  int
+	pending int
+}
+
+// Add adds a job run on schedule, e.g. Add("gc", Every(time.Minute), gc) or Add("report", MustParseCron("0 9 * * mon"), report)
+func (s *Scheduler) Add(name string, schedule Schedule, fn Job, options ...JobOption) error {
+	j := &job{name: name, schedule: schedule, fn: fn, stop: make(chan struct{})}
+	for _, opt := range options {
+		opt(j)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("%w %q", ErrDuplicateJob, name)
+	}
+	s.jobs[name] = j
+	if s.ctx != nil {
+		s.start(j)
+	}
+	return nil
+}
+
+// Remove removes a job, its runs in progress are not canceled
+func (s *Scheduler) Remove(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrJobNotFound, name)
+	}
+	delete(s.jobs, name)
+	close(j.stop)
+	return nil
+}
+
+// Next returns when a job runs next, the zero time when the scheduler is not started
+func (s *Scheduler) Next(name string) (time.Time, error) {
+	s.mutex.Lock()
+	j, ok := s.jobs[name]
+	s.mutex.Unlock()
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w %q", ErrJobNotFound, name)
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.next, nil
+}
+
+// Start starts running the jobs until ctx is done or Stop is called, a stopped scheduler does not start again
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, j := range s.jobs {
+		s.start(j)
+	}
+}
+
+// Stop stops the scheduler, cancels the runs in progress and waits for them to return
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mutex.Unlock()
+	s.running.Wait()
+}
+
+// start runs the loop of a job, the lock must be held
+func (s *Scheduler) start(j *job) {
+	ctx := s.ctx
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.loop(ctx, j)
+	}()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	at := s.now()
+	for {
+		at = j.schedule.Next(at)
+		if at.IsZero() {
+			return
+		}
+		j.mutex.Lock()
+		j.next = at
+		j.mutex.Unlock()
+
+		timer := time.NewTimer(at.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-j.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.dispatch(ctx, j, at)
+		// occurrences missed meanwhile, e.g. while the process was suspended, are skipped
+		if now := s.now(); !j.schedule.Next(at).After(now) {
+			at = now
+		}
+	}
+}
+
+// dispatch applies the overlap policy to an occurrence due at
+func (s *Scheduler) dispatch(ctx context.Context, j *job, at time.Time) {
+	if j.locker != nil {
+		locked, err := s.tryLock(j, at)
+		if err != nil {
+			s.errorHandler(j.name, err)
+		}
+		if !locked {
+			return
+		}
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.active > 0 {
+		switch j.overlap {
+		case OverlapSkip:
+			return
+		case OverlapQueue:
+			j.pending++
+			return
+		}
+	}
+	j.active++
+	s.running.Add(1)
+	go s.run(ctx, j)
+}
+
+// tryLock locks the occurrence due at, lock.RedisMutex panics on Redis errors which skip the occurrence
+func (s *Scheduler) tryLock(j *job, at time.Time) (locked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("lock panic: %v", r)
+		}
+	}()
+	return j.locker.TryLock("sched:" + j.name + ":" + strconv.FormatInt(at.UnixNano(), 10)), nil
+}
+
+// run runs a job, then the runs queued meanwhile
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	defer s.running.Done()
+	for {
+		if j.jitter > 0 {
+			timer := time.NewTimer(rand.N(j.jitter))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() == nil {
+			if err := s.call(ctx, j); err != nil {
+				s.errorHandler(j.name, err)
+			}
+		}
+
+		j.mutex.Lock()
+		if j.pending == 0 || ctx.Err() != nil {
+			j.pending = 0
+			j.active--
+			j.mutex.Unlock()
+			return
+		}
+		j.pending--
+		j.mutex.Unlock()
+	}
+}
+
+func (s *Scheduler) call(ctx context.Context, j *job) (err error) {
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.fn(ctx)
+}
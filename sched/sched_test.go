@@ -0,0 +1,224 @@
+package sched
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	s := New()
+	var runs atomic.Int32
+	if err := s.Add("tick", Every(10*time.Millisecond), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("tick", Every(time.Second), nil); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("expect ErrDuplicateJob, get %v", err)
+	}
+	s.Start(context.Background())
+	time.Sleep(55 * time.Millisecond)
+	if next, err := s.Next("tick"); err != nil || next.IsZero() {
+		t.Errorf("expect the next run, get %v, %v", next, err)
+	}
+	s.Stop()
+	if n := runs.Load(); n < 3 || n > 6 {
+		t.Errorf("expect about 5 runs, get %d", n)
+	}
+	n := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	if runs.Load() != n {
+		t.Error("expect no run after Stop")
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	for _, test := range []struct {
+		policy   OverlapPolicy
+		min, max int32
+		parallel bool
+	}{
+		{OverlapSkip, 1, 3, false},
+		{OverlapQueue, 4, 10, false},
+		{OverlapConcurrent, 4, 10, true},
+	} {
+		s := New()
+		var runs, active, parallel atomic.Int32
+		release := make(chan struct{})
+		s.Add("slow", Every(5*time.Millisecond), func(ctx context.Context) error {
+			if active.Add(1) > 1 {
+				parallel.Store(1)
+			}
+			defer active.Add(-1)
+			runs.Add(1)
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return nil
+		}, Overlap(test.policy))
+		s.Start(context.Background())
+		time.Sleep(30 * time.Millisecond)
+		if err := s.Remove("slow"); err != nil {
+			t.Fatal(err)
+		}
+		close(release)
+		time.Sleep(10 * time.Millisecond)
+		s.Stop()
+		if n := runs.Load(); n < test.min || n > test.max || (parallel.Load() == 1) != test.parallel {
+			t.Errorf("policy %d expect %d-%d runs, parallel %v, get %d, %v", test.policy, test.min, test.max, test.parallel, n, parallel.Load() == 1)
+		}
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	var mutex sync.Mutex
+	errs := map[string]error{}
+	s := New(ErrorHandler(func(name string, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if errs[name] == nil {
+			errs[name] = err
+		}
+	}))
+	s.Add("fail", Every(5*time.Millisecond), func(ctx context.Context) error {
+		return errors.New("failed")
+	})
+	s.Add("panic", Every(5*time.Millisecond), func(ctx context.Context) error {
+		panic("boom")
+	})
+	s.Add("timeout", Every(5*time.Millisecond), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, Timeout(time.Millisecond))
+	s.Start(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if err := errs["fail"]; err == nil || err.Error() != "failed" {
+		t.Errorf("expect the error of the job, get %v", err)
+	}
+	if err := errs["panic"]; err == nil || err.Error() != "panic: boom" {
+		t.Errorf("expect the panic of the job, get %v", err)
+	}
+	if err := errs["timeout"]; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect the job timed out, get %v", err)
+	}
+}
+
+// locker is an in-memory Locker shared by the schedulers of a test
+type locker struct {
+	mutex     sync.Mutex
+	keys      map[string]bool
+	contended int
+}
+
+func (l *locker) TryLock(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.keys[key] {
+		l.contended++
+		return false
+	}
+	l.keys[key] = true
+	return true
+}
+
+func TestSingleRun(t *testing.T) {
+	l := &locker{keys: map[string]bool{}}
+	var runs atomic.Int32
+	var schedulers []*Scheduler
+	for i := 0; i < 3; i++ {
+		s := New()
+		s.Add("once", MustParseCron("* * * * * *"), func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}, SingleRun(l), Jitter(time.Millisecond))
+		schedulers = append(schedulers, s)
+	}
+	for _, s := range schedulers {
+		s.Start(context.Background())
+	}
+	time.Sleep(1100 * time.Millisecond)
+	for _, s := range schedulers {
+		s.Stop()
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if n := runs.Load(); n != int32(len(l.keys)) || n < 1 {
+		t.Errorf("expect a run per occurrence, get %d runs for %d occurrences", n, len(l.keys))
+	}
+}
+
+func TestSingleRunEvery(t *testing.T) {
+	l := &locker{keys: map[string]bool{}}
+	var runs atomic.Int32
+	var schedulers []*Scheduler
+	for i := 0; i < 3; i++ {
+		s := New()
+		s.Add("every", Every(50*time.Millisecond), func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}, SingleRun(l))
+		schedulers = append(schedulers, s)
+	}
+	// instances started at different times still agree on the occurrences
+	for _, s := range schedulers {
+		s.Start(context.Background())
+		time.Sleep(7 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+	for _, s := range schedulers {
+		s.Stop()
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if n := runs.Load(); n != int32(len(l.keys)) || n < 1 {
+		t.Errorf("expect a run per occurrence, get %d runs for %d occurrences", n, len(l.keys))
+	}
+	if l.contended == 0 {
+		t.Error("expect the instances to contend for the same occurrences")
+	}
+}
+
+type panicLocker struct{}
+
+func (panicLocker) TryLock(key string) bool {
+	panic("connection refused")
+}
+
+func TestSingleRunLockPanic(t *testing.T) {
+	var runs atomic.Int32
+	errs := make(chan error, 10)
+	s := New(ErrorHandler(func(name string, err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	s.Add("locked", Every(20*time.Millisecond), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, SingleRun(panicLocker{}))
+	s.Start(context.Background())
+	var err error
+	select {
+	case err = <-errs:
+	case <-time.After(time.Second):
+	}
+	s.Stop()
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expect the lock panic to be reported, get %v", err)
+	}
+	if n := runs.Load(); n != 0 {
+		t.Errorf("expect no run without the lock, get %d", n)
+	}
+}
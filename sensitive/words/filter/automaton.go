@@ -0,0 +1,242 @@
+package filter
+
+import (
+	"unicode/utf8"
+)
+
+// Automaton is an Aho-Corasick automaton compiled from a sensitive words tree.
+// It finds every word in a single pass over the text, whatever the size of the dictionary.
+// Words must be contiguous, as with StrictContains and StrictReplace.
+//
+// An Automaton is immutable and safe for concurrent use, compile it again once the tree changed.
+type Automaton struct {
+	stripSpace  bool
+	normalizers []Normalizer
+	policy      policy
+	states      []state
+	// whitelist is compiled from the whitelist of the filter, nil when empty
+	whitelist *Automaton
+	// maxDepth is the length of the longest word
+	maxDepth int
+}
+
+type state struct {
+	next  map[rune]int32
+	fail  int32
+	depth int32
+	// out is the nearest state ending a word through the failure links, 0 when none
+	out          int32
+	placeholders string
+	word         string
+	category     string
+	level        int
+}
+
+func (s *state) isWord() bool {
+	return s.placeholders != ""
+}
+
+// Compile builds an Automaton from the words of root, the whitelist and the actions of the filter
+func (wf *WordsFilter) Compile(root map[string]*Node) *Automaton {
+	wf.mutex.RLock()
+	defer wf.mutex.RUnlock()
+
+	a := wf.compile(root)
+	a.policy = wf.policy.clone()
+	a.normalizers = wf.normalizers
+	if len(wf.whitelist) > 0 {
+		a.whitelist = wf.compile(wf.whitelist)
+	}
+	return a
+}
+
+func (wf *WordsFilter) compile(root map[string]*Node) *Automaton {
+	a := &Automaton{stripSpace: wf.StripSpace, states: []state{{next: map[rune]int32{}}}}
+	// breadth first so that failure links point to states already linked
+	type item struct {
+		state    int32
+		prefix   []rune
+		children map[string]*Node
+	}
+	queue := []item{{0, nil, root}}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for key, n := range parent.children {
+			r, _ := utf8.DecodeRuneInString(key)
+			prefix := append(parent.prefix[:len(parent.prefix):len(parent.prefix)], r)
+			child := int32(len(a.states))
+			a.states = append(a.states, state{
+				next:         map[rune]int32{},
+				depth:        a.states[parent.state].depth + 1,
+				placeholders: n.Placeholders,
+				category:     n.Category,
+				level:        n.Level,
+			})
+			if n.Placeholders != "" {
+				a.states[child].word = string(prefix)
+			}
+			a.states[parent.state].next[r] = child
+			a.link(parent.state, child, r)
+			if len(n.Child) > 0 {
+				queue = append(queue, item{child, prefix, n.Child})
+			}
+		}
+	}
+	a.setMaxDepth()
+	return a
+}
+
+func (a *Automaton) setMaxDepth() {
+	a.maxDepth = 0
+	for i := range a.states {
+		a.maxDepth = max(a.maxDepth, int(a.states[i].depth))
+	}
+}
+
+// link sets the failure and output links of child, reached from parent with r
+func (a *Automaton) link(parent, child int32, r rune) {
+	if parent != 0 {
+		f := a.states[parent].fail
+		for {
+			if next, ok := a.states[f].next[r]; ok {
+				a.states[child].fail = next
+				break
+			}
+			if f == 0 {
+				break
+			}
+			f = a.states[f].fail
+		}
+	}
+	fail := &a.states[a.states[child].fail]
+	if a.states[child].fail != 0 && fail.isWord() {
+		a.states[child].out = a.states[child].fail
+	} else {
+		a.states[child].out = fail.out
+	}
+}
+
+// match calls f with the rune offsets and the final state of every word found in text,
+// ordered by end offset, until f returns false
+func (a *Automaton) match(text []rune, f func(start, end int, s *state) bool) {
+	current := int32(0)
+	for i, r := range text {
+		for {
+			if next, ok := a.states[current].next[r]; ok {
+				current = next
+				break
+			}
+			if current == 0 {
+				break
+			}
+			current = a.states[current].fail
+		}
+
+		found := current
+		if !a.states[found].isWord() {
+			found = a.states[found].out
+		}
+		for found != 0 {
+			s := &a.states[found]
+			if !f(i+1-int(s.depth), i+1, s) {
+				return
+			}
+			found = s.out
+		}
+	}
+}
+
+// whitelisted is WordsFilter.whitelisted for the compiled whitelist
+func (a *Automaton) whitelisted(textr []rune) func(start, end int) bool {
+	if a.whitelist == nil {
+		return nil
+	}
+	reach := make([]int, len(textr))
+	a.whitelist.match(textr, func(start, end int, s *state) bool {
+		reach[start] = max(reach[start], end)
+		return true
+	})
+	for i := 1; i < len(reach); i++ {
+		reach[i] = max(reach[i], reach[i-1])
+	}
+	return covered(reach)
+}
+
+// Contains reports whether text contains a sensitive word, words of ActionLog categories are ignored
+func (a *Automaton) Contains(text string) bool {
+	textr := newInput(text, a.stripSpace, a.normalizers).runes
+	skip := a.whitelisted(textr)
+	found := false
+	a.match(textr, func(start, end int, s *state) bool {
+		if skip != nil && skip(start, end) {
+			return true
+		}
+		found = a.policy.actions[s.category] != ActionLog
+		if found {
+			a.policy.counts.inc(s.word)
+		}
+		return !found
+	})
+	return found
+}
+
+// find returns the words of textr which do not overlap, the leftmost and then longest first
+func (a *Automaton) find(textr []rune) []hit {
+	type span struct {
+		end int
+		s   *state
+	}
+	skip := a.whitelisted(textr)
+	// the longest word starting at each offset
+	longest := make(map[int]span)
+	a.match(textr, func(start, end int, s *state) bool {
+		if skip != nil && skip(start, end) {
+			return true
+		}
+		if end > longest[start].end {
+			longest[start] = span{end, s}
+		}
+		return true
+	})
+	if len(longest) == 0 {
+		return nil
+	}
+
+	hits := make([]hit, 0, len(longest))
+	for i := 0; i < len(textr); i++ {
+		if sp, ok := longest[i]; ok {
+			hits = append(hits, hit{i, sp.end, sp.s.word, sp.s.placeholders, sp.s.category, sp.s.level})
+			i = sp.end - 1
+		}
+	}
+	return hits
+}
+
+// Replace replaces sensitive words with their placeholders, the leftmost and then longest words first
+func (a *Automaton) Replace(text string) string {
+	replaced, _, _ := a.Filter(text)
+	return replaced
+}
+
+// Filter applies the actions of the categories to the sensitive words of text, see WordsFilter.Filter
+func (a *Automaton) Filter(text string) (string, []Match, error) {
+	in := newInput(text, a.stripSpace, a.normalizers)
+	replaced, matches, blocked := a.policy.apply(in, a.find(in.runes))
+	a.policy.logAll(matches)
+	if blocked {
+		return replaced, matches, ErrBlocked
+	}
+	return replaced, matches, nil
+}
+
+// FindAll returns the sensitive words of text in order, see WordsFilter.FindAll
+func (a *Automaton) FindAll(text string) []Match {
+	in := newInput(text, a.stripSpace, a.normalizers)
+	var matches []Match
+	for _, h := range a.find(in.runes) {
+		a.policy.counts.inc(h.word)
+		matches = append(matches, h.match(in, a.policy.actions[h.category]))
+	}
+	return matches
+}
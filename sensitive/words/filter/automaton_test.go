@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAutomaton(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"he", "she", "his", "hers", "妲己", "アンジェラ"})
+	a := wf.Compile(root)
+
+	if a.Contains("アン") {
+		t.Errorf("Contains expect false")
+	}
+	if !a.Contains("->アンジェラ2333") {
+		t.Errorf("Contains expect true")
+	}
+	if a.Contains("妲xxxxx己") {
+		t.Errorf("Contains expect false for a non contiguous word")
+	}
+
+	tests := []struct {
+		text, want string
+	}{
+		{"ushers", "u***rs"},
+		{"ahishers", "a*******"},
+		{"i like 妲己!", "ilike**!"},
+		{"nothing", "nothing"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := a.Replace(tt.text); got != tt.want {
+			t.Errorf("Replace(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAutomatonWithFile(t *testing.T) {
+	wf := New()
+	root, err := wf.GenerateWithFile("./words_test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := wf.Compile(root)
+	text := "妲己，己姓，字妲，為中國商朝最後一位君主帝辛的王后"
+	if got, want := a.Replace(text), "**，己姓，字妲，為中國商朝最後一位君主帝辛的王后"; got != want {
+		t.Errorf("Replace = %q, want %q", got, want)
+	}
+	if !a.Contains(text) {
+		t.Errorf("Contains expect true")
+	}
+}
+
+func BenchmarkAutomatonReplace(b *testing.B) {
+	wf := New()
+	words := make([]string, 0, 10000)
+	for i := range 10000 {
+		words = append(words, fmt.Sprintf("word%dx", i))
+	}
+	a := wf.Compile(wf.Generate(words))
+	text := "some text with word42x and word9999x inside and nothing else to see here"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Replace(text)
+	}
+}
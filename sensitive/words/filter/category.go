@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"errors"
+	"log"
+	"maps"
+	"strings"
+)
+
+// ErrBlocked is returned by Filter for texts containing words of a category blocked with ActionBlock
+var ErrBlocked = errors.New("filter: text contains blocked words")
+
+// Action determines what the filter does with the words of a category
+type Action int
+
+const (
+	// ActionMask replaces the words with their placeholders. It's the default action.
+	ActionMask Action = iota
+	// ActionBlock replaces the words like ActionMask, Filter also rejects the text.
+	ActionBlock
+	// ActionLog only passes the words to the log handler, they are neither replaced nor contained.
+	ActionLog
+)
+
+// Word is a sensitive word with its category, e.g. porn, politics or ads, and its severity level
+type Word struct {
+	Text     string
+	Category string
+	Level    int
+}
+
+// GenerateWords converts sensitive words with their categories into sensitive word tree nodes
+func (wf *WordsFilter) GenerateWords(words []Word) map[string]*Node {
+	root := make(map[string]*Node)
+	wf.AddWords(words, root)
+	return root
+}
+
+// AddWords adds sensitive words with their categories at once, see AddAll.
+// The category of a word added again is overwritten.
+func (wf *WordsFilter) AddWords(words []Word, root map[string]*Node) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	for _, word := range words {
+		if n := wf.node.add(wf.word(word.Text), root, wf.Placeholder); n != nil {
+			n.Category, n.Level = word.Category, word.Level
+		}
+	}
+}
+
+// SetAction sets the action applied to the words of category
+func (wf *WordsFilter) SetAction(category string, action Action) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	if wf.policy.actions == nil {
+		wf.policy.actions = make(map[string]Action)
+	}
+	wf.policy.actions[category] = action
+}
+
+// SetLogHandler sets the function receiving the words of ActionLog categories found by Replace and Filter,
+// they are written to the standard logger by default
+func (wf *WordsFilter) SetLogHandler(handler func(Match)) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	wf.policy.logHandler = handler
+}
+
+// Filter applies the actions of the categories to the sensitive words of text.
+// It returns the text with the words of ActionMask and ActionBlock categories replaced, all the words found,
+// and ErrBlocked when some of them belong to ActionBlock categories.
+func (wf *WordsFilter) Filter(text string, root map[string]*Node) (string, []Match, error) {
+	return wf.filter(text, root, false)
+}
+
+func (wf *WordsFilter) StrictFilter(text string, root map[string]*Node) (string, []Match, error) {
+	return wf.filter(text, root, true)
+}
+
+func (wf *WordsFilter) filter(text string, root map[string]*Node, strict bool) (string, []Match, error) {
+	wf.mutex.RLock()
+	in := newInput(text, wf.StripSpace, wf.normalizers)
+	var hits []hit
+	wf.find(in.runes, root, strict, func(h hit) bool {
+		hits = append(hits, h)
+		return true
+	})
+	p := wf.policy
+	wf.mutex.RUnlock()
+
+	replaced, matches, blocked := p.apply(in, hits)
+	p.logAll(matches)
+	if blocked {
+		return replaced, matches, ErrBlocked
+	}
+	return replaced, matches, nil
+}
+
+// policy holds the actions of the categories and how words are replaced
+type policy struct {
+	actions    map[string]Action
+	logHandler func(Match)
+	replacer   Replacer
+	// counts are shared with the automata compiled by the filter
+	counts *wordCounts
+}
+
+func (p policy) clone() policy {
+	p.actions = maps.Clone(p.actions)
+	return p
+}
+
+// hit is a sensitive word found in runes
+type hit struct {
+	start, end   int
+	word         string
+	placeholders string
+	category     string
+	level        int
+}
+
+// apply replaces the hits of the original text according to the actions of their categories,
+// it returns them as matches and whether some are blocked
+func (p policy) apply(in input, hits []hit) (string, []Match, bool) {
+	var (
+		bf      strings.Builder
+		matches []Match
+		blocked bool
+	)
+	s := 0
+	for _, h := range hits {
+		p.counts.inc(h.word)
+		m := h.match(in, p.actions[h.category])
+		matches = append(matches, m)
+		switch m.Action {
+		case ActionLog:
+			continue
+		case ActionBlock:
+			blocked = true
+		}
+		// words normalized from the same character may overlap in the original text
+		start := max(m.Start, s)
+		bf.WriteString(in.text(s, start))
+		if p.replacer != nil {
+			bf.WriteString(p.replacer(in.text(start, m.End)))
+		} else {
+			bf.WriteString(h.placeholders)
+		}
+		s = m.End
+	}
+	bf.WriteString(in.text(s, len(in.orig)))
+	return bf.String(), matches, blocked
+}
+
+// logAll passes the matches of ActionLog categories to the log handler
+func (p policy) logAll(matches []Match) {
+	for _, m := range matches {
+		if m.Action != ActionLog {
+			continue
+		}
+		if p.logHandler != nil {
+			p.logHandler(m)
+		} else {
+			log.Printf("filter: sensitive word %q of category %q at %d-%d", m.Word, m.Category, m.Start, m.End)
+		}
+	}
+}
+
+func (h hit) match(in input, action Action) Match {
+	start, end := in.span(h.start, h.end)
+	return Match{
+		Word:     h.word,
+		Category: h.category,
+		Level:    h.level,
+		Action:   action,
+		Start:    start,
+		End:      end,
+	}
+}
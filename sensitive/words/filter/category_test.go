@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCategories(t *testing.T) {
+	wf := New()
+	root := wf.GenerateWords([]Word{
+		{Text: "妲己", Category: "porn", Level: 3},
+		{Text: "buy now", Category: "ads", Level: 1},
+		{Text: "damn", Category: "swear", Level: 2},
+	})
+	wf.Add("foo", root)
+	wf.SetAction("porn", ActionBlock)
+	wf.SetAction("swear", ActionLog)
+	var logged []Match
+	wf.SetLogHandler(func(m Match) { logged = append(logged, m) })
+
+	text := "damn, buy now 妲己 foo"
+	replaced, matches, err := wf.StrictFilter(text, root)
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("StrictFilter expect ErrBlocked, get %v", err)
+	}
+	if replaced != "damn,***********" {
+		t.Errorf("StrictFilter expect damn,***********, get %q", replaced)
+	}
+	want := []Match{
+		{Word: "damn", Category: "swear", Level: 2, Action: ActionLog, Start: 0, End: 4},
+		{Word: "buynow", Category: "ads", Level: 1, Action: ActionMask, Start: 6, End: 13},
+		{Word: "妲己", Category: "porn", Level: 3, Action: ActionBlock, Start: 14, End: 16},
+		{Word: "foo", Start: 17, End: 20},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("StrictFilter expect %v, get %v", want, matches)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("match %d expect %v, get %v", i, want[i], matches[i])
+		}
+	}
+	if len(logged) != 1 || logged[0] != want[0] {
+		t.Errorf("expect %v logged, get %v", want[0], logged)
+	}
+
+	a := wf.Compile(root)
+	if got := a.FindAll(text); len(got) != 4 || got[2] != want[2] {
+		t.Errorf("Automaton FindAll expect %v, get %v", want, got)
+	}
+	if got, _, err := a.Filter(text); got != replaced || !errors.Is(err, ErrBlocked) {
+		t.Errorf("Automaton Filter expect %q, ErrBlocked, get %q, %v", replaced, got, err)
+	}
+	if wf.StrictContains("damn it", root) || a.Contains("damn it") {
+		t.Error("Contains expect false for words which are only logged")
+	}
+	if got := wf.StrictReplace("damn ads, buy now", root); got != "damnads,******" {
+		t.Errorf("StrictReplace expect damnads,******, get %q", got)
+	}
+
+	wf.Remove("妲己", root)
+	wf.Add("妲己", root)
+	if _, _, err := wf.Filter("妲己", root); err != nil {
+		t.Errorf("Filter expect no error once the category is removed, get %v", err)
+	}
+}
@@ -1,21 +1,27 @@
 package filter
 
 import (
-	"bufio"
 	"bytes"
-	"io"
 	"os"
 	"strings"
 	"sync"
+	"unicode"
 )
 
 var DefaultPlaceholder = "*"
 var DefaultStripSpace = true
 
+// WordsFilter matches sensitive words against the trees built by Generate.
+// Its methods are safe for concurrent use, words can be added to or removed from a tree
+// while it is searched as long as every access goes through the same filter.
 type WordsFilter struct {
 	Placeholder string
 	StripSpace  bool
 	node        *Node
+	whitelist   map[string]*Node
+	normalizers []Normalizer
+	noise       func(r rune) bool
+	policy      policy
 	mutex       sync.RWMutex
 }
 
@@ -25,15 +31,15 @@ func New() *WordsFilter {
 		Placeholder: DefaultPlaceholder,
 		StripSpace:  DefaultStripSpace,
 		node:        NewNode(make(map[string]*Node), ""),
+		whitelist:   make(map[string]*Node),
+		policy:      policy{counts: newWordCounts()},
 	}
 }
 
 // Convert sensitive text lists into sensitive word tree nodes
 func (wf *WordsFilter) Generate(texts []string) map[string]*Node {
 	root := make(map[string]*Node)
-	for _, text := range texts {
-		wf.Add(text, root)
-	}
+	wf.AddAll(texts, root)
 	return root
 }
 
@@ -45,84 +51,218 @@ func (wf *WordsFilter) GenerateWithFile(path string) (map[string]*Node, error) {
 		return nil, err
 	}
 	defer fd.Close()
-	buf := bufio.NewReader(fd)
-	var texts []string
-	for {
-		line, _, err := buf.ReadLine()
-		if err != nil {
-			if err == io.EOF {
-				break
-			} else {
-				return nil, err
-			}
-		}
-		text := strings.TrimSpace(string(line))
-		if text == "" {
-			continue
-		}
-		texts = append(texts, text)
-	}
-
-	root := wf.Generate(texts)
-	return root, nil
+	return wf.GenerateFromReader(fd)
 }
 
 // Add sensitive words to specified sensitive words Map.
 func (wf *WordsFilter) Add(text string, root map[string]*Node) {
-	if wf.StripSpace {
-		text = stripSpace(text)
-	}
 	wf.mutex.Lock()
 	defer wf.mutex.Unlock()
-	wf.node.add(text, root, wf.Placeholder)
+	wf.node.add(wf.word(text), root, wf.Placeholder)
+}
+
+// AddAll adds sensitive words to specified sensitive words Map at once,
+// concurrent searches see either none or all of them.
+func (wf *WordsFilter) AddAll(texts []string, root map[string]*Node) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	for _, text := range texts {
+		wf.node.add(wf.word(text), root, wf.Placeholder)
+	}
 }
 
 // Replace sensitive words in strings and return new strings.
 func (wf *WordsFilter) Replace(text string, root map[string]*Node) string {
-	if wf.StripSpace {
-		text = stripSpace(text)
-	}
-	wf.mutex.RLock()
-	defer wf.mutex.RUnlock()
-	return wf.node.replace(text, root, false)
+	return wf.replace(text, root, false)
 }
 
 func (wf *WordsFilter) StrictReplace(text string, root map[string]*Node) string {
-	if wf.StripSpace {
-		text = stripSpace(text)
-	}
-	wf.mutex.RLock()
-	defer wf.mutex.RUnlock()
-	return wf.node.replace(text, root, true)
+	return wf.replace(text, root, true)
 }
 
-// Whether the string contains sensitive words.
+func (wf *WordsFilter) replace(text string, root map[string]*Node, strict bool) string {
+	replaced, _, _ := wf.filter(text, root, strict)
+	return replaced
+}
+
+// Whether the string contains sensitive words, words of ActionLog categories are ignored.
 func (wf *WordsFilter) Contains(text string, root map[string]*Node) bool {
-	if wf.StripSpace {
-		text = stripSpace(text)
-	}
+	return wf.contains(text, root, false)
+}
+
+func (wf *WordsFilter) StrictContains(text string, root map[string]*Node) bool {
+	return wf.contains(text, root, true)
+}
+
+func (wf *WordsFilter) contains(text string, root map[string]*Node, strict bool) bool {
 	wf.mutex.RLock()
 	defer wf.mutex.RUnlock()
-	return wf.node.contains(text, root, false)
+	found := false
+	wf.find(newInput(text, wf.StripSpace, wf.normalizers).runes, root, strict, func(h hit) bool {
+		found = wf.policy.actions[h.category] != ActionLog
+		if found {
+			wf.policy.counts.inc(h.word)
+		}
+		return !found
+	})
+	return found
 }
 
-func (wf *WordsFilter) StrictContains(text string, root map[string]*Node) bool {
-	if wf.StripSpace {
-		text = stripSpace(text)
+// find calls f with the sensitive words of textr until f returns false, the read lock must be held
+func (wf *WordsFilter) find(textr []rune, root map[string]*Node, strict bool, f func(h hit) bool) {
+	var noise func(r rune) bool
+	if !strict {
+		noise = wf.noise
+		if noise == nil {
+			noise = anyNoise
+		}
 	}
+	wf.node.find(textr, root, noise, wf.whitelisted(textr), func(start, end int, word []rune, n *Node) bool {
+		return f(hit{start, end, string(word), n.Placeholders, n.Category, n.Level})
+	})
+}
+
+// AddWhitelist adds words in which sensitive words are not matched,
+// e.g. "assistant" keeps "ass" from being matched inside it.
+// A sensitive word is only skipped when it lies wholly inside a whitelisted word.
+func (wf *WordsFilter) AddWhitelist(texts ...string) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	if wf.whitelist == nil {
+		wf.whitelist = make(map[string]*Node)
+	}
+	for _, text := range texts {
+		wf.node.add(wf.word(text), wf.whitelist, wf.Placeholder)
+	}
+}
+
+// RemoveWhitelist removes words from the whitelist
+func (wf *WordsFilter) RemoveWhitelist(texts ...string) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	for _, text := range texts {
+		wf.node.remove(wf.word(text), wf.whitelist)
+	}
+}
+
+// whitelisted returns a function reporting whether the runes of textr from start to end
+// lie inside a whitelisted word, nil when there is no whitelist
+func (wf *WordsFilter) whitelisted(textr []rune) func(start, end int) bool {
+	if len(wf.whitelist) == 0 {
+		return nil
+	}
+	// reach is the furthest end of the whitelisted words starting at or before each offset
+	reach := make([]int, len(textr))
+	for i := range textr {
+		words := wf.whitelist
+		for j := i; j < len(textr) && words != nil; j++ {
+			n, ok := words[string(textr[j])]
+			if !ok {
+				break
+			}
+			if n.Placeholders != "" {
+				reach[i] = j + 1
+			}
+			words = n.Child
+		}
+		if i > 0 {
+			reach[i] = max(reach[i], reach[i-1])
+		}
+	}
+	return covered(reach)
+}
+
+func covered(reach []int) func(start, end int) bool {
+	return func(start, end int) bool {
+		return reach[start] >= end
+	}
+}
+
+// Match is a sensitive word found in a text
+type Match struct {
+	// Word is the sensitive word, without the characters skipped by non-strict matching
+	Word string
+	// Category and Level of the word, empty when it has none
+	Category string
+	Level    int
+	// Action of the category
+	Action Action
+	// Start and End are the rune offsets of the match in the original text, spaces included
+	Start int
+	End   int
+}
+
+// FindAll returns the sensitive words of text in order, words may contain other characters as with Replace.
+func (wf *WordsFilter) FindAll(text string, root map[string]*Node) []Match {
+	return wf.findAll(text, root, false)
+}
+
+// StrictFindAll returns the sensitive words of text in order, words must be contiguous as with StrictReplace.
+func (wf *WordsFilter) StrictFindAll(text string, root map[string]*Node) []Match {
+	return wf.findAll(text, root, true)
+}
+
+func (wf *WordsFilter) findAll(text string, root map[string]*Node, strict bool) []Match {
 	wf.mutex.RLock()
 	defer wf.mutex.RUnlock()
-	return wf.node.contains(text, root, true)
+	in := newInput(text, wf.StripSpace, wf.normalizers)
+	var matches []Match
+	wf.find(in.runes, root, strict, func(h hit) bool {
+		wf.policy.counts.inc(h.word)
+		matches = append(matches, h.match(in, wf.policy.actions[h.category]))
+		return true
+	})
+	return matches
 }
 
 // Remove specified sensitive words from sensitive word map.
 func (wf *WordsFilter) Remove(text string, root map[string]*Node) {
-	if wf.StripSpace {
-		text = stripSpace(text)
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	wf.node.remove(wf.word(text), root)
+}
+
+// RemoveAll removes sensitive words from specified sensitive words Map at once,
+// their branches are left in the tree until Compact.
+func (wf *WordsFilter) RemoveAll(texts []string, root map[string]*Node) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	for _, text := range texts {
+		wf.node.remove(wf.word(text), root)
 	}
+}
+
+// Compact prunes the branches of root which no longer lead to a word after removals,
+// it returns the number of nodes pruned.
+func (wf *WordsFilter) Compact(root map[string]*Node) int {
 	wf.mutex.Lock()
 	defer wf.mutex.Unlock()
-	wf.node.remove(text, root)
+	return wf.node.compact(root)
+}
+
+// SetNoise sets the characters that Replace, Contains, FindAll and Filter skip inside words,
+// e.g. SetNoise(NoisePunct) matches "妲.己" but not "妲x己". Any character is skipped when noise is nil, the default.
+// The Strict methods never skip characters.
+func (wf *WordsFilter) SetNoise(noise func(r rune) bool) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	wf.noise = noise
+}
+
+// NoiseChars returns a noise function skipping the characters of chars
+func NoiseChars(chars string) func(r rune) bool {
+	return func(r rune) bool {
+		return strings.ContainsRune(chars, r)
+	}
+}
+
+// NoisePunct skips punctuation, symbols and spaces
+func NoisePunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+}
+
+func anyNoise(rune) bool {
+	return true
 }
 
 // Strip space
@@ -1,6 +1,9 @@
 package filter
 
 import (
+	"fmt"
+	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -69,3 +72,154 @@ func TestStrictContains(t *testing.T) {
 		t.Errorf("Test Contains expect false, get %T, %v", c2, c2)
 	}
 }
+
+func TestConcurrentUpdates(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"妲己"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				word := fmt.Sprintf("word%d-%d", i, j)
+				wf.Add(word, root)
+				if !wf.StrictContains("say "+word, root) {
+					t.Errorf("expected %s to be found", word)
+				}
+				wf.Replace("妲己 "+word, root)
+				wf.Remove(word, root)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !wf.Contains("妲己", root) || wf.StrictContains("word1-10", root) {
+		t.Error("unexpected words after the updates")
+	}
+	wf.AddAll([]string{"foo", "bar"}, root)
+	if !wf.StrictContains("foo", root) || !wf.StrictContains("bar", root) {
+		t.Error("expected the added words to be found")
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"妲己", "Miyamoto Musashi"})
+	text := "i like 妲x己 and Miyamoto Musashi"
+
+	want := []Match{
+		{Word: "妲己", Start: 7, End: 10},
+		{Word: "MiyamotoMusashi", Start: 15, End: 31},
+	}
+	if got := wf.FindAll(text, root); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll expect %v, get %v", want, got)
+	}
+	if got := wf.StrictFindAll(text, root); !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("StrictFindAll expect %v, get %v", want[1:], got)
+	}
+	if got := wf.Compile(root).FindAll(text); !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("Automaton FindAll expect %v, get %v", want[1:], got)
+	}
+	if got := wf.FindAll("nothing", root); got != nil {
+		t.Errorf("FindAll expect nil, get %v", got)
+	}
+}
+
+func TestWhitelist(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"ass", "妲己"})
+	wf.AddWhitelist("assistant", "classic")
+	a := wf.Compile(root)
+
+	tests := []struct {
+		text, want string
+	}{
+		{"my assistant", "myassistant"},
+		{"an assistant ass", "anassistant***"},
+		{"a classic", "aclassic"},
+		{"class", "cl***"},
+		{"妲己assistant", "**assistant"},
+	}
+	for _, tt := range tests {
+		if got := wf.StrictReplace(tt.text, root); got != tt.want {
+			t.Errorf("StrictReplace(%q) expect %q, get %q", tt.text, tt.want, got)
+		}
+		if got := a.Replace(tt.text); got != tt.want {
+			t.Errorf("Automaton Replace(%q) expect %q, get %q", tt.text, tt.want, got)
+		}
+	}
+	if wf.Contains("assistant", root) || a.Contains("assistant") {
+		t.Error("Contains expect false inside a whitelisted word")
+	}
+	if got := wf.StrictFindAll("assistant ass", root); len(got) != 1 || got[0].Start != 10 {
+		t.Errorf("StrictFindAll expect the last ass only, get %v", got)
+	}
+	if got := wf.Replace("my assistant", root); got != "myassistant" {
+		t.Errorf("Replace expect myassistant, get %q", got)
+	}
+
+	wf.RemoveWhitelist("assistant")
+	if !wf.Contains("assistant", root) {
+		t.Error("Contains expect true once removed from the whitelist")
+	}
+}
+
+func TestNoise(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"妲己"})
+	wf.SetNoise(NoisePunct)
+	if !wf.Contains("妲.，己", root) || wf.Contains("妲x己", root) {
+		t.Error("Contains expect only punctuation to be skipped")
+	}
+	if got := wf.Replace("a妲-己b", root); got != "a**b" {
+		t.Errorf("Replace expect a**b, get %q", got)
+	}
+	if wf.StrictContains("妲.己", root) {
+		t.Error("StrictContains expect false")
+	}
+
+	wf.SetNoise(NoiseChars("xy"))
+	if !wf.Contains("妲xyx己", root) || wf.Contains("妲z己", root) {
+		t.Error("Contains expect only x and y to be skipped")
+	}
+	wf.SetNoise(nil)
+	if !wf.Contains("妲z己", root) {
+		t.Error("Contains expect any character to be skipped by default")
+	}
+}
+
+func countNodes(root map[string]*Node) int {
+	n := 0
+	for _, node := range root {
+		n += 1 + countNodes(node.Child)
+	}
+	return n
+}
+
+func TestRemoveAllCompact(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"abc", "abd", "ab", "xyz", "妲己"})
+	if n := countNodes(root); n != 9 {
+		t.Fatalf("expect 9 nodes, get %d", n)
+	}
+
+	wf.RemoveAll([]string{"abc", "ab", "xyz", "missing"}, root)
+	if wf.StrictContains("abc", root) || wf.StrictContains("xyz", root) || !wf.StrictContains("abd", root) {
+		t.Error("expect the removed words only to be gone")
+	}
+	if n := countNodes(root); n != 9 {
+		t.Errorf("expect the branches to be kept before compaction, get %d nodes", n)
+	}
+
+	if removed := wf.Compact(root); removed != 4 {
+		t.Errorf("Compact expect 4 nodes pruned, get %d", removed)
+	}
+	if n := countNodes(root); n != 5 {
+		t.Errorf("expect 5 nodes after compaction, get %d", n)
+	}
+	if !wf.StrictContains("abd", root) || !wf.StrictContains("妲己", root) || wf.StrictContains("ab", root) {
+		t.Error("expect the remaining words to be found after compaction")
+	}
+}
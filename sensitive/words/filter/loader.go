@@ -0,0 +1,167 @@
+package filter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// GenerateFromReader converts sensitive text read from r into sensitive word tree nodes, see GenerateWithFile.
+func (wf *WordsFilter) GenerateFromReader(r io.Reader) (map[string]*Node, error) {
+	root := make(map[string]*Node)
+	if err := wf.AddFromReader(r, root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// GenerateFromFS converts sensitive text from the files of fsys matching patterns into sensitive word tree nodes,
+// e.g. an embed.FS. Patterns are the ones of fs.Glob.
+func (wf *WordsFilter) GenerateFromFS(fsys fs.FS, patterns ...string) (map[string]*Node, error) {
+	root := make(map[string]*Node)
+	if err := wf.AddFromFS(fsys, root, patterns...); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// AddFromReader adds the sensitive words read from r, one per line, to root.
+func (wf *WordsFilter) AddFromReader(r io.Reader, root map[string]*Node) error {
+	texts, err := readWords(r)
+	if err != nil {
+		return err
+	}
+	wf.AddAll(texts, root)
+	return nil
+}
+
+// AddFromFS adds the sensitive words of the files of fsys matching patterns to root.
+func (wf *WordsFilter) AddFromFS(fsys fs.FS, root map[string]*Node, patterns ...string) error {
+	var texts []string
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			words, err := readFileWords(fsys, name)
+			if err != nil {
+				return err
+			}
+			texts = append(texts, words...)
+		}
+	}
+	wf.AddAll(texts, root)
+	return nil
+}
+
+// AddFromURL adds the sensitive words downloaded by l to root.
+func (wf *WordsFilter) AddFromURL(ctx context.Context, l *URLLoader, root map[string]*Node) error {
+	texts, _, err := l.Load(ctx)
+	if err != nil {
+		return err
+	}
+	wf.AddAll(texts, root)
+	return nil
+}
+
+func readFileWords(fsys fs.FS, name string) ([]string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readWords(f)
+}
+
+// readWords returns the lines of r which are not blank, trimmed
+func readWords(r io.Reader) ([]string, error) {
+	buf := bufio.NewReader(r)
+	var texts []string
+	for {
+		line, _, err := buf.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				break
+			} else {
+				return nil, err
+			}
+		}
+		text := strings.TrimSpace(string(line))
+		if text == "" {
+			continue
+		}
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+// URLLoader downloads sensitive words from a URL, one per line.
+// It keeps the words with their ETag and Last-Modified headers, so they are only downloaded again once changed.
+// It is safe for concurrent use.
+type URLLoader struct {
+	url          string
+	client       *http.Client
+	mutex        sync.Mutex
+	etag         string
+	lastModified string
+	words        []string
+}
+
+// NewURLLoader creates a loader for url, http.DefaultClient is used when client is nil
+func NewURLLoader(url string, client *http.Client) *URLLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &URLLoader{url: url, client: client}
+}
+
+// Load returns the words at the URL and whether they changed since the previous call.
+// Words not modified on the server are returned from the previous download.
+func (l *URLLoader) Load(ctx context.Context) ([]string, bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if l.words != nil {
+		if l.etag != "" {
+			req.Header.Set("If-None-Match", l.etag)
+		}
+		if l.lastModified != "" {
+			req.Header.Set("If-Modified-Since", l.lastModified)
+		}
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		if l.words != nil {
+			return l.words, false, nil
+		}
+	case http.StatusOK:
+		words, err := readWords(resp.Body)
+		if err != nil {
+			return nil, false, err
+		}
+		if words == nil {
+			words = []string{}
+		}
+		l.words = words
+		l.etag = resp.Header.Get("ETag")
+		l.lastModified = resp.Header.Get("Last-Modified")
+		return words, true, nil
+	}
+	return nil, false, fmt.Errorf("filter: loading %s: %s", l.url, resp.Status)
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerateFromReader(t *testing.T) {
+	wf := New()
+	root, err := wf.GenerateFromReader(strings.NewReader("妲己\n\n  foo \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wf.StrictContains("a foo", root) || !wf.StrictContains("妲己", root) {
+		t.Error("StrictContains expect true for the words read")
+	}
+}
+
+func TestGenerateFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"words/porn.txt": {Data: []byte("妲己\n")},
+		"words/ads.txt":  {Data: []byte("buy now\n")},
+		"readme.md":      {Data: []byte("readme\n")},
+	}
+	wf := New()
+	root, err := wf.GenerateFromFS(fsys, "words/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wf.StrictContains("妲己", root) || !wf.StrictContains("buy now", root) || wf.StrictContains("readme", root) {
+		t.Error("expect the words of the matching files only")
+	}
+	if _, err := wf.GenerateFromFS(fsys, "["); err == nil {
+		t.Error("GenerateFromFS expect an error for a malformed pattern")
+	}
+}
+
+func TestURLLoader(t *testing.T) {
+	var requests, downloads, version atomic.Int32
+	versions := []string{"妲己\nfoo\n", "bar\n"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		v := version.Load()
+		etag := fmt.Sprintf(`"v%d"`, v)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(versions[v]))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	l := NewURLLoader(server.URL, nil)
+	got, changed, err := l.Load(ctx)
+	if err != nil || !changed || len(got) != 2 {
+		t.Fatalf("Load expect 2 changed words, get %v, %v, %v", got, changed, err)
+	}
+	got, changed, err = l.Load(ctx)
+	if err != nil || changed || len(got) != 2 {
+		t.Fatalf("Load expect 2 unchanged words, get %v, %v, %v", got, changed, err)
+	}
+	version.Store(1)
+	if got, changed, err = l.Load(ctx); err != nil || !changed || len(got) != 1 {
+		t.Fatalf("Load expect 1 changed word, get %v, %v, %v", got, changed, err)
+	}
+	if requests.Load() != 3 || downloads.Load() != 2 {
+		t.Errorf("expect 3 requests and 2 downloads, get %d and %d", requests.Load(), downloads.Load())
+	}
+
+	// sources are merged into one root
+	wf := New()
+	root, err := wf.GenerateFromReader(strings.NewReader("妲己\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wf.AddFromURL(ctx, l, root); err != nil {
+		t.Fatal(err)
+	}
+	if !wf.StrictContains("妲己", root) || !wf.StrictContains("bar", root) {
+		t.Error("StrictContains expect true for words of both sources")
+	}
+
+	if _, _, err := NewURLLoader(server.URL+"/%zz", nil).Load(ctx); err == nil {
+		t.Error("Load expect an error for an invalid URL")
+	}
+}
@@ -1,13 +1,15 @@
 package filter
 
 import (
-	"bytes"
 	"strings"
 )
 
 type Node struct {
 	Child        map[string]*Node
 	Placeholders string
+	// Category and Level of the word ending at the node, see Word
+	Category string
+	Level    int
 }
 
 // New creates a node.
@@ -18,18 +20,20 @@ func NewNode(child map[string]*Node, placeholders string) *Node {
 	}
 }
 
-// Add sensitive words to specified sensitive words Map.
-func (node *Node) add(text string, root map[string]*Node, placeholder string) {
+// Add sensitive words to specified sensitive words Map, returns the node ending the word.
+func (node *Node) add(text string, root map[string]*Node, placeholder string) *Node {
 	if text == "" {
-		return
+		return nil
 	}
 	textr := []rune(text)
 	end := len(textr) - 1
+	var last *Node
 	for i := 0; i <= end; i++ {
 		word := string(textr[i])
 		if n, ok := root[word]; ok { // contains key
 			if i == end { // the last
 				n.Placeholders = strings.Repeat(placeholder, end+1)
+				last = n
 			} else {
 				if n.Child != nil {
 					root = n.Child
@@ -43,10 +47,12 @@ func (node *Node) add(text string, root map[string]*Node, placeholder string) {
 			if i == end {
 				placeholders = strings.Repeat(placeholder, end+1)
 			}
-			root[word] = NewNode(child, placeholders)
+			last = NewNode(child, placeholders)
+			root[word] = last
 			root = child
 		}
 	}
+	return last
 }
 
 // Remove specified sensitive words from sensitive word map.
@@ -58,6 +64,7 @@ func (node *Node) remove(text string, root map[string]*Node) {
 		if n, ok := root[word]; ok {
 			if i == end {
 				n.Placeholders = ""
+				n.Category, n.Level = "", 0
 			} else {
 				root = n.Child
 			}
@@ -67,89 +74,58 @@ func (node *Node) remove(text string, root map[string]*Node) {
 	}
 }
 
-// Replace sensitive words in strings and return new strings.
-// Follow the principle of maximum matching.
-func (node *Node) replace(text string, root map[string]*Node, strict bool) string {
-	if root == nil || text == "" {
-		return text
+// find calls f with the rune offsets, the word and the node of each sensitive word in textr until f returns false.
+// Follow the principle of maximum matching, the leftmost and then longest word is matched first.
+// Characters for which noise, when not nil, returns true are skipped once the first character of a word matched.
+// Words for which skip, when not nil, returns true are not matched.
+func (node *Node) find(textr []rune, root map[string]*Node, noise func(r rune) bool, skip func(start, end int) bool, f func(start, end int, word []rune, n *Node) bool) {
+	if root == nil {
+		return
 	}
-	textr := []rune(text)
-	i, s, e, l := 0, 0, 0, len(textr)
-	bf := bytes.Buffer{}
-	var words map[string]*Node
-	var back []*Node
-loop:
-	for e < l {
-		words = root
-		i = e
-		// Maximum Matching Principle, Matching Backwards First
-		for ; i < l; i++ {
-			word := string(textr[i])
-			if n, ok := words[word]; ok {
-				if n.Child != nil {
-					words = n.Child
-					back = append(back, n)
-				} else if n.Placeholders != "" {
-					bf.WriteString(string(textr[s:e]))
-					bf.WriteString(n.Placeholders)
-					i++
-					s, e = i, i
-					continue loop
-				} else {
+	l := len(textr)
+	var word []rune
+	for s := 0; s < l; {
+		words := root
+		word = word[:0]
+		var last *Node
+		e, wl := 0, 0
+		for i := s; i < l; i++ {
+			n, ok := words[string(textr[i])]
+			if !ok {
+				if noise == nil || i == s || !noise(textr[i]) {
 					break
 				}
-			} else if strict {
-				back = back[:0]
-				break
+				continue
 			}
-		}
-
-		// Backward match fails, backtracking.
-		for ; i > e; i-- {
-			bl := len(back)
-			if bl == 0 {
-				break
+			word = append(word, textr[i])
+			if n.Placeholders != "" && (skip == nil || !skip(s, i+1)) {
+				last, e, wl = n, i+1, len(word)
 			}
-			last := back[bl-1]
-			back = back[:bl-1]
-			if last.Placeholders != "" {
-				bf.WriteString(string(textr[s:e]))
-				bf.WriteString(last.Placeholders)
-				s, e = i, i
-				continue loop
+			if len(n.Child) == 0 {
+				break
 			}
+			words = n.Child
 		}
-
-		e++
-		back = back[:0]
+		if last == nil {
+			s++
+			continue
+		}
+		if !f(s, e, word[:wl], last) {
+			return
+		}
+		s = e
 	}
-	bf.WriteString(string(textr[s:e]))
-
-	return bf.String()
 }
 
-// Whether the string contains sensitive words.
-func (node *Node) contains(text string, root map[string]*Node, strict bool) bool {
-	if root == nil || text == "" {
-		return false
-	}
-	textr := []rune(text)
-	end := len(textr) - 1
-	searchroot := root
-	for i := 0; i <= end; i++ {
-		word := string(textr[i])
-		if n, ok := searchroot[word]; ok {
-			if i == end {
-				return n.Placeholders != ""
-			} else {
-				if len(n.Child) == 0 { // last
-					return true
-				}
-				searchroot = n.Child
-			}
-		} else if strict {
-			searchroot = root
+// Prune the branches without words, returns the number of nodes removed.
+func (node *Node) compact(root map[string]*Node) int {
+	removed := 0
+	for key, n := range root {
+		removed += node.compact(n.Child)
+		if n.Placeholders == "" && len(n.Child) == 0 {
+			delete(root, key)
+			removed++
 		}
 	}
-	return false
+	return removed
 }
@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer appends the normalized form of r to dst, it may append several characters or none.
+// Characters are normalized independently so that matches can be mapped back to the original text.
+type Normalizer func(dst []rune, r rune) []rune
+
+// Lowercase maps characters to lower case
+func Lowercase(dst []rune, r rune) []rune {
+	return append(dst, unicode.ToLower(r))
+}
+
+// HalfWidth maps full-width ASCII variants, e.g. "Ｖ", and the ideographic space to ASCII
+func HalfWidth(dst []rune, r rune) []rune {
+	switch {
+	case r >= 0xFF01 && r <= 0xFF5E:
+		r -= 0xFEE0
+	case r == 0x3000:
+		r = ' '
+	}
+	return append(dst, r)
+}
+
+// NFKC applies the Unicode compatibility decomposition followed by the canonical composition,
+// mapping e.g. full-width letters, ligatures and circled digits to their plain forms
+func NFKC(dst []rune, r rune) []rune {
+	if r < 0x80 {
+		return append(dst, r)
+	}
+	for _, c := range norm.NFKC.String(string(r)) {
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// SetNormalizers sets the normalizers applied in order to both the words and the texts searched,
+// e.g. SetNormalizers(NFKC, Lowercase) matches "ＶＰＮ" and "Vpn" with "vpn".
+// Set them before adding words, words already added are not normalized again.
+func (wf *WordsFilter) SetNormalizers(normalizers ...Normalizer) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	wf.normalizers = normalizers
+}
+
+// word returns text as added to a tree, the lock must be held
+func (wf *WordsFilter) word(text string) string {
+	if wf.StripSpace {
+		text = stripSpace(text)
+	}
+	if len(wf.normalizers) == 0 {
+		return text
+	}
+	return string(newInput(text, false, wf.normalizers).runes)
+}
+
+// input is a text prepared for matching
+type input struct {
+	orig  []rune
+	strip bool
+	// runes are normalized, without spaces when stripped
+	runes []rune
+	// offsets are the offsets in orig of each rune
+	offsets []int
+}
+
+func newInput(text string, strip bool, normalizers []Normalizer) input {
+	in := input{orig: []rune(text), strip: strip}
+	in.runes = make([]rune, 0, len(in.orig))
+	in.offsets = make([]int, 0, len(in.orig))
+	var buf, next []rune
+	for i, r := range in.orig {
+		if strip && unicode.IsSpace(r) {
+			continue
+		}
+		if len(normalizers) == 0 {
+			in.runes = append(in.runes, r)
+			in.offsets = append(in.offsets, i)
+			continue
+		}
+		buf = append(buf[:0], r)
+		for _, normalize := range normalizers {
+			next = next[:0]
+			for _, r := range buf {
+				next = normalize(next, r)
+			}
+			buf, next = next, buf
+		}
+		for _, r := range buf {
+			in.runes = append(in.runes, r)
+			in.offsets = append(in.offsets, i)
+		}
+	}
+	return in
+}
+
+// span returns the offsets in the original text of runes[start:end]
+func (in input) span(start, end int) (int, int) {
+	return in.offsets[start], in.offsets[end-1] + 1
+}
+
+// text returns orig[start:end], without spaces when stripped
+func (in input) text(start, end int) string {
+	if !in.strip {
+		return string(in.orig[start:end])
+	}
+	var bf strings.Builder
+	for _, r := range in.orig[start:end] {
+		if !unicode.IsSpace(r) {
+			bf.WriteRune(r)
+		}
+	}
+	return bf.String()
+}
@@ -0,0 +1,53 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizers(t *testing.T) {
+	wf := New()
+	wf.SetNormalizers(HalfWidth, Lowercase)
+	root := wf.Generate([]string{"VPN"})
+
+	for _, text := range []string{"use a vpn", "use a ＶＰＮ", "use a Vpn", "use a Ｖ ｐ Ｎ"} {
+		if !wf.StrictContains(text, root) {
+			t.Errorf("StrictContains(%q) expect true", text)
+		}
+	}
+	if got := wf.Replace("Use a ＶＰＮ now", root); got != "Usea***now" {
+		t.Errorf("Replace expect Usea***now, get %q", got)
+	}
+	want := []Match{{Word: "vpn", Start: 6, End: 9}}
+	if got := wf.FindAll("Use a ＶＰＮ now", root); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll expect %v, get %v", want, got)
+	}
+	if got := wf.Compile(root).FindAll("Use a ＶＰＮ now"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Automaton FindAll expect %v, get %v", want, got)
+	}
+
+	wf.Remove("vPn", root)
+	if wf.Contains("vpn", root) {
+		t.Error("Contains expect false once removed")
+	}
+}
+
+func TestNormalizerOffsets(t *testing.T) {
+	// ß expands to two characters which map back to a single one
+	sharpS := func(dst []rune, r rune) []rune {
+		if r == 'ß' {
+			return append(dst, 's', 's')
+		}
+		return append(dst, r)
+	}
+	wf := New()
+	wf.SetNormalizers(sharpS)
+	root := wf.Generate([]string{"strasse"})
+	want := []Match{{Word: "strasse", Start: 3, End: 9}}
+	if got := wf.StrictFindAll("in straße", root); !reflect.DeepEqual(got, want) {
+		t.Errorf("StrictFindAll expect %v, get %v", want, got)
+	}
+	if got := wf.StrictReplace("in straße!", root); got != "in*******!" {
+		t.Errorf("StrictReplace expect in*******!, get %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package filter
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+const automatonFormatVersion = 1
+
+var errAutomatonFormat = errors.New("filter: unsupported automaton format")
+
+// automatonFile is the gob encoded content of an exported Automaton, the edges of all states are flattened
+type automatonFile struct {
+	Version int
+	// Edges is the number of edges of each state, Runes and Targets the edges in state order
+	Edges        []int32
+	Runes        []rune
+	Targets      []int32
+	Fail         []int32
+	Depth        []int32
+	Out          []int32
+	Placeholders []string
+	Words        []string
+	Categories   []string
+	Levels       []int
+	Whitelist    *automatonFile
+}
+
+// Export writes the automaton with encoding/gob, see WordsFilter.Import
+func (a *Automaton) Export(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(a.file())
+}
+
+func (a *Automaton) file() *automatonFile {
+	n := len(a.states)
+	f := &automatonFile{
+		Version:      automatonFormatVersion,
+		Edges:        make([]int32, n),
+		Fail:         make([]int32, n),
+		Depth:        make([]int32, n),
+		Out:          make([]int32, n),
+		Placeholders: make([]string, n),
+		Words:        make([]string, n),
+		Categories:   make([]string, n),
+		Levels:       make([]int, n),
+	}
+	for i := range a.states {
+		s := &a.states[i]
+		runes := make([]rune, 0, len(s.next))
+		for r := range s.next {
+			runes = append(runes, r)
+		}
+		slices.Sort(runes)
+		for _, r := range runes {
+			f.Runes = append(f.Runes, r)
+			f.Targets = append(f.Targets, s.next[r])
+		}
+		f.Edges[i] = int32(len(runes))
+		f.Fail[i], f.Depth[i], f.Out[i] = s.fail, s.depth, s.out
+		f.Placeholders[i], f.Words[i] = s.placeholders, s.word
+		f.Categories[i], f.Levels[i] = s.category, s.level
+	}
+	if a.whitelist != nil {
+		f.Whitelist = a.whitelist.file()
+	}
+	return f
+}
+
+// Import reads an automaton written by Export, it saves compiling large dictionaries at startup.
+// The normalizers, actions and replacer of the filter apply to it as if it compiled the automaton,
+// they should be the ones of the filter which did.
+func (wf *WordsFilter) Import(r io.Reader) (*Automaton, error) {
+	var f automatonFile
+	if err := gob.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+	a, err := f.automaton()
+	if err != nil {
+		return nil, err
+	}
+
+	wf.mutex.RLock()
+	defer wf.mutex.RUnlock()
+	a.stripSpace = wf.StripSpace
+	a.normalizers = wf.normalizers
+	a.policy = wf.policy.clone()
+	return a, nil
+}
+
+func (f *automatonFile) automaton() (*Automaton, error) {
+	n := len(f.Edges)
+	if f.Version != automatonFormatVersion || n == 0 || len(f.Runes) != len(f.Targets) ||
+		len(f.Fail) != n || len(f.Depth) != n || len(f.Out) != n || len(f.Placeholders) != n ||
+		len(f.Words) != n || len(f.Categories) != n || len(f.Levels) != n {
+		return nil, fmt.Errorf("%w: version %d", errAutomatonFormat, f.Version)
+	}
+	valid := func(s int32) bool { return s >= 0 && int(s) < n }
+
+	a := &Automaton{states: make([]state, n)}
+	edge := 0
+	for i := range a.states {
+		edges := int(f.Edges[i])
+		if edges < 0 || edge+edges > len(f.Runes) || !valid(f.Fail[i]) || !valid(f.Out[i]) {
+			return nil, fmt.Errorf("%w: invalid state %d", errAutomatonFormat, i)
+		}
+		next := make(map[rune]int32, edges)
+		for _, r := range f.Runes[edge : edge+edges] {
+			if !valid(f.Targets[edge]) {
+				return nil, fmt.Errorf("%w: invalid state %d", errAutomatonFormat, i)
+			}
+			next[r] = f.Targets[edge]
+			edge++
+		}
+		a.states[i] = state{
+			next:         next,
+			fail:         f.Fail[i],
+			depth:        f.Depth[i],
+			out:          f.Out[i],
+			placeholders: f.Placeholders[i],
+			word:         f.Words[i],
+			category:     f.Categories[i],
+			level:        f.Levels[i],
+		}
+	}
+	if f.Whitelist != nil {
+		whitelist, err := f.Whitelist.automaton()
+		if err != nil {
+			return nil, err
+		}
+		a.whitelist = whitelist
+	}
+	a.setMaxDepth()
+	return a, nil
+}
+
+// ExportTree writes the words tree root with encoding/gob, see ImportTree
+func (wf *WordsFilter) ExportTree(w io.Writer, root map[string]*Node) error {
+	wf.mutex.RLock()
+	defer wf.mutex.RUnlock()
+	return gob.NewEncoder(w).Encode(root)
+}
+
+// ImportTree reads a words tree written by ExportTree
+func (wf *WordsFilter) ImportTree(r io.Reader) (map[string]*Node, error) {
+	root := make(map[string]*Node)
+	if err := gob.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+)
+
+func TestExportImport(t *testing.T) {
+	wf := New()
+	root := wf.GenerateWords([]Word{
+		{Text: "he", Category: "a", Level: 1},
+		{Text: "she"},
+		{Text: "hers"},
+		{Text: "妲己", Category: "porn", Level: 3},
+	})
+	wf.AddWhitelist("ushers")
+	a := wf.Compile(root)
+
+	var buf bytes.Buffer
+	if err := a.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := wf.Import(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(imported.states, a.states) || !reflect.DeepEqual(imported.whitelist.states, a.whitelist.states) {
+		t.Error("Import expect the states of the exported automaton")
+	}
+	text := "ushers, she, 妲己 and hers"
+	if got, want := imported.FindAll(text), a.FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll expect %v, get %v", want, got)
+	}
+
+	if _, err := wf.Import(bytes.NewReader([]byte("garbage"))); err == nil {
+		t.Error("Import expect an error for garbage")
+	}
+	buf.Reset()
+	f := a.file()
+	f.Version++
+	if err := gobEncode(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wf.Import(&buf); err == nil {
+		t.Error("Import expect an error for another version")
+	}
+}
+
+func TestExportImportTree(t *testing.T) {
+	wf := New()
+	root := wf.GenerateWords([]Word{{Text: "妲己", Category: "porn", Level: 3}, {Text: "foo"}})
+	var buf bytes.Buffer
+	if err := wf.ExportTree(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := wf.ImportTree(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(imported, root) {
+		t.Error("ImportTree expect the exported tree")
+	}
+}
+
+func gobEncode(buf *bytes.Buffer, f *automatonFile) error {
+	return gob.NewEncoder(buf).Encode(f)
+}
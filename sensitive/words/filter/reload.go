@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Source returns a whole word list and whether it changed since the previous call.
+// A Reloader never calls its sources concurrently.
+type Source func(ctx context.Context) (words []string, changed bool, err error)
+
+// FileSource reads the words of a file, one per line, once again whenever its modification time or size changed
+func FileSource(path string) Source {
+	var (
+		modTime time.Time
+		size    int64
+		words   []string
+	)
+	return func(context.Context) ([]string, bool, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, false, err
+		}
+		if words != nil && info.ModTime().Equal(modTime) && info.Size() == size {
+			return words, false, nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		read, err := readWords(f)
+		if err != nil {
+			return nil, false, err
+		}
+		if read == nil {
+			read = []string{}
+		}
+		words, modTime, size = read, info.ModTime(), info.Size()
+		return words, true, nil
+	}
+}
+
+// URLSource downloads words with l, see URLLoader
+func URLSource(l *URLLoader) Source {
+	return l.Load
+}
+
+type ReloaderOption func(*Reloader)
+
+// ReloaderErrorHandler sets the function receiving the errors of the reloads done by Watch,
+// they are written to the standard logger by default
+func ReloaderErrorHandler(handler func(error)) ReloaderOption {
+	return func(r *Reloader) { r.errorHandler = handler }
+}
+
+// ReloaderOnReload sets a function called with each new root, e.g. to compile it into an Automaton
+func ReloaderOnReload(f func(root map[string]*Node)) ReloaderOption {
+	return func(r *Reloader) { r.onReload = f }
+}
+
+// Reloader keeps a words tree generated from sources up to date.
+// A new tree is generated whenever a source changed and swapped atomically,
+// searches in progress keep using the previous tree which is never modified.
+type Reloader struct {
+	wf           *WordsFilter
+	sources      []Source
+	mutex        sync.Mutex
+	root         atomic.Pointer[map[string]*Node]
+	dirty        bool // a source changed since the last swap
+	errorHandler func(error)
+	onReload     func(root map[string]*Node)
+}
+
+// NewReloader creates a reloader generating trees with wf from the words of all sources, loaded a first time
+func NewReloader(ctx context.Context, wf *WordsFilter, sources []Source, opts ...ReloaderOption) (*Reloader, error) {
+	r := &Reloader{wf: wf, sources: sources}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	if _, err := r.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Root returns the current tree, pass it to the methods of the filter
+func (r *Reloader) Root() map[string]*Node {
+	return *r.root.Load()
+}
+
+// Reload loads the sources and swaps the tree when one of them changed, it reports whether it did.
+// The tree is kept when a source fails.
+func (r *Reloader) Reload(ctx context.Context) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var texts []string
+	for _, source := range r.sources {
+		words, changed, err := source(ctx)
+		// the changes were consumed before the error, rebuild on the next successful reload
+		r.dirty = r.dirty || changed
+		if err != nil {
+			return false, err
+		}
+		texts = append(texts, words...)
+	}
+	if !r.dirty && r.root.Load() != nil {
+		return false, nil
+	}
+
+	root := r.wf.Generate(texts)
+	r.root.Store(&root)
+	r.dirty = false
+	if r.onReload != nil {
+		r.onReload(root)
+	}
+	return true, nil
+}
+
+// Watch reloads the sources every interval until ctx is done
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reload(ctx); err != nil && ctx.Err() == nil {
+				if r.errorHandler != nil {
+					r.errorHandler(err)
+				} else {
+					log.Printf("filter: reloading words failed: %v", err)
+				}
+			}
+		}
+	}
+}
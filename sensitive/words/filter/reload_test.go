@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("妲己\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	static := func(context.Context) ([]string, bool, error) {
+		return []string{"foo"}, false, nil
+	}
+
+	wf := New()
+	var (
+		mutex     sync.Mutex
+		automaton *Automaton
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := NewReloader(ctx, wf, []Source{FileSource(path), static}, ReloaderOnReload(func(root map[string]*Node) {
+		mutex.Lock()
+		automaton = wf.Compile(root)
+		mutex.Unlock()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wf.StrictContains("妲己", r.Root()) || !wf.StrictContains("foo", r.Root()) {
+		t.Fatal("expect the words of both sources")
+	}
+	if changed, err := r.Reload(ctx); changed || err != nil {
+		t.Errorf("Reload expect no change, get %v, %v", changed, err)
+	}
+
+	old := r.Root()
+	go r.Watch(ctx, 10*time.Millisecond)
+	if err := os.WriteFile(path, []byte("bar\nbaz\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !wf.StrictContains("baz", r.Root()) {
+		if time.Now().After(deadline) {
+			t.Fatal("expect the file to be reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if wf.StrictContains("妲己", r.Root()) || !wf.StrictContains("foo", r.Root()) {
+		t.Error("expect the words of the new file and the other source")
+	}
+	if !wf.StrictContains("妲己", old) {
+		t.Error("expect the previous tree to be left untouched")
+	}
+	mutex.Lock()
+	if !automaton.Contains("bar") {
+		t.Error("expect the automaton to be compiled again")
+	}
+	mutex.Unlock()
+
+	if _, err := NewReloader(ctx, wf, []Source{FileSource(path + ".missing")}); err == nil {
+		t.Error("NewReloader expect an error for a missing file")
+	}
+}
+
+func TestReloaderKeepsChangesAfterError(t *testing.T) {
+	words := []string{"foo"}
+	changed, failing := false, false
+	first := func(context.Context) ([]string, bool, error) {
+		c := changed
+		changed = false
+		return words, c, nil
+	}
+	second := func(context.Context) ([]string, bool, error) {
+		if failing {
+			return nil, false, errors.New("failure")
+		}
+		return nil, false, nil
+	}
+
+	wf := New()
+	r, err := NewReloader(context.Background(), wf, []Source{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	words, changed, failing = []string{"bar"}, true, true
+	if _, err := r.Reload(context.Background()); err == nil {
+		t.Fatal("Reload expect the source error")
+	}
+	failing = false
+	if changed, err := r.Reload(context.Background()); !changed || err != nil {
+		t.Errorf("Reload expect the change seen before the error, get %v, %v", changed, err)
+	}
+	if !wf.StrictContains("bar", r.Root()) {
+		t.Error("expect the new words after the failed reload")
+	}
+}
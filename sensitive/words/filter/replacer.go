@@ -0,0 +1,57 @@
+package filter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Replacer returns the replacement of a sensitive word as it appears in the text,
+// including the characters skipped by non-strict matching
+type Replacer func(word string) string
+
+// MaskReplacer replaces each character of the word with placeholder
+func MaskReplacer(placeholder string) Replacer {
+	return func(word string) string {
+		return strings.Repeat(placeholder, utf8.RuneCountInString(word))
+	}
+}
+
+// FixedReplacer replaces words with n placeholders whatever their length, hiding it
+func FixedReplacer(placeholder string, n int) Replacer {
+	mask := strings.Repeat(placeholder, n)
+	return func(string) string {
+		return mask
+	}
+}
+
+// TokenReplacer replaces whole words with token, e.g. "[censored]"
+func TokenReplacer(token string) Replacer {
+	return func(string) string {
+		return token
+	}
+}
+
+// KeepEndsReplacer keeps the first and last characters of the word and replaces the others with placeholder.
+// Words of two characters keep the first one, words of one character are masked.
+func KeepEndsReplacer(placeholder string) Replacer {
+	return func(word string) string {
+		wordr := []rune(word)
+		switch len(wordr) {
+		case 0:
+			return ""
+		case 1:
+			return placeholder
+		case 2:
+			return string(wordr[0]) + placeholder
+		}
+		return string(wordr[0]) + strings.Repeat(placeholder, len(wordr)-2) + string(wordr[len(wordr)-1])
+	}
+}
+
+// SetReplacer sets how Replace and Filter replace sensitive words, r is called for each word replaced.
+// The placeholders of the words, computed with Placeholder when they are added, are used when r is nil.
+func (wf *WordsFilter) SetReplacer(r Replacer) {
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	wf.policy.replacer = r
+}
@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplacers(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Replacer
+		want string
+	}{
+		{"default", nil, "a**b*****c"},
+		{"mask", MaskReplacer("#"), "a##b#####c"},
+		{"fixed", FixedReplacer("*", 3), "a***b***c"},
+		{"token", TokenReplacer("[censored]"), "a[censored]b[censored]c"},
+		{"keep ends", KeepEndsReplacer("*"), "a妲*bf***kc"},
+		{"callback", strings.ToUpper, "a妲己bFUCKKc"},
+	}
+	wf := New()
+	root := wf.Generate([]string{"妲己", "fuckk"})
+	for _, tt := range tests {
+		wf.SetReplacer(tt.r)
+		if got := wf.Replace("a妲己b fuckk c", root); got != tt.want {
+			t.Errorf("%s: Replace expect %q, get %q", tt.name, tt.want, got)
+		}
+		if got := wf.Compile(root).Replace("a妲己b fuckk c"); got != tt.want {
+			t.Errorf("%s: Automaton Replace expect %q, get %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestKeepEndsReplacer(t *testing.T) {
+	r := KeepEndsReplacer("*")
+	for word, want := range map[string]string{"": "", "a": "*", "ab": "a*", "abc": "a*c", "妲xx己": "妲**己"} {
+		if got := r(word); got != want {
+			t.Errorf("KeepEndsReplacer(%q) expect %q, get %q", word, want, got)
+		}
+	}
+}
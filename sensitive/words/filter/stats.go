@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"sync"
+
+	"github.com/dreamsxin/go-utils/stats"
+)
+
+// StatsName is the name of the counters registered by SetStatsRegistry, labelled by word
+const StatsName = "sensitive_word_hits"
+
+// wordCounts counts the matches of each word
+type wordCounts struct {
+	mutex     sync.RWMutex
+	counters  map[string]*stats.Counter
+	registry  *stats.Registry
+	namespace string
+}
+
+func newWordCounts() *wordCounts {
+	return &wordCounts{counters: make(map[string]*stats.Counter)}
+}
+
+func (wc *wordCounts) counter(word string) *stats.Counter {
+	wc.mutex.RLock()
+	c := wc.counters[word]
+	wc.mutex.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	wc.mutex.Lock()
+	defer wc.mutex.Unlock()
+	if c = wc.counters[word]; c == nil {
+		c = wc.newCounter(word)
+		wc.counters[word] = c
+	}
+	return c
+}
+
+func (wc *wordCounts) newCounter(word string) *stats.Counter {
+	if wc.registry != nil {
+		return wc.registry.CounterWith(wc.namespace, StatsName, stats.Labels{"word": word})
+	}
+	return stats.NewCounter()
+}
+
+func (wc *wordCounts) inc(word string) {
+	if wc != nil {
+		wc.counter(word).Inc()
+	}
+}
+
+// Stats returns how many times each word matched since the filter was created or its stats reset.
+// Words are counted by Replace, Contains, FindAll, Filter and their variants, compiled automata included,
+// in their form in the tree, e.g. without spaces. Contains only counts the first word found.
+func (wf *WordsFilter) Stats() map[string]int64 {
+	wc := wf.policy.counts
+	wc.mutex.RLock()
+	defer wc.mutex.RUnlock()
+	counts := make(map[string]int64, len(wc.counters))
+	for word, c := range wc.counters {
+		if n := c.Count(); n > 0 {
+			counts[word] = n
+		}
+	}
+	return counts
+}
+
+// ResetStats sets the counts of all words back to zero
+func (wf *WordsFilter) ResetStats() {
+	wc := wf.policy.counts
+	wc.mutex.RLock()
+	defer wc.mutex.RUnlock()
+	for _, c := range wc.counters {
+		c.Reset()
+	}
+}
+
+// SetStatsRegistry registers the counters of the words in registry,
+// under namespace and StatsName with a "word" label, so that they are reported with the other metrics.
+// Counts so far are carried over.
+func (wf *WordsFilter) SetStatsRegistry(registry *stats.Registry, namespace string) {
+	wc := wf.policy.counts
+	wc.mutex.Lock()
+	defer wc.mutex.Unlock()
+	wc.registry, wc.namespace = registry, namespace
+	for word, c := range wc.counters {
+		registered := wc.newCounter(word)
+		registered.Add(c.Count())
+		wc.counters[word] = registered
+	}
+}
@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dreamsxin/go-utils/stats"
+)
+
+func TestStats(t *testing.T) {
+	wf := New()
+	root := wf.Generate([]string{"妲己", "foo", "buy now"})
+	a := wf.Compile(root)
+
+	wf.Replace("妲己 foo 妲己", root)
+	wf.Contains("buy now", root)
+	wf.StrictFindAll("foo", root)
+	a.Replace("foo")
+	want := map[string]int64{"妲己": 2, "foo": 3, "buynow": 1}
+	if got := wf.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats expect %v, get %v", want, got)
+	}
+
+	registry := stats.NewRegistry()
+	wf.SetStatsRegistry(registry, "chat")
+	a.Contains("foo")
+	if got := registry.CounterWith("chat", StatsName, stats.Labels{"word": "foo"}).Count(); got != 4 {
+		t.Errorf("registered counter expect 4, get %d", got)
+	}
+
+	wf.ResetStats()
+	if got := wf.Stats(); len(got) != 0 {
+		t.Errorf("Stats expect nothing after a reset, get %v", got)
+	}
+}
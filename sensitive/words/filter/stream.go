@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// streamChunkSize is the number of bytes read or buffered before filtering a stream
+const streamChunkSize = 4096
+
+// streamer filters a stream chunk by chunk. It holds back the end of the text which could
+// start a word continuing in the next chunk, at most the length of the longest word.
+type streamer struct {
+	a *Automaton
+	// buf is the input not filtered yet
+	buf []byte
+	// context is the end of the text already filtered in which whitelisted words may start
+	context []rune
+}
+
+// filter returns the filtered text which can no longer change, all the text buffered when final.
+// Unlike Replace, spaces out of the words are kept.
+func (s *streamer) filter(final bool) []byte {
+	// an incomplete character waits for its next bytes
+	n := len(s.buf)
+	if !final {
+		for i := max(n-utf8.UTFMax, 0); i < n; i++ {
+			if utf8.RuneStart(s.buf[i]) && !utf8.FullRune(s.buf[i:n]) {
+				n = i
+				break
+			}
+		}
+	}
+
+	prefix := string(s.context)
+	in := newInput(prefix+string(s.buf[:n]), s.a.stripSpace, s.a.normalizers)
+	in.strip = false
+	orig := in.orig
+	// runes of the context were filtered already
+	from := 0
+	for from < len(in.offsets) && in.offsets[from] < len(s.context) {
+		from++
+	}
+	hits := s.a.find(in.runes)
+	for len(hits) > 0 && hits[0].start < from {
+		hits = hits[1:]
+	}
+
+	cut := len(orig)
+	if !final {
+		// words starting before the last maxDepth-1 characters are complete
+		cutRunes := max(len(in.runes)-max(s.a.maxDepth-1, 0), from)
+		if cutRunes < len(in.runes) {
+			cut = in.offsets[cutRunes]
+		}
+		complete := 0
+		for complete < len(hits) && hits[complete].start < cutRunes {
+			_, end := in.span(hits[complete].start, hits[complete].end)
+			cut = max(cut, end)
+			complete++
+		}
+		hits = hits[:complete]
+		in = in.prefix(cut)
+	}
+
+	out, matches, _ := s.a.policy.apply(in, hits)
+	s.a.policy.logAll(matches)
+	if depth := s.a.whitelistDepth(); depth > 1 {
+		s.context = append(s.context[:0], orig[max(cut-depth+1, 0):cut]...)
+	}
+	s.buf = append([]byte(string(orig[cut:])), s.buf[n:]...)
+	return []byte(out[len(prefix):])
+}
+
+func (a *Automaton) whitelistDepth() int {
+	if a.whitelist == nil {
+		return 0
+	}
+	return a.whitelist.maxDepth
+}
+
+// prefix returns the input truncated to the first n characters of the original text
+func (in input) prefix(n int) input {
+	runes := len(in.offsets)
+	for runes > 0 && in.offsets[runes-1] >= n {
+		runes--
+	}
+	in.orig = in.orig[:n]
+	in.runes, in.offsets = in.runes[:runes], in.offsets[:runes]
+	return in
+}
+
+type filterReader struct {
+	streamer
+	r   io.Reader
+	out []byte
+	err error
+}
+
+// FilterReader returns a reader replacing the sensitive words read from r like Replace,
+// buffering no more than a chunk and the length of the longest word.
+// Spaces are kept, words of ActionBlock categories are replaced without error.
+func (a *Automaton) FilterReader(r io.Reader) io.Reader {
+	return &filterReader{streamer: streamer{a: a}, r: r}
+}
+
+func (fr *filterReader) Read(p []byte) (int, error) {
+	for len(fr.out) == 0 {
+		if fr.err != nil {
+			return 0, fr.err
+		}
+		chunk := make([]byte, streamChunkSize)
+		n, err := fr.r.Read(chunk)
+		fr.buf = append(fr.buf, chunk[:n]...)
+		if err != nil {
+			fr.err = err
+			fr.out = fr.filter(true)
+		} else if len(fr.buf) >= streamChunkSize {
+			fr.out = fr.filter(false)
+		}
+	}
+	n := copy(p, fr.out)
+	fr.out = fr.out[n:]
+	return n, nil
+}
+
+type filterWriter struct {
+	streamer
+	w io.Writer
+}
+
+// FilterWriter returns a writer replacing the sensitive words written to it like FilterReader before writing them to w.
+// Close writes the text held back, it does not close w.
+func (a *Automaton) FilterWriter(w io.Writer) io.WriteCloser {
+	return &filterWriter{streamer: streamer{a: a}, w: w}
+}
+
+func (fw *filterWriter) Write(p []byte) (int, error) {
+	fw.buf = append(fw.buf, p...)
+	if len(fw.buf) < streamChunkSize {
+		return len(p), nil
+	}
+	if _, err := fw.w.Write(fw.filter(false)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (fw *filterWriter) Close() error {
+	out := fw.filter(true)
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := fw.w.Write(out)
+	return err
+}
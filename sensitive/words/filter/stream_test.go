@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func streamText(n int) string {
+	parts := []string{"he", "she", "hers", "妲己", "classic", "ass", " ", "x", "é", "汉", "\n"}
+	rnd := rand.New(rand.NewSource(1))
+	var bf strings.Builder
+	for bf.Len() < n {
+		bf.WriteString(parts[rnd.Intn(len(parts))])
+	}
+	return bf.String()
+}
+
+func TestFilterReader(t *testing.T) {
+	wf := New()
+	wf.StripSpace = false
+	root := wf.Generate([]string{"he", "she", "hers", "妲己", "ass"})
+	wf.AddWhitelist("classic")
+	a := wf.Compile(root)
+
+	text := streamText(5 * streamChunkSize)
+	want := a.Replace(text)
+	for name, r := range map[string]io.Reader{
+		"reader":   strings.NewReader(text),
+		"one byte": iotest.OneByteReader(strings.NewReader(text)),
+		"half":     iotest.HalfReader(strings.NewReader(text)),
+	} {
+		got, err := io.ReadAll(a.FilterReader(r))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: FilterReader differs from Replace", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := a.FilterWriter(&buf)
+	for i := 0; i < len(text); i += 1000 {
+		if _, err := w.Write([]byte(text[i:min(i+1000, len(text))])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Error("FilterWriter differs from Replace")
+	}
+}
+
+func TestFilterReaderKeepsSpaces(t *testing.T) {
+	wf := New()
+	a := wf.Compile(wf.Generate([]string{"buy now"}))
+	text := strings.Repeat("x", streamChunkSize-3) + " buy  now and then\n"
+	got, err := io.ReadAll(a.FilterReader(iotest.HalfReader(strings.NewReader(text))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Repeat("x", streamChunkSize-3) + " ****** and then\n"; string(got) != want {
+		t.Errorf("FilterReader expect %q, get %q", want[len(want)-20:], string(got[max(len(got)-20, 0):]))
+	}
+}
@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// homoglyphs maps characters commonly substituted to evade filters to the lower case letters they look like
+var homoglyphs = map[rune]rune{
+	// digits and symbols
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l',
+	// Cyrillic
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j',
+	'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'һ': 'h', 'ӏ': 'l',
+	'А': 'a', 'В': 'b', 'Е': 'e', 'К': 'k', 'М': 'm', 'Н': 'h', 'О': 'o', 'Р': 'p', 'С': 'c', 'Т': 't',
+	'Х': 'x', 'У': 'y', 'Ѕ': 's', 'І': 'i', 'Ј': 'j',
+	// Greek
+	'α': 'a', 'β': 'b', 'ο': 'o', 'ν': 'v', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'ι': 'i', 'κ': 'k',
+	'Α': 'a', 'Β': 'b', 'Ε': 'e', 'Ζ': 'z', 'Η': 'h', 'Ι': 'i', 'Κ': 'k', 'Μ': 'm', 'Ν': 'n', 'Ο': 'o',
+	'Ρ': 'p', 'Τ': 't', 'Υ': 'y', 'Χ': 'x',
+}
+
+// Homoglyphs maps look-alike characters to lower case Latin letters, e.g. "0" to "o" or the Cyrillic "ѕ" to "s".
+// Digits are mapped too, add it after Lowercase and only when dictionary words rarely contain digits.
+func Homoglyphs(dst []rune, r rune) []rune {
+	if h, ok := homoglyphs[r]; ok {
+		r = h
+	}
+	return append(dst, r)
+}
+
+// traditionalPairs lists common traditional Chinese characters each followed by its simplified form
+const traditionalPairs = "" +
+	"國国學学會会來来對对說说時时為为這这個个過过後后還还經经點点樣样現现發发開开關关長长問问頭头與与從从" +
+	"動动種种實实無无當当兩两見见電电東东車车書书馬马門门鳥鸟魚鱼龍龙風风飛飞雲云氣气愛爱親亲聽听讓让記记" +
+	"話话語语認认識识請请讀读寫写買买賣卖錢钱銀银鐵铁錯错鐘钟陽阳陰阴隊队際际險险難难雙双雜杂雞鸡離离麗丽" +
+	"顏颜題题願愿類类飯饭館馆驗验體体黨党齊齐歲岁歷历殺杀決决況况減减溫温測测滿满漢汉濟济灣湾災灾煙烟熱热" +
+	"爭争爾尔獨独獲获環环產产畫画盡尽監监盤盘眾众確确禮礼穩稳窮穷競竞筆笔節节範范築筑簡简糧粮紀纪約约紅红" +
+	"級级紙纸細细終终組组結结絕绝給给統统絲丝網网線线練练緊紧總总縣县績绩織织繼继續续罰罚罵骂義义習习聖圣" +
+	"聞闻聯联聲声職职腦脑膽胆舉举舊旧藝艺藥药蘇苏蘭兰處处號号蟲虫衛卫補补裝装製制複复觀观覺觉計计討讨訓训" +
+	"設设許许訴诉試试詩诗該该詳详誠诚誤误調调談谈論论講讲證证護护變变讚赞貝贝負负財财責责貨货貧贫質质費费" +
+	"資资賊贼賞赏贏赢趕赶趙赵跡迹輕轻載载輛辆輸输轉转辦办農农連连進进運运達达遠远適适選选遺遗邊边鄉乡醫医" +
+	"釋释針针鋼钢錄录鎮镇鏡镜閃闪閉闭間间閱阅陳陈陸陆隨随隱隐雖虽靈灵靜静韓韩響响頁页頂顶項项順顺須须領领" +
+	"頻频顆颗顧顾餘余駕驾騎骑驚惊髮发鬥斗鬧闹鮮鲜鳳凤鴨鸭鹽盐麥麦黃黄齒齿龜龟婦妇媽妈孫孙寶宝寧宁專专將将" +
+	"尋寻導导層层屬属島岛幣币幫帮廣广廠厂張张強强彈弹歸归徑径復复徵征憑凭應应懷怀戰战戲戏擁拥擇择擊击據据" +
+	"擔担擴扩攝摄敗败數数斷断暫暂曆历條条極极標标權权機机檢检樓楼榮荣槍枪歡欢歐欧殘残毀毁淚泪淺浅漁渔潔洁" +
+	"澤泽濃浓爐炉燈灯營营牆墙犧牺狀状獵猎獻献異异疊叠療疗盜盗睜睁碼码礦矿禍祸稱称窩窝竊窃簽签籃篮糾纠紛纷" +
+	"純纯納纳紹绍維维綠绿緣缘編编緩缓縮缩繩绳罷罢羅罗聰聪脅胁脫脱腳脚膚肤臉脸臨临興兴艦舰莊庄華华萬万葉叶" +
+	"蓋盖薦荐蘋苹虛虚蝦虾術术衝冲襲袭視视規规覽览訂订訊讯託托訪访評评詞词譯译議议豐丰豬猪貓猫貢贡貴贵貸贷" +
+	"賀贺賭赌賽赛購购贈赠趨趋躍跃軍军軟软較较輔辅輝辉輪轮辭辞郵邮醜丑鄰邻釣钓鈴铃鉛铅銳锐鋒锋錦锦鍋锅鍵键" +
+	"鎖锁鏈链鑰钥閣阁闖闯陣阵階阶霧雾韋韦頓顿頸颈額额顯显飄飘飽饱餅饼養养餓饿駐驻騙骗騰腾驅驱髒脏魯鲁鯨鲸" +
+	"鴿鸽鵝鹅鶴鹤鷹鹰麼么齡龄劉刘劇剧則则剛刚創创劃划勁劲勞劳勝胜勢势區区協协卻却厲厉參参嚇吓嚴严囑嘱園园" +
+	"圍围圖图團团執执堅坚報报場场塊块塵尘墊垫壓压壞坏壯壮壽寿夢梦夾夹奪夺奮奋亂乱亞亚佔占係系倫伦偉伟側侧" +
+	"傳传傷伤傾倾僅仅價价儀仪億亿儘尽償偿優优兒儿內内冊册凍冻凱凯刪删別别劍剑務务勵励勸劝單单錶表黴霉"
+
+var (
+	simplifiedOnce sync.Once
+	simplified     map[rune]rune
+)
+
+// Simplified maps common traditional Chinese characters to their simplified form, e.g. "國" to "国"
+func Simplified(dst []rune, r rune) []rune {
+	if r >= 0x3400 {
+		simplifiedOnce.Do(func() {
+			pairs := []rune(traditionalPairs)
+			simplified = make(map[rune]rune, len(pairs)/2)
+			for i := 0; i+1 < len(pairs); i += 2 {
+				simplified[pairs[i]] = pairs[i+1]
+			}
+		})
+		if s, ok := simplified[r]; ok {
+			r = s
+		}
+	}
+	return append(dst, r)
+}
+
+// Pinyin returns a normalizer spelling Chinese characters in pinyin with table, e.g. "妲己" as "daji",
+// so that pinyin spellings of dictionary words are matched. Put it after Simplified since table
+// may only hold simplified characters. Characters without pinyin are kept.
+func Pinyin(table map[rune]string) Normalizer {
+	return func(dst []rune, r rune) []rune {
+		p, ok := table[r]
+		if !ok {
+			return append(dst, r)
+		}
+		for _, c := range p {
+			dst = append(dst, c)
+		}
+		return dst
+	}
+}
+
+// toneless maps the vowels with tone marks to plain letters
+var toneless = strings.NewReplacer(
+	"ā", "a", "á", "a", "ǎ", "a", "à", "a",
+	"ē", "e", "é", "e", "ě", "e", "è", "e", "ế", "e", "ề", "e",
+	"ī", "i", "í", "i", "ǐ", "i", "ì", "i",
+	"ō", "o", "ó", "o", "ǒ", "o", "ò", "o",
+	"ū", "u", "ú", "u", "ǔ", "u", "ù", "u",
+	"ǖ", "v", "ǘ", "v", "ǚ", "v", "ǜ", "v", "ü", "v",
+	"ń", "n", "ň", "n", "ǹ", "n", "ḿ", "m",
+)
+
+// LoadPinyin reads a pinyin table for Pinyin in the format of the pinyin-data project,
+// lines like "U+4E2D: zhōng,zhòng  # 中". The first reading of each character is kept, without tones.
+func LoadPinyin(r io.Reader) (map[rune]string, error) {
+	table := make(map[rune]string)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		code, readings, ok := strings.Cut(text, ":")
+		if !ok {
+			if strings.TrimSpace(text) != "" {
+				return nil, fmt.Errorf("filter: pinyin line %d: missing ':'", line)
+			}
+			continue
+		}
+		c, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(code), "U+"), 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("filter: pinyin line %d: %w", line, err)
+		}
+		reading, _, _ := strings.Cut(strings.TrimSpace(readings), ",")
+		reading = strings.TrimFunc(toneless.Replace(reading), unicode.IsSpace)
+		if reading != "" {
+			table[rune(c)] = reading
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHomoglyphs(t *testing.T) {
+	wf := New()
+	wf.SetNormalizers(Lowercase, Homoglyphs)
+	root := wf.Generate([]string{"boss"})
+	for _, text := range []string{"b0ss", "B0$$", "bоѕs", "ΒΟSS"} {
+		if !wf.StrictContains(text, root) {
+			t.Errorf("StrictContains(%q) expect true", text)
+		}
+	}
+	if got := wf.StrictReplace("the b0$$!", root); got != "the****!" {
+		t.Errorf("StrictReplace expect the****!, get %q", got)
+	}
+}
+
+func TestSimplified(t *testing.T) {
+	wf := New()
+	wf.SetNormalizers(Simplified)
+	root := wf.Generate([]string{"赌博", "電話"})
+	for _, text := range []string{"賭博", "赌博", "电话", "電話"} {
+		if !wf.StrictContains(text, root) {
+			t.Errorf("StrictContains(%q) expect true", text)
+		}
+	}
+}
+
+func TestPinyin(t *testing.T) {
+	table, err := LoadPinyin(strings.NewReader(`# pinyin-data
+U+59B2: dá  # 妲
+U+5DF1: jǐ  # 己
+U+7EFF: lǜ,lù  # 绿
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[rune]string{'妲': "da", '己': "ji", '绿': "lv"}; !reflect.DeepEqual(table, want) {
+		t.Errorf("LoadPinyin expect %v, get %v", want, table)
+	}
+
+	wf := New()
+	wf.SetNormalizers(Lowercase, Simplified, Pinyin(table))
+	root := wf.Generate([]string{"妲己"})
+	for _, text := range []string{"妲己", "da ji", "DaJi", "妲ji"} {
+		if !wf.StrictContains(text, root) {
+			t.Errorf("StrictContains(%q) expect true", text)
+		}
+	}
+	want := []Match{{Word: "daji", Start: 2, End: 4}}
+	if got := wf.StrictFindAll("i 妲己", root); !reflect.DeepEqual(got, want) {
+		t.Errorf("StrictFindAll expect %v, get %v", want, got)
+	}
+
+	if _, err := LoadPinyin(strings.NewReader("U+ZZZZ: da\n")); err == nil {
+		t.Error("LoadPinyin expect an error for an invalid code point")
+	}
+}
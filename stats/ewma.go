@@ -0,0 +1,161 @@
+package stats
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TickInterval is the interval at which EWMA rates are expected to be ticked
+const TickInterval = 5 * time.Second
+
+// EWMA is an exponentially weighted moving average of a per-second rate,
+// as used for Unix load averages. Events are accumulated with Update and
+// folded into the average every TickInterval by calling Tick.
+type EWMA struct {
+	uncounted int64
+	alpha     float64
+
+	mu          sync.Mutex
+	rate        float64
+	initialized bool
+}
+
+// NewEWMA creates an EWMA with the given smoothing factor
+func NewEWMA(alpha float64) *EWMA {
+	return &EWMA{
+		alpha: alpha,
+	}
+}
+
+// NewEWMA1 creates an EWMA averaging over a one-minute window
+func NewEWMA1() *EWMA {
+	return NewEWMA(1 - math.Exp(-TickInterval.Seconds()/60/1))
+}
+
+// NewEWMA5 creates an EWMA averaging over a five-minute window
+func NewEWMA5() *EWMA {
+	return NewEWMA(1 - math.Exp(-TickInterval.Seconds()/60/5))
+}
+
+// NewEWMA15 creates an EWMA averaging over a fifteen-minute window
+func NewEWMA15() *EWMA {
+	return NewEWMA(1 - math.Exp(-TickInterval.Seconds()/60/15))
+}
+
+// Update records n new events
+func (e *EWMA) Update(n int64) {
+	atomic.AddInt64(&e.uncounted, n)
+}
+
+// Tick folds the events recorded since the last tick into the moving average
+func (e *EWMA) Tick() {
+	count := atomic.SwapInt64(&e.uncounted, 0)
+	instantRate := float64(count) / TickInterval.Seconds()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.initialized {
+		e.rate += e.alpha * (instantRate - e.rate)
+	} else {
+		e.rate = instantRate
+		e.initialized = true
+	}
+}
+
+// Rate returns the moving average rate of events per second
+func (e *EWMA) Rate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rate
+}
+
+// CounterRates tracks 1, 5 and 15 minute moving average rates of a Counter
+// by sampling it every TickInterval.
+type CounterRates struct {
+	counter  *Counter
+	last     int64
+	m1       *EWMA
+	m5       *EWMA
+	m15      *EWMA
+	mu       sync.Mutex
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewCounterRates starts tracking the rates of the given counter until Stop is called
+func NewCounterRates(counter *Counter) *CounterRates {
+	r := &CounterRates{
+		counter: counter,
+		last:    counter.Count(),
+		m1:      NewEWMA1(),
+		m5:      NewEWMA5(),
+		m15:     NewEWMA15(),
+		stop:    make(chan struct{}),
+	}
+	go r.tickLoop()
+	return r
+}
+
+// Counter returns the wrapped counter
+func (r *CounterRates) Counter() *Counter {
+	return r.counter
+}
+
+// Rate1 returns the one-minute moving average rate of events per second
+func (r *CounterRates) Rate1() float64 {
+	return r.m1.Rate()
+}
+
+// Rate5 returns the five-minute moving average rate of events per second
+func (r *CounterRates) Rate5() float64 {
+	return r.m5.Rate()
+}
+
+// Rate15 returns the fifteen-minute moving average rate of events per second
+func (r *CounterRates) Rate15() float64 {
+	return r.m15.Rate()
+}
+
+// Tick samples the counter and updates the moving averages.
+// It's called automatically every TickInterval but can be invoked manually.
+func (r *CounterRates) Tick() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.counter.Count()
+	delta := count - r.last
+	if delta < 0 {
+		// Counter was reset
+		delta = count
+	}
+	r.last = count
+
+	for _, e := range []*EWMA{r.m1, r.m5, r.m15} {
+		e.Update(delta)
+		e.Tick()
+	}
+}
+
+// Stop terminates the sampling goroutine
+func (r *CounterRates) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (r *CounterRates) tickLoop() {
+	ticker := time.NewTicker(TickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Tick()
+		case <-r.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEWMA1(t *testing.T) {
+
+	e := NewEWMA1()
+	e.Update(3)
+	e.Tick()
+	assertEqual(t, 0.6, e.Rate())
+
+	// Decays after one minute without events
+	for i := 0; i < 12; i++ {
+		e.Tick()
+	}
+	if math.Abs(e.Rate()-0.22072766) > 1e-6 {
+		t.Errorf("Expected rate 0.22072766 but was %v", e.Rate())
+	}
+}
+
+func TestCounterRates(t *testing.T) {
+
+	c := NewCounter()
+	r := NewCounterRates(c)
+	defer r.Stop()
+
+	c.Add(10)
+	r.Tick()
+	assertEqual(t, 2.0, r.Rate1())
+	assertEqual(t, 2.0, r.Rate5())
+	assertEqual(t, 2.0, r.Rate15())
+
+	r.Tick()
+	if !(r.Rate1() < r.Rate5() && r.Rate5() < r.Rate15()) {
+		t.Errorf("Expected shorter windows to decay faster: %v %v %v", r.Rate1(), r.Rate5(), r.Rate15())
+	}
+}
@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Handler returns an http.Handler serving a JSON snapshot of the registry (or DefaultRegistry if nil),
+// e.g. mounted on /debug/stats for quick debugging without a metrics stack.
+// The namespace query parameter keeps the samples of the given namespaces only,
+// e.g. /debug/stats?namespace=http,db or /debug/stats?namespace=http&namespace=db
+func Handler(registry *Registry) http.Handler {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		snapshot := registry.Snapshot()
+		if namespaces := namespaceFilter(req); len(namespaces) > 0 {
+			samples := make([]Sample, 0, len(snapshot.Samples))
+			for _, sample := range snapshot.Samples {
+				if namespaces[sample.Namespace] {
+					samples = append(samples, sample)
+				}
+			}
+			snapshot.Samples = samples
+		}
+
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(data)
+	})
+}
+
+// namespaceFilter returns the namespaces requested with the namespace query parameter, nil for all
+func namespaceFilter(req *http.Request) map[string]bool {
+	var namespaces map[string]bool
+	for _, value := range req.URL.Query()["namespace"] {
+		for _, namespace := range strings.Split(value, ",") {
+			if namespace = strings.TrimSpace(namespace); namespace == "" {
+				continue
+			}
+			if namespaces == nil {
+				namespaces = make(map[string]bool)
+			}
+			namespaces[namespace] = true
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+
+	r := NewRegistry()
+	r.Counter("http", "requests").Add(2)
+	r.Gauge("db", "connections").Set(4)
+	h, _ := NewHdrHistogram(1, 1000, 3)
+	h.RecordValue(10)
+	r.Register("cache", "latency", h)
+
+	get := func(target string) Snapshot {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		Handler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "application/json", rec.Header().Get("Content-Type"))
+		var snapshot Snapshot
+		if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil {
+			t.Fatal(err)
+		}
+		return snapshot
+	}
+
+	assertEqual(t, 3, len(get("/debug/stats").Samples))
+
+	snapshot := get("/debug/stats?namespace=http")
+	assertEqual(t, 1, len(snapshot.Samples))
+	assertEqual(t, "requests", snapshot.Samples[0].Name)
+	assertEqual(t, 2.0, snapshot.Samples[0].Value)
+
+	snapshot = get("/debug/stats?namespace=db,cache")
+	assertEqual(t, 2, len(snapshot.Samples))
+	assertEqual(t, 10.0, snapshot.Samples[0].Fields["p99"])
+
+	assertEqual(t, 2, len(get("/debug/stats?namespace=db&namespace=http").Samples))
+	assertEqual(t, 0, len(get("/debug/stats?namespace=unknown").Samples))
+
+	rec := httptest.NewRecorder()
+	Handler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/stats", nil))
+	assertEqual(t, http.StatusMethodNotAllowed, rec.Code)
+}
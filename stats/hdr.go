@@ -0,0 +1,386 @@
+// Package stats provides concurrency-safe metric primitives and histograms.
+package stats
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+var (
+	// ErrInvalidHdrConfig is returned when a histogram is created with an invalid value range or precision
+	ErrInvalidHdrConfig = errors.New("invalid hdr histogram configuration")
+	// ErrValueOutOfRange is returned when recording a value outside of the trackable range of a histogram
+	ErrValueOutOfRange = errors.New("value out of histogram range")
+)
+
+// HdrHistogram is a High Dynamic Range histogram recording integer values
+// (typically latencies in nanoseconds or microseconds) with a fixed relative
+// precision over a configurable value range, so high percentiles such as p99.9
+// stay accurate. Exact min, max, sum and count are tracked alongside the buckets.
+//
+// HdrHistogram is safe for concurrent use by multiple goroutines.
+type HdrHistogram struct {
+	mu sync.Mutex
+
+	lowestTrackableValue        int64
+	highestTrackableValue       int64
+	significantFigures          int
+	unitMagnitude               int
+	subBucketHalfCountMagnitude int
+	subBucketCount              int
+	subBucketHalfCount          int
+	subBucketMask               int64
+	bucketCount                 int
+
+	counts     []int64
+	totalCount int64
+	min        int64
+	max        int64
+	sum        float64
+}
+
+// NewHdrHistogram creates a histogram able to track values between lowestTrackableValue (at least 1)
+// and highestTrackableValue (at least twice the lowest) while keeping significantFigures (1 to 5)
+// significant decimal digits of precision.
+func NewHdrHistogram(lowestTrackableValue, highestTrackableValue int64, significantFigures int) (*HdrHistogram, error) {
+	if lowestTrackableValue < 1 || highestTrackableValue < 2*lowestTrackableValue ||
+		significantFigures < 1 || significantFigures > 5 {
+		return nil, ErrInvalidHdrConfig
+	}
+
+	h := &HdrHistogram{
+		lowestTrackableValue:  lowestTrackableValue,
+		highestTrackableValue: highestTrackableValue,
+		significantFigures:    significantFigures,
+	}
+
+	largestValueWithSingleUnitResolution := 2 * math.Pow10(significantFigures)
+	subBucketCountMagnitude := int(math.Ceil(math.Log2(largestValueWithSingleUnitResolution)))
+	h.subBucketHalfCountMagnitude = subBucketCountMagnitude - 1
+	h.unitMagnitude = int(math.Floor(math.Log2(float64(lowestTrackableValue))))
+	h.subBucketCount = 1 << uint(h.subBucketHalfCountMagnitude+1)
+	h.subBucketHalfCount = h.subBucketCount / 2
+	h.subBucketMask = int64(h.subBucketCount-1) << uint(h.unitMagnitude)
+
+	// Determine the number of power-of-two buckets needed to cover the value range
+	smallestUntrackableValue := int64(h.subBucketCount) << uint(h.unitMagnitude)
+	h.bucketCount = 1
+	for smallestUntrackableValue <= highestTrackableValue {
+		if smallestUntrackableValue > math.MaxInt64/2 {
+			h.bucketCount++
+			break
+		}
+		smallestUntrackableValue <<= 1
+		h.bucketCount++
+	}
+
+	h.counts = make([]int64, (h.bucketCount+1)*h.subBucketHalfCount)
+	h.reset()
+
+	return h, nil
+}
+
+// LowestTrackableValue returns the lowest value the histogram can track
+func (h *HdrHistogram) LowestTrackableValue() int64 {
+	return h.lowestTrackableValue
+}
+
+// HighestTrackableValue returns the highest value the histogram can track
+func (h *HdrHistogram) HighestTrackableValue() int64 {
+	return h.highestTrackableValue
+}
+
+// SignificantFigures returns the number of significant decimal digits kept by the histogram
+func (h *HdrHistogram) SignificantFigures() int {
+	return h.significantFigures
+}
+
+// RecordValue records a single value
+func (h *HdrHistogram) RecordValue(v int64) error {
+	return h.RecordValues(v, 1)
+}
+
+// RecordValues records n occurrences of the given value
+func (h *HdrHistogram) RecordValues(v, n int64) error {
+	if n <= 0 {
+		return nil
+	}
+	if v < 0 || v > h.highestTrackableValue {
+		return ErrValueOutOfRange
+	}
+	idx := h.countsIndexFor(v)
+	if idx < 0 || idx >= len(h.counts) {
+		return ErrValueOutOfRange
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts[idx] += n
+	h.totalCount += n
+	h.sum += float64(v) * float64(n)
+	if v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	return nil
+}
+
+// TotalCount returns the number of recorded values
+func (h *HdrHistogram) TotalCount() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.totalCount
+}
+
+// Min returns the exact lowest recorded value, or 0 if the histogram is empty
+func (h *HdrHistogram) Min() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.totalCount == 0 {
+		return 0
+	}
+	return h.min
+}
+
+// Max returns the exact highest recorded value, or 0 if the histogram is empty
+func (h *HdrHistogram) Max() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.totalCount == 0 {
+		return 0
+	}
+	return h.max
+}
+
+// Sum returns the exact sum of all recorded values
+func (h *HdrHistogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+// Mean returns the exact mean of all recorded values, or 0 if the histogram is empty
+func (h *HdrHistogram) Mean() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.totalCount == 0 {
+		return 0
+	}
+	return h.sum / float64(h.totalCount)
+}
+
+// StdDev returns the approximate standard deviation of the recorded values
+func (h *HdrHistogram) StdDev() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.totalCount == 0 {
+		return 0
+	}
+
+	mean := h.sum / float64(h.totalCount)
+	geometricDevTotal := 0.0
+	for i, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+		dev := float64(h.medianEquivalentValue(h.valueFromCountsIndex(i))) - mean
+		geometricDevTotal += dev * dev * float64(count)
+	}
+	return math.Sqrt(geometricDevTotal / float64(h.totalCount))
+}
+
+// ValueAtPercentile returns the recorded value at the given percentile (0 to 100),
+// e.g. ValueAtPercentile(99.9). The result is accurate to the configured precision.
+func (h *HdrHistogram) ValueAtPercentile(percentile float64) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.valueAtPercentile(percentile)
+}
+
+// ValuesAtPercentiles returns the recorded values at the given percentiles under a single lock
+func (h *HdrHistogram) ValuesAtPercentiles(percentiles ...float64) []int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	values := make([]int64, len(percentiles))
+	for i, p := range percentiles {
+		values[i] = h.valueAtPercentile(p)
+	}
+	return values
+}
+
+// Merge adds all values recorded in other to this histogram, other may be the histogram itself.
+// Counts are merged bucket by bucket and the exact count, sum, min and max of other are kept.
+// Both histograms may use different ranges and precisions; values of other outside of the
+// trackable range of this histogram are dropped and reported with ErrValueOutOfRange.
+func (h *HdrHistogram) Merge(other *HdrHistogram) error {
+	if other == nil {
+		return nil
+	}
+
+	// Snapshot other under its own lock to avoid lock ordering issues and support self merges
+	other.mu.Lock()
+	counts := make([]int64, len(other.counts))
+	copy(counts, other.counts)
+	min, max, total, sum := other.min, other.max, other.totalCount, other.sum
+	other.mu.Unlock()
+
+	if total == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var (
+		err        error
+		dropped    int64
+		droppedSum float64
+	)
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		v := other.valueFromCountsIndex(i)
+		idx := -1
+		if v <= h.highestTrackableValue {
+			idx = h.countsIndexFor(v)
+		}
+		if idx < 0 || idx >= len(h.counts) {
+			dropped += count
+			droppedSum += float64(other.medianEquivalentValue(v)) * float64(count)
+			err = ErrValueOutOfRange
+			continue
+		}
+		h.counts[idx] += count
+	}
+	if dropped == total {
+		return err
+	}
+
+	h.totalCount += total - dropped
+	h.sum += sum - droppedSum
+	if min < h.min {
+		h.min = min
+	}
+	if max > h.highestTrackableValue {
+		max = h.highestTrackableValue
+	}
+	if max > h.max {
+		h.max = max
+	}
+	return err
+}
+
+// Reset clears all recorded values
+func (h *HdrHistogram) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reset()
+}
+
+func (h *HdrHistogram) reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.totalCount = 0
+	h.min = math.MaxInt64
+	h.max = 0
+	h.sum = 0
+}
+
+func (h *HdrHistogram) valueAtPercentile(percentile float64) int64 {
+	if h.totalCount == 0 {
+		return 0
+	}
+	if percentile > 100 {
+		percentile = 100
+	}
+	if percentile <= 0 {
+		return h.min
+	}
+
+	countAtPercentile := int64(percentile/100*float64(h.totalCount) + 0.5)
+	if countAtPercentile < 1 {
+		countAtPercentile = 1
+	}
+
+	var total int64
+	for i, count := range h.counts {
+		total += count
+		if total >= countAtPercentile {
+			v := h.highestEquivalentValue(h.valueFromCountsIndex(i))
+			// Never report values beyond the exact extremes
+			if v > h.max {
+				v = h.max
+			}
+			if v < h.min {
+				v = h.min
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+func (h *HdrHistogram) bucketIndex(v int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(v|h.subBucketMask))
+	return pow2Ceiling - h.unitMagnitude - (h.subBucketHalfCountMagnitude + 1)
+}
+
+func (h *HdrHistogram) subBucketIndex(v int64, bucketIdx int) int {
+	return int(v >> uint(bucketIdx+h.unitMagnitude))
+}
+
+func (h *HdrHistogram) countsIndex(bucketIdx, subBucketIdx int) int {
+	bucketBaseIdx := (bucketIdx + 1) << uint(h.subBucketHalfCountMagnitude)
+	offsetInBucket := subBucketIdx - h.subBucketHalfCount
+	return bucketBaseIdx + offsetInBucket
+}
+
+func (h *HdrHistogram) countsIndexFor(v int64) int {
+	bucketIdx := h.bucketIndex(v)
+	return h.countsIndex(bucketIdx, h.subBucketIndex(v, bucketIdx))
+}
+
+func (h *HdrHistogram) valueFromIndex(bucketIdx, subBucketIdx int) int64 {
+	return int64(subBucketIdx) << uint(bucketIdx+h.unitMagnitude)
+}
+
+func (h *HdrHistogram) valueFromCountsIndex(idx int) int64 {
+	bucketIdx := (idx >> uint(h.subBucketHalfCountMagnitude)) - 1
+	subBucketIdx := (idx & (h.subBucketHalfCount - 1)) + h.subBucketHalfCount
+	if bucketIdx < 0 {
+		subBucketIdx -= h.subBucketHalfCount
+		bucketIdx = 0
+	}
+	return h.valueFromIndex(bucketIdx, subBucketIdx)
+}
+
+func (h *HdrHistogram) sizeOfEquivalentValueRange(v int64) int64 {
+	bucketIdx := h.bucketIndex(v)
+	subBucketIdx := h.subBucketIndex(v, bucketIdx)
+	adjustedBucket := bucketIdx
+	if subBucketIdx >= h.subBucketCount {
+		adjustedBucket++
+	}
+	return int64(1) << uint(h.unitMagnitude+adjustedBucket)
+}
+
+func (h *HdrHistogram) lowestEquivalentValue(v int64) int64 {
+	bucketIdx := h.bucketIndex(v)
+	return h.valueFromIndex(bucketIdx, h.subBucketIndex(v, bucketIdx))
+}
+
+func (h *HdrHistogram) highestEquivalentValue(v int64) int64 {
+	return h.lowestEquivalentValue(v) + h.sizeOfEquivalentValueRange(v) - 1
+}
+
+func (h *HdrHistogram) medianEquivalentValue(v int64) int64 {
+	return h.lowestEquivalentValue(v) + h.sizeOfEquivalentValueRange(v)>>1
+}
@@ -0,0 +1,140 @@
+package stats
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func assertEqual(t *testing.T, expected interface{}, actual interface{}) {
+	if expected != actual {
+		t.Helper()
+		t.Errorf("Expected %T(%v) but was %T(%v)", expected, expected, actual, actual)
+	}
+}
+
+func assertWithin(t *testing.T, expected, actual int64, relativeError float64) {
+	if math.Abs(float64(actual-expected)) > float64(expected)*relativeError {
+		t.Helper()
+		t.Errorf("Expected %d (±%.3f%%) but was %d", expected, relativeError*100, actual)
+	}
+}
+
+func TestNewHdrHistogramWithInvalidConfig(t *testing.T) {
+
+	_, err := NewHdrHistogram(0, 100, 3)
+	assertEqual(t, ErrInvalidHdrConfig, err)
+
+	_, err = NewHdrHistogram(10, 15, 3)
+	assertEqual(t, ErrInvalidHdrConfig, err)
+
+	_, err = NewHdrHistogram(1, 100, 6)
+	assertEqual(t, ErrInvalidHdrConfig, err)
+}
+
+func TestHdrHistogramPercentiles(t *testing.T) {
+
+	h, err := NewHdrHistogram(1, 3600*1000*1000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(1); i <= 1000000; i++ {
+		if err := h.RecordValue(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertEqual(t, int64(1000000), h.TotalCount())
+	assertEqual(t, int64(1), h.Min())
+	assertEqual(t, int64(1000000), h.Max())
+	assertEqual(t, 500000.5, h.Mean())
+
+	assertWithin(t, 500000, h.ValueAtPercentile(50), 0.001)
+	assertWithin(t, 990000, h.ValueAtPercentile(99), 0.001)
+	assertWithin(t, 999000, h.ValueAtPercentile(99.9), 0.001)
+	assertEqual(t, int64(1000000), h.ValueAtPercentile(100))
+	assertEqual(t, int64(1), h.ValueAtPercentile(0))
+
+	values := h.ValuesAtPercentiles(50, 99.9)
+	assertEqual(t, h.ValueAtPercentile(50), values[0])
+	assertEqual(t, h.ValueAtPercentile(99.9), values[1])
+
+	assertWithin(t, 288675, int64(h.StdDev()), 0.001)
+}
+
+func TestHdrHistogramOutOfRange(t *testing.T) {
+
+	h, _ := NewHdrHistogram(1, 1000, 2)
+
+	assertEqual(t, ErrValueOutOfRange, h.RecordValue(-1))
+	assertEqual(t, ErrValueOutOfRange, h.RecordValue(1001))
+	assertEqual(t, nil, h.RecordValue(1000))
+	assertEqual(t, int64(1), h.TotalCount())
+}
+
+func TestHdrHistogramMerge(t *testing.T) {
+
+	a, _ := NewHdrHistogram(1, 100000, 3)
+	b, _ := NewHdrHistogram(1, 10000000, 2)
+
+	for i := int64(1); i <= 100; i++ {
+		a.RecordValue(i)
+		b.RecordValue(i * 1000)
+	}
+
+	assertEqual(t, nil, b.Merge(a))
+	assertEqual(t, int64(200), b.TotalCount())
+	assertEqual(t, int64(1), b.Min())
+	assertEqual(t, int64(100000), b.Max())
+
+	// Values above the range of a are reported
+	b.RecordValue(1000000)
+	assertEqual(t, ErrValueOutOfRange, a.Merge(b))
+	assertEqual(t, int64(300), a.TotalCount())
+
+	b.Reset()
+	assertEqual(t, int64(0), b.TotalCount())
+	assertEqual(t, int64(0), b.ValueAtPercentile(50))
+}
+
+func TestHdrHistogramMergeExact(t *testing.T) {
+
+	a, _ := NewHdrHistogram(1, 1000000, 1)
+	b, _ := NewHdrHistogram(1, 1000000, 1)
+
+	// Both values fall in the same bucket with a single significant figure
+	a.RecordValue(100001)
+	a.RecordValue(100003)
+	assertEqual(t, nil, b.Merge(a))
+	assertEqual(t, int64(100001), b.Min())
+	assertEqual(t, int64(100003), b.Max())
+	assertEqual(t, float64(200004), b.Sum())
+
+	// Merging a histogram into itself doubles its values
+	assertEqual(t, nil, b.Merge(b))
+	assertEqual(t, int64(4), b.TotalCount())
+	assertEqual(t, float64(400008), b.Sum())
+	assertEqual(t, int64(100001), b.Min())
+	assertEqual(t, int64(100003), b.Max())
+}
+
+func TestHdrHistogramConcurrentRecord(t *testing.T) {
+
+	h, _ := NewHdrHistogram(1, 1000000, 3)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int64(1); i <= 1000; i++ {
+				h.RecordValue(i)
+				h.ValueAtPercentile(99)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assertEqual(t, int64(8000), h.TotalCount())
+}
@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Counter is a monotonically increasing counter
+type Counter struct {
+	count int64
+}
+
+// NewCounter creates a counter starting at zero
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	atomic.AddInt64(&c.count, 1)
+}
+
+// Add increments the counter by n, negative values are ignored
+func (c *Counter) Add(n int64) {
+	if n <= 0 {
+		return
+	}
+	atomic.AddInt64(&c.count, n)
+}
+
+// Count returns the current value of the counter
+func (c *Counter) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+// Reset sets the counter back to zero
+func (c *Counter) Reset() {
+	atomic.StoreInt64(&c.count, 0)
+}
+
+// Gauge holds a float64 value that can go up and down
+type Gauge struct {
+	bits uint64
+}
+
+// NewGauge creates a gauge with a zero value
+func NewGauge() *Gauge {
+	return &Gauge{}
+}
+
+// Set replaces the value of the gauge
+func (g *Gauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+// Add adds delta (which may be negative) to the value of the gauge
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&g.bits, old, updated) {
+			return
+		}
+	}
+}
+
+// Value returns the current value of the gauge
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Meter counts events and measures the mean rate at which they occur
+type Meter struct {
+	count     int64
+	startedAt int64
+}
+
+// NewMeter creates a meter whose rate is measured from now
+func NewMeter() *Meter {
+	return &Meter{
+		startedAt: time.Now().UnixNano(),
+	}
+}
+
+// Mark records n events
+func (m *Meter) Mark(n int64) {
+	atomic.AddInt64(&m.count, n)
+}
+
+// Count returns the number of events recorded
+func (m *Meter) Count() int64 {
+	return atomic.LoadInt64(&m.count)
+}
+
+// RateMean returns the mean number of events per second since the meter was created
+func (m *Meter) RateMean() float64 {
+	elapsed := time.Since(time.Unix(0, atomic.LoadInt64(&m.startedAt))).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.Count()) / elapsed
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusHandler returns an http.Handler serving the registry (or DefaultRegistry if nil)
+// in the Prometheus text exposition format, e.g. mounted on /metrics.
+// Metrics are named <namespace>_<name> with their labels, histograms are exposed as summaries
+// and rates as additional gauges suffixed by the field name, e.g. http_requests_rate1.
+// The namespace query parameter filters the samples as in Handler.
+func PrometheusHandler(registry *Registry) http.Handler {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		samples := registry.Snapshot().Samples
+		if namespaces := namespaceFilter(req); len(namespaces) > 0 {
+			filtered := samples[:0]
+			for _, sample := range samples {
+				if namespaces[sample.Namespace] {
+					filtered = append(filtered, sample)
+				}
+			}
+			samples = filtered
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(formatPrometheus(samples))
+	})
+}
+
+// promFamily holds the lines of a metric family, which must be written together under a single TYPE line
+type promFamily struct {
+	name  string
+	kind  string
+	lines []string
+}
+
+func formatPrometheus(samples []Sample) []byte {
+	var families []*promFamily
+	index := make(map[string]*promFamily)
+	add := func(name, kind string, labels Labels, extra string, value float64) {
+		family := index[name]
+		if family == nil {
+			family = &promFamily{name: name, kind: kind}
+			index[name] = family
+			families = append(families, family)
+		}
+		family.lines = append(family.lines, name+promLabels(labels, extra)+" "+promValue(value))
+	}
+
+	for _, sample := range samples {
+		name := promName(sample.Namespace, sample.Name)
+		switch sample.Kind {
+		case KindCounter, KindMeter:
+			add(name, "counter", sample.Labels, "", sample.Value)
+			for _, field := range sortedFields(sample.Fields) {
+				add(name+"_"+promSanitize(field), "gauge", sample.Labels, "", sample.Fields[field])
+			}
+		case KindGauge:
+			add(name, "gauge", sample.Labels, "", sample.Value)
+		case KindHistogram:
+			for _, q := range []struct{ field, quantile string }{
+				{"p50", "0.5"}, {"p95", "0.95"}, {"p99", "0.99"}, {"p999", "0.999"},
+			} {
+				add(name, "summary", sample.Labels, `quantile="`+q.quantile+`"`, sample.Fields[q.field])
+			}
+			// the summary lines share the family of the quantiles
+			family := index[name]
+			family.lines = append(family.lines,
+				name+"_sum"+promLabels(sample.Labels, "")+" "+promValue(sample.Fields["mean"]*sample.Value),
+				name+"_count"+promLabels(sample.Labels, "")+" "+promValue(sample.Value))
+			add(name+"_min", "gauge", sample.Labels, "", sample.Fields["min"])
+			add(name+"_max", "gauge", sample.Labels, "", sample.Fields["max"])
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		buf.WriteString("# TYPE " + family.name + " " + family.kind + "\n")
+		for _, line := range family.lines {
+			buf.WriteString(line + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+func sortedFields(fields map[string]float64) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func promName(namespace, name string) string {
+	if namespace == "" {
+		return promSanitize(name)
+	}
+	return promSanitize(namespace + "_" + name)
+}
+
+// promSanitize replaces the characters not allowed in Prometheus metric and label names by '_'
+func promSanitize(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9' {
+			continue
+		}
+		b[i] = '_'
+	}
+	return string(b)
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promLabels formats the labels ordered by name, followed by an already formatted extra label
+func promLabels(labels Labels, extra string) string {
+	if len(labels) == 0 && extra == "" {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		pairs = append(pairs, promSanitize(name)+`="`+promEscaper.Replace(labels[name])+`"`)
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func promValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+
+	r := NewRegistry()
+	r.CounterWith("http", "requests", Labels{"code": "200"}).Add(2)
+	r.CounterWith("http", "requests", Labels{"code": "500"}).Inc()
+	r.GaugeWith("db", "open-connections", Labels{"dsn": `a"b`}).Set(-1.5)
+	h, _ := NewHdrHistogram(1, 1000, 3)
+	h.RecordValue(10)
+	h.RecordValue(20)
+	r.Register("cache", "latency", h)
+
+	get := func(target string) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		PrometheusHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+		return rec.Body.String()
+	}
+
+	expected := strings.Join([]string{
+		"# TYPE cache_latency summary",
+		`cache_latency{quantile="0.5"} 10`,
+		`cache_latency{quantile="0.95"} 20`,
+		`cache_latency{quantile="0.99"} 20`,
+		`cache_latency{quantile="0.999"} 20`,
+		"cache_latency_sum 30",
+		"cache_latency_count 2",
+		"# TYPE cache_latency_min gauge",
+		"cache_latency_min 10",
+		"# TYPE cache_latency_max gauge",
+		"cache_latency_max 20",
+		"# TYPE db_open_connections gauge",
+		`db_open_connections{dsn="a\"b"} -1.5`,
+		"# TYPE http_requests counter",
+		`http_requests{code="200"} 2`,
+		`http_requests{code="500"} 1`,
+		"",
+	}, "\n")
+	assertEqual(t, expected, get("/metrics"))
+
+	assertEqual(t, "# TYPE http_requests counter\nhttp_requests{code=\"200\"} 2\nhttp_requests{code=\"500\"} 1\n",
+		get("/metrics?namespace=http"))
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	assertEqual(t, http.StatusMethodNotAllowed, rec.Code)
+}
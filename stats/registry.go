@@ -0,0 +1,243 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	labelValueSeparator = "\xfe"
+	labelPairSeparator  = "\xff"
+)
+
+// Labels holds key/value dimensions attached to a metric (e.g. endpoint, tenant)
+type Labels map[string]string
+
+// encode returns a canonical representation of the labels, usable as a map key
+func (l Labels) encode() string {
+	if len(l) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteString(labelPairSeparator)
+		}
+		sb.WriteString(name)
+		sb.WriteString(labelValueSeparator)
+		sb.WriteString(l[name])
+	}
+	return sb.String()
+}
+
+// Key identifies a metric in a Registry
+type Key struct {
+	Namespace string
+	Name      string
+	labels    string
+}
+
+// NewKey creates a key from a namespace, a name and optional labels
+func NewKey(namespace, name string, labels Labels) Key {
+	return Key{Namespace: namespace, Name: name, labels: labels.encode()}
+}
+
+// Labels returns the labels attached to the key, or nil if there are none
+func (k Key) Labels() Labels {
+	if k.labels == "" {
+		return nil
+	}
+	pairs := strings.Split(k.labels, labelPairSeparator)
+	labels := make(Labels, len(pairs))
+	for _, pair := range pairs {
+		name, value, _ := strings.Cut(pair, labelValueSeparator)
+		labels[name] = value
+	}
+	return labels
+}
+
+// LabelNames returns the sorted names of the labels attached to the key
+func (k Key) LabelNames() []string {
+	if k.labels == "" {
+		return nil
+	}
+	pairs := strings.Split(k.labels, labelPairSeparator)
+	names := make([]string, len(pairs))
+	for i, pair := range pairs {
+		names[i], _, _ = strings.Cut(pair, labelValueSeparator)
+	}
+	return names
+}
+
+// String returns the dotted representation of the key followed by its labels, if any
+func (k Key) String() string {
+	name := k.Name
+	if k.Namespace != "" {
+		name = k.Namespace + "." + k.Name
+	}
+	if k.labels == "" {
+		return name
+	}
+
+	labels := k.Labels()
+	pairs := make([]string, 0, len(labels))
+	for _, label := range k.LabelNames() {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", label, labels[label]))
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Registry holds metrics keyed by namespace and name.
+// It's safe for concurrent use by multiple goroutines.
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[Key]any
+}
+
+// DefaultRegistry is a process-wide registry ready for use
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[Key]any),
+	}
+}
+
+// Register adds a metric under the given namespace and name.
+// It returns an error if a metric is already registered with the same key.
+func (r *Registry) Register(namespace, name string, metric any) error {
+	return r.RegisterWith(namespace, name, nil, metric)
+}
+
+// RegisterWith adds a metric under the given namespace, name and labels.
+// It returns an error if a metric is already registered with the same key.
+func (r *Registry) RegisterWith(namespace, name string, labels Labels, metric any) error {
+	key := NewKey(namespace, name, labels)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.metrics[key]; exists {
+		return fmt.Errorf("stats: metric %q already registered", key)
+	}
+	r.metrics[key] = metric
+	return nil
+}
+
+// Get returns the metric registered under the given namespace and name, or nil
+func (r *Registry) Get(namespace, name string) any {
+	return r.GetWith(namespace, name, nil)
+}
+
+// GetWith returns the metric registered under the given namespace, name and labels, or nil
+func (r *Registry) GetWith(namespace, name string, labels Labels) any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.metrics[NewKey(namespace, name, labels)]
+}
+
+// Unregister removes the metric registered under the given namespace and name
+func (r *Registry) Unregister(namespace, name string) {
+	r.UnregisterWith(namespace, name, nil)
+}
+
+// UnregisterWith removes the metric registered under the given namespace, name and labels
+func (r *Registry) UnregisterWith(namespace, name string, labels Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.metrics, NewKey(namespace, name, labels))
+}
+
+// Counter returns the counter registered under the given namespace and name, creating it if needed.
+// It panics if another kind of metric is registered with the same key.
+func (r *Registry) Counter(namespace, name string) *Counter {
+	return getOrRegister(r, NewKey(namespace, name, nil), NewCounter)
+}
+
+// CounterWith returns the counter registered under the given namespace, name and labels, creating it if needed.
+// It panics if another kind of metric is registered with the same key.
+func (r *Registry) CounterWith(namespace, name string, labels Labels) *Counter {
+	return getOrRegister(r, NewKey(namespace, name, labels), NewCounter)
+}
+
+// Gauge returns the gauge registered under the given namespace and name, creating it if needed.
+// It panics if another kind of metric is registered with the same key.
+func (r *Registry) Gauge(namespace, name string) *Gauge {
+	return getOrRegister(r, NewKey(namespace, name, nil), NewGauge)
+}
+
+// GaugeWith returns the gauge registered under the given namespace, name and labels, creating it if needed.
+// It panics if another kind of metric is registered with the same key.
+func (r *Registry) GaugeWith(namespace, name string, labels Labels) *Gauge {
+	return getOrRegister(r, NewKey(namespace, name, labels), NewGauge)
+}
+
+// Meter returns the meter registered under the given namespace and name, creating it if needed.
+// It panics if another kind of metric is registered with the same key.
+func (r *Registry) Meter(namespace, name string) *Meter {
+	return getOrRegister(r, NewKey(namespace, name, nil), NewMeter)
+}
+
+// MeterWith returns the meter registered under the given namespace, name and labels, creating it if needed.
+// It panics if another kind of metric is registered with the same key.
+func (r *Registry) MeterWith(namespace, name string, labels Labels) *Meter {
+	return getOrRegister(r, NewKey(namespace, name, labels), NewMeter)
+}
+
+// Each calls fn for every registered metric, ordered by key
+func (r *Registry) Each(fn func(key Key, metric any)) {
+	r.mu.RLock()
+	keys := make([]Key, 0, len(r.metrics))
+	for key := range r.metrics {
+		keys = append(keys, key)
+	}
+	metrics := make([]any, len(keys))
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].labels < keys[j].labels
+	})
+	for i, key := range keys {
+		metrics[i] = r.metrics[key]
+	}
+	r.mu.RUnlock()
+
+	// Call fn without holding the lock so it can use the registry
+	for i, key := range keys {
+		fn(key, metrics[i])
+	}
+}
+
+func getOrRegister[M any](r *Registry, key Key, create func() *M) *M {
+	r.mu.RLock()
+	metric, exists := r.metrics[key]
+	r.mu.RUnlock()
+
+	if !exists {
+		r.mu.Lock()
+		if metric, exists = r.metrics[key]; !exists {
+			metric = create()
+			r.metrics[key] = metric
+		}
+		r.mu.Unlock()
+	}
+
+	typed, ok := metric.(*M)
+	if !ok {
+		panic(fmt.Sprintf("stats: metric %q is a %T, not a %T", key, metric, typed))
+	}
+	return typed
+}
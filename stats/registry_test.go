@@ -0,0 +1,108 @@
+package stats
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCounterGaugeMeter(t *testing.T) {
+
+	c := NewCounter()
+	c.Inc()
+	c.Add(4)
+	c.Add(-10)
+	assertEqual(t, int64(5), c.Count())
+	c.Reset()
+	assertEqual(t, int64(0), c.Count())
+
+	g := NewGauge()
+	g.Set(1.5)
+	g.Add(-0.5)
+	assertEqual(t, 1.0, g.Value())
+
+	m := NewMeter()
+	m.Mark(3)
+	assertEqual(t, int64(3), m.Count())
+	if m.RateMean() <= 0 {
+		t.Errorf("Expected a positive mean rate but was %v", m.RateMean())
+	}
+}
+
+func TestRegistry(t *testing.T) {
+
+	r := NewRegistry()
+
+	r.Counter("http", "requests").Inc()
+	r.Counter("http", "requests").Inc()
+	r.Gauge("db", "connections").Set(3)
+	r.Meter("http", "bytes").Mark(10)
+
+	assertEqual(t, int64(2), r.Counter("http", "requests").Count())
+	assertEqual(t, nil, r.Register("app", "errors", NewCounter()))
+	if err := r.Register("app", "errors", NewCounter()); err == nil {
+		t.Error("Expected an error when registering a metric twice")
+	}
+
+	var keys []string
+	r.Each(func(key Key, metric any) {
+		keys = append(keys, key.String())
+	})
+	assertEqual(t, "[app.errors db.connections http.bytes http.requests]", fmt.Sprint(keys))
+
+	r.Unregister("app", "errors")
+	assertEqual(t, nil, r.Get("app", "errors"))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic when requesting a metric with a different kind")
+		}
+	}()
+	r.Gauge("http", "requests")
+}
+
+func TestRegistryConcurrentAccess(t *testing.T) {
+
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				r.Counter("pool", "tasks").Inc()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assertEqual(t, int64(8000), r.Counter("pool", "tasks").Count())
+}
+
+func TestRegistryLabels(t *testing.T) {
+
+	r := NewRegistry()
+
+	r.CounterWith("http", "requests", Labels{"endpoint": "/users", "tenant": "a"}).Inc()
+	r.CounterWith("http", "requests", Labels{"tenant": "a", "endpoint": "/users"}).Inc()
+	r.CounterWith("http", "requests", Labels{"endpoint": "/orders"}).Inc()
+	r.Counter("http", "requests").Add(5)
+
+	assertEqual(t, int64(2), r.CounterWith("http", "requests", Labels{"endpoint": "/users", "tenant": "a"}).Count())
+	assertEqual(t, int64(5), r.Counter("http", "requests").Count())
+
+	var keys []string
+	r.Each(func(key Key, metric any) {
+		keys = append(keys, key.String())
+	})
+	assertEqual(t, `[http.requests http.requests{endpoint="/orders"} http.requests{endpoint="/users",tenant="a"}]`, fmt.Sprint(keys))
+
+	key := NewKey("http", "requests", Labels{"tenant": "b", "endpoint": "/"})
+	assertEqual(t, "[endpoint tenant]", fmt.Sprint(key.LabelNames()))
+	assertEqual(t, "b", key.Labels()["tenant"])
+	assertEqual(t, 0, len(NewKey("http", "requests", nil).Labels()))
+
+	r.UnregisterWith("http", "requests", Labels{"endpoint": "/orders"})
+	assertEqual(t, nil, r.GetWith("http", "requests", Labels{"endpoint": "/orders"}))
+}
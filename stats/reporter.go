@@ -0,0 +1,217 @@
+package stats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dreamsxin/go-utils/bus"
+)
+
+// Sink receives the snapshots taken by a Reporter
+type Sink interface {
+	Send(ctx context.Context, snapshot Snapshot) error
+}
+
+// SinkFunc adapts a function to the Sink interface
+type SinkFunc func(ctx context.Context, snapshot Snapshot) error
+
+// Send calls f(ctx, snapshot)
+func (f SinkFunc) Send(ctx context.Context, snapshot Snapshot) error {
+	return f(ctx, snapshot)
+}
+
+// LogSink writes one line per sample to the given logger (or the standard logger if nil)
+func LogSink(logger *log.Logger) Sink {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return SinkFunc(func(ctx context.Context, snapshot Snapshot) error {
+		for _, sample := range snapshot.Samples {
+			logger.Println(formatSample(sample))
+		}
+		return nil
+	})
+}
+
+// WriterSink writes each snapshot as a JSON line to w
+func WriterSink(w io.Writer) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(ctx context.Context, snapshot Snapshot) error {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, err = w.Write(append(data, '\n'))
+		return err
+	})
+}
+
+// FileSink appends each snapshot as a JSON line to the file at path, creating it if needed
+func FileSink(path string) Sink {
+	return SinkFunc(func(ctx context.Context, snapshot Snapshot) error {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		if err := WriterSink(f).Send(ctx, snapshot); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// BusSink publishes each snapshot on the given bus as a *Snapshot message
+func BusSink(b bus.Bus) Sink {
+	return SinkFunc(func(ctx context.Context, snapshot Snapshot) error {
+		return b.Publish(ctx, &snapshot)
+	})
+}
+
+// HTTPSink posts each snapshot as JSON to the given URL using client (or http.DefaultClient if nil)
+func HTTPSink(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return SinkFunc(func(ctx context.Context, snapshot Snapshot) error {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("stats: http sink received status %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+// ReporterOption represents an option that can be passed when instantiating a reporter to customize it
+type ReporterOption func(*Reporter)
+
+// ReportErrorHandler allows to change the function invoked when sinks fail during periodic reports.
+// By default errors are written to the standard logger.
+func ReportErrorHandler(handler func(error)) ReporterOption {
+	return func(r *Reporter) {
+		r.errorHandler = handler
+	}
+}
+
+// Reporter periodically takes a snapshot of a Registry and sends it to every sink
+type Reporter struct {
+	registry     *Registry
+	sinks        []Sink
+	errorHandler func(error)
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewReporter creates a reporter sending snapshots of registry to sinks every interval until Stop is called.
+// A non-positive interval disables periodic reports, leaving only explicit calls to Report.
+func NewReporter(registry *Registry, interval time.Duration, sinks []Sink, options ...ReporterOption) *Reporter {
+	r := &Reporter{
+		registry: registry,
+		sinks:    sinks,
+		errorHandler: func(err error) {
+			log.Printf("stats: report failed: %v", err)
+		},
+		done: make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	go r.reportLoop(interval)
+
+	return r
+}
+
+// Report takes a snapshot of the registry and sends it to every sink immediately
+func (r *Reporter) Report(ctx context.Context) error {
+	snapshot := r.registry.Snapshot()
+
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Send(ctx, snapshot); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stop terminates the reporting goroutine and waits for an in-flight report to complete
+func (r *Reporter) Stop() {
+	r.stopOnce.Do(func() {
+		r.cancel()
+		<-r.done
+	})
+}
+
+func (r *Reporter) reportLoop(interval time.Duration) {
+	defer close(r.done)
+
+	if interval <= 0 {
+		<-r.ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Report(r.ctx); err != nil && r.errorHandler != nil {
+				r.errorHandler(err)
+			}
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// formatSample returns a human-readable representation of a sample
+func formatSample(sample Sample) string {
+	var sb strings.Builder
+	sb.WriteString(NewKey(sample.Namespace, sample.Name, sample.Labels).String())
+	fmt.Fprintf(&sb, " %s=%g", sample.Kind, sample.Value)
+
+	names := make([]string, 0, len(sample.Fields))
+	for name := range sample.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, " %s=%g", name, sample.Fields[name])
+	}
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dreamsxin/go-utils/bus"
+)
+
+func TestRegistrySnapshot(t *testing.T) {
+
+	r := NewRegistry()
+	r.CounterWith("http", "requests", Labels{"endpoint": "/"}).Add(2)
+	r.Gauge("db", "connections").Set(4)
+	h, _ := NewHdrHistogram(1, 1000, 3)
+	h.RecordValue(10)
+	r.Register("http", "latency", h)
+	r.Register("app", "unknown", "not a metric")
+
+	snapshot := r.Snapshot()
+	assertEqual(t, 3, len(snapshot.Samples))
+	assertEqual(t, KindGauge, snapshot.Samples[0].Kind)
+	assertEqual(t, 4.0, snapshot.Samples[0].Value)
+	assertEqual(t, KindHistogram, snapshot.Samples[1].Kind)
+	assertEqual(t, 10.0, snapshot.Samples[1].Fields["p99"])
+	assertEqual(t, "/", snapshot.Samples[2].Labels["endpoint"])
+}
+
+func TestReporterSinks(t *testing.T) {
+
+	r := NewRegistry()
+	r.Counter("http", "requests").Add(2)
+
+	var logs bytes.Buffer
+	var lines bytes.Buffer
+	path := filepath.Join(t.TempDir(), "stats.log")
+
+	var received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var snapshot Snapshot
+		if err := json.NewDecoder(req.Body).Decode(&snapshot); err == nil && len(snapshot.Samples) == 1 {
+			atomic.AddInt32(&received, 1)
+		}
+	}))
+	defer server.Close()
+
+	b := bus.ProvideBus()
+	var published int32
+	b.AddEventListener(func(ctx context.Context, snapshot *Snapshot) error {
+		atomic.AddInt32(&published, 1)
+		return nil
+	})
+
+	reporter := NewReporter(r, 0, []Sink{
+		LogSink(log.New(&logs, "", 0)),
+		WriterSink(&lines),
+		FileSink(path),
+		BusSink(b),
+		HTTPSink(server.URL, nil),
+	})
+	defer reporter.Stop()
+
+	assertEqual(t, nil, reporter.Report(context.Background()))
+
+	assertEqual(t, "http.requests counter=2\n", logs.String())
+	if !strings.Contains(lines.String(), `"name":"requests"`) {
+		t.Errorf("Expected a JSON line but was %s", lines.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, lines.String(), string(data))
+	assertEqual(t, int32(1), atomic.LoadInt32(&published))
+	assertEqual(t, int32(1), atomic.LoadInt32(&received))
+}
+
+func TestReporterPeriodicReport(t *testing.T) {
+
+	r := NewRegistry()
+
+	var reports int32
+	var failures int32
+	reporter := NewReporter(r, 10*time.Millisecond, []Sink{
+		SinkFunc(func(ctx context.Context, snapshot Snapshot) error {
+			atomic.AddInt32(&reports, 1)
+			return errors.New("sink failed")
+		}),
+	}, ReportErrorHandler(func(err error) {
+		atomic.AddInt32(&failures, 1)
+	}))
+
+	time.Sleep(55 * time.Millisecond)
+	reporter.Stop()
+
+	if atomic.LoadInt32(&reports) < 2 {
+		t.Errorf("Expected at least 2 reports but was %d", reports)
+	}
+	assertEqual(t, atomic.LoadInt32(&reports), atomic.LoadInt32(&failures))
+}
@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"time"
+)
+
+// Metric kinds reported in samples
+const (
+	KindCounter   = "counter"
+	KindGauge     = "gauge"
+	KindMeter     = "meter"
+	KindHistogram = "histogram"
+)
+
+// Sample holds the point-in-time value of a registered metric.
+// Value is the count of counters, meters and histograms and the value of gauges,
+// Fields holds additional values such as rates and percentiles.
+type Sample struct {
+	Namespace string             `json:"namespace,omitempty"`
+	Name      string             `json:"name"`
+	Labels    Labels             `json:"labels,omitempty"`
+	Kind      string             `json:"kind"`
+	Value     float64            `json:"value"`
+	Fields    map[string]float64 `json:"fields,omitempty"`
+}
+
+// Snapshot holds the samples taken from a registry at a given time
+type Snapshot struct {
+	Time    time.Time `json:"time"`
+	Samples []Sample  `json:"samples"`
+}
+
+// Snapshot samples every metric of the registry, ordered by key.
+// Metrics of unknown types are skipped.
+func (r *Registry) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Time:    time.Now(),
+		Samples: make([]Sample, 0),
+	}
+
+	r.Each(func(key Key, metric any) {
+		sample := Sample{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    key.Labels(),
+		}
+
+		switch m := metric.(type) {
+		case *Counter:
+			sample.Kind = KindCounter
+			sample.Value = float64(m.Count())
+		case *CounterRates:
+			sample.Kind = KindCounter
+			sample.Value = float64(m.Counter().Count())
+			sample.Fields = map[string]float64{
+				"rate1":  m.Rate1(),
+				"rate5":  m.Rate5(),
+				"rate15": m.Rate15(),
+			}
+		case *Gauge:
+			sample.Kind = KindGauge
+			sample.Value = m.Value()
+		case *Meter:
+			sample.Kind = KindMeter
+			sample.Value = float64(m.Count())
+			sample.Fields = map[string]float64{
+				"rate_mean": m.RateMean(),
+			}
+		case *HdrHistogram:
+			sample.Kind = KindHistogram
+			sample.Value, sample.Fields = histogramFields(m)
+		case *WindowedHistogram:
+			sample.Kind = KindHistogram
+			sample.Value, sample.Fields = histogramFields(m.Snapshot())
+		default:
+			return
+		}
+
+		snapshot.Samples = append(snapshot.Samples, sample)
+	})
+
+	return snapshot
+}
+
+func histogramFields(h *HdrHistogram) (float64, map[string]float64) {
+	percentiles := h.ValuesAtPercentiles(50, 95, 99, 99.9)
+	return float64(h.TotalCount()), map[string]float64{
+		"min":  float64(h.Min()),
+		"max":  float64(h.Max()),
+		"mean": h.Mean(),
+		"p50":  float64(percentiles[0]),
+		"p95":  float64(percentiles[1]),
+		"p99":  float64(percentiles[2]),
+		"p999": float64(percentiles[3]),
+	}
+}
@@ -0,0 +1,195 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxStatsdPacketSize keeps packets below the usual network MTU
+const maxStatsdPacketSize = 1432
+
+// StatsdReporter periodically flushes the metrics of a Registry to a StatsD server over UDP.
+// Counters and meters are sent as count deltas, gauges as gauges and histograms as
+// gauges of their count, min, max, mean and main percentiles. Labels are sent as
+// DogStatsD-style tags.
+type StatsdReporter struct {
+	registry *Registry
+	conn     net.Conn
+	prefix   string
+
+	mu         sync.Mutex
+	lastCounts map[Key]int64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewStatsdReporter connects to the StatsD server at addr (host:port) and flushes the registry
+// every interval until Stop is called. All metric names are prefixed with prefix, if not empty.
+func NewStatsdReporter(registry *Registry, addr string, interval time.Duration, prefix string) (*StatsdReporter, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("stats: invalid statsd flush interval %v", interval)
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &StatsdReporter{
+		registry:   registry,
+		conn:       conn,
+		prefix:     strings.TrimSuffix(prefix, "."),
+		lastCounts: make(map[Key]int64),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go r.flushLoop(interval)
+
+	return r, nil
+}
+
+// Flush sends the current value of every metric of the registry
+func (r *StatsdReporter) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var packet bytes.Buffer
+	var err error
+	write := func(line string) {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxStatsdPacketSize {
+			if _, writeErr := r.conn.Write(packet.Bytes()); writeErr != nil {
+				err = writeErr
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+
+	r.registry.Each(func(key Key, metric any) {
+		for _, line := range r.format(key, metric) {
+			write(line)
+		}
+	})
+
+	if packet.Len() > 0 {
+		if _, writeErr := r.conn.Write(packet.Bytes()); writeErr != nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+// Stop flushes the registry one last time and closes the connection
+func (r *StatsdReporter) Stop() error {
+	var err error
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		err = r.Flush()
+		if closeErr := r.conn.Close(); err == nil {
+			err = closeErr
+		}
+	})
+	return err
+}
+
+func (r *StatsdReporter) flushLoop(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Flush()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// format returns the StatsD lines describing a metric
+func (r *StatsdReporter) format(key Key, metric any) []string {
+	name := key.Namespace + "." + key.Name
+	if key.Namespace == "" {
+		name = key.Name
+	}
+	if r.prefix != "" {
+		name = r.prefix + "." + name
+	}
+	tags := statsdTags(key)
+
+	switch m := metric.(type) {
+	case *Counter:
+		return []string{r.formatDelta(key, name, m.Count(), tags)}
+	case *CounterRates:
+		return append([]string{r.formatDelta(key, name, m.Counter().Count(), tags)},
+			formatStatsdGauge(name+".rate1", m.Rate1(), tags)...)
+	case *Meter:
+		return []string{r.formatDelta(key, name, m.Count(), tags)}
+	case *Gauge:
+		return formatStatsdGauge(name, m.Value(), tags)
+	case *HdrHistogram:
+		return formatStatsdHistogram(name, m, tags)
+	case *WindowedHistogram:
+		return formatStatsdHistogram(name, m.Snapshot(), tags)
+	}
+	return nil
+}
+
+// formatDelta returns a counter line with the increment since the previous flush
+func (r *StatsdReporter) formatDelta(key Key, name string, count int64, tags string) string {
+	delta := count - r.lastCounts[key]
+	if delta < 0 {
+		// Counter was reset
+		delta = count
+	}
+	r.lastCounts[key] = count
+	return fmt.Sprintf("%s:%d|c%s", name, delta, tags)
+}
+
+// formatStatsdGauge returns the lines setting a gauge, a signed value would be read as a change
+// so a negative one is sent after resetting the gauge to 0
+func formatStatsdGauge(name string, value float64, tags string) []string {
+	line := fmt.Sprintf("%s:%g|g%s", name, value, tags)
+	if value < 0 {
+		return []string{fmt.Sprintf("%s:0|g%s", name, tags), line}
+	}
+	return []string{line}
+}
+
+func formatStatsdHistogram(name string, h *HdrHistogram, tags string) []string {
+	percentiles := h.ValuesAtPercentiles(50, 95, 99, 99.9)
+	return []string{
+		fmt.Sprintf("%s.count:%d|g%s", name, h.TotalCount(), tags),
+		fmt.Sprintf("%s.min:%d|g%s", name, h.Min(), tags),
+		fmt.Sprintf("%s.max:%d|g%s", name, h.Max(), tags),
+		fmt.Sprintf("%s.mean:%g|g%s", name, h.Mean(), tags),
+		fmt.Sprintf("%s.p50:%d|g%s", name, percentiles[0], tags),
+		fmt.Sprintf("%s.p95:%d|g%s", name, percentiles[1], tags),
+		fmt.Sprintf("%s.p99:%d|g%s", name, percentiles[2], tags),
+		fmt.Sprintf("%s.p999:%d|g%s", name, percentiles[3], tags),
+	}
+}
+
+func statsdTags(key Key) string {
+	names := key.LabelNames()
+	if len(names) == 0 {
+		return ""
+	}
+	labels := key.Labels()
+	tags := make([]string, len(names))
+	for i, name := range names {
+		tags[i] = name + ":" + labels[name]
+	}
+	return "|#" + strings.Join(tags, ",")
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readStatsdPacket(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+
+	buf := make([]byte, 65536)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+func TestStatsdReporter(t *testing.T) {
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	r := NewRegistry()
+	r.Counter("http", "requests").Add(3)
+	r.GaugeWith("db", "connections", Labels{"shard": "1"}).Set(2.5)
+	h, _ := NewHdrHistogram(1, 1000, 3)
+	h.RecordValue(100)
+	r.Register("http", "latency", h)
+
+	reporter, err := NewStatsdReporter(r, server.LocalAddr().String(), time.Hour, "app.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, nil, reporter.Flush())
+	lines := strings.Split(readStatsdPacket(t, server), "\n")
+	assertEqual(t, "app.db.connections:2.5|g|#shard:1", lines[0])
+	assertEqual(t, "app.http.latency.count:1|g", lines[1])
+	assertEqual(t, "app.http.latency.p999:100|g", lines[8])
+	assertEqual(t, "app.http.requests:3|c", lines[9])
+
+	// Counters are sent as deltas
+	r.Counter("http", "requests").Inc()
+	assertEqual(t, nil, reporter.Stop())
+	lines = strings.Split(readStatsdPacket(t, server), "\n")
+	assertEqual(t, "app.http.requests:1|c", lines[9])
+}
+
+func TestNewStatsdReporterWithInvalidInterval(t *testing.T) {
+
+	_, err := NewStatsdReporter(NewRegistry(), "127.0.0.1:8125", 0, "")
+	if err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+}
+
+func TestStatsdReporterGaugesAndRates(t *testing.T) {
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	r := NewRegistry()
+	r.Gauge("queue", "balance").Set(-3)
+	rates := NewCounterRates(NewCounter())
+	defer rates.Stop()
+	rates.Counter().Add(5)
+	r.Register("http", "hits", rates)
+
+	reporter, err := NewStatsdReporter(r, server.LocalAddr().String(), time.Hour, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reporter.Stop()
+
+	assertEqual(t, nil, reporter.Flush())
+	lines := strings.Split(readStatsdPacket(t, server), "\n")
+	assertEqual(t, "http.hits:5|c", lines[0])
+	assertEqual(t, "http.hits.rate1:0|g", lines[1])
+	// A negative gauge is reset first, "-3" alone would be a decrement
+	assertEqual(t, "queue.balance:0|g", lines[2])
+	assertEqual(t, "queue.balance:-3|g", lines[3])
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"time"
+)
+
+// ValueRecorder is implemented by histograms able to record integer values,
+// such as HdrHistogram and WindowedHistogram
+type ValueRecorder interface {
+	RecordValue(v int64) error
+}
+
+// Timer measures the time elapsed since it was started
+type Timer struct {
+	startedAt time.Time
+}
+
+// StartTimer creates a timer starting now. Typical usage is:
+//
+//	t := stats.StartTimer()
+//	defer t.ObserveDuration(latency)
+func StartTimer() Timer {
+	return Timer{
+		startedAt: time.Now(),
+	}
+}
+
+// Elapsed returns the time elapsed since the timer was started
+func (t Timer) Elapsed() time.Duration {
+	return time.Since(t.startedAt)
+}
+
+// ObserveDuration records the elapsed time in nanoseconds into the given recorder and returns it
+func (t Timer) ObserveDuration(recorder ValueRecorder) time.Duration {
+	return t.ObserveDurationIn(recorder, time.Nanosecond)
+}
+
+// ObserveDurationIn records the elapsed time expressed in the given unit
+// (e.g. time.Microsecond) into the given recorder and returns it
+func (t Timer) ObserveDurationIn(recorder ValueRecorder, unit time.Duration) time.Duration {
+	elapsed := t.Elapsed()
+	if unit <= 0 {
+		unit = time.Nanosecond
+	}
+	recorder.RecordValue(int64(elapsed / unit))
+	return elapsed
+}
+
+// Time executes fn and records its duration in nanoseconds into the given recorder
+func Time(recorder ValueRecorder, fn func()) time.Duration {
+	t := StartTimer()
+	fn()
+	return t.ObserveDuration(recorder)
+}
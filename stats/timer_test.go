@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+
+	h, _ := NewHdrHistogram(1, int64(time.Minute), 3)
+
+	func() {
+		timer := StartTimer()
+		defer timer.ObserveDuration(h)
+		time.Sleep(10 * time.Millisecond)
+	}()
+
+	assertEqual(t, int64(1), h.TotalCount())
+	if h.Min() < int64(10*time.Millisecond) {
+		t.Errorf("Expected at least 10ms to be recorded but was %v", time.Duration(h.Min()))
+	}
+
+	elapsed := Time(h, func() {
+		time.Sleep(time.Millisecond)
+	})
+	assertEqual(t, int64(2), h.TotalCount())
+	if elapsed < time.Millisecond {
+		t.Errorf("Expected at least 1ms to elapse but was %v", elapsed)
+	}
+}
+
+func TestTimerObserveDurationIn(t *testing.T) {
+
+	h, _ := NewHdrHistogram(1, 1000, 3)
+
+	timer := StartTimer()
+	time.Sleep(2 * time.Millisecond)
+	timer.ObserveDurationIn(h, time.Millisecond)
+
+	assertEqual(t, int64(1), h.TotalCount())
+	if h.Min() < 2 {
+		t.Errorf("Expected at least 2ms to be recorded but was %d", h.Min())
+	}
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// WindowedHistogram is a sliding-window histogram that only reflects values recorded
+// during the last window. The window is split into a ring of sub-histograms which are
+// rotated on a ticker: every window/slots the oldest sub-histogram is cleared and
+// becomes the one receiving new values.
+//
+// WindowedHistogram is safe for concurrent use by multiple goroutines.
+type WindowedHistogram struct {
+	mu       sync.RWMutex
+	slots    []*HdrHistogram
+	current  int
+	window   time.Duration
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewWindowedHistogram creates a histogram covering the last window, split in the given number of slots.
+// The value range and precision parameters are the same as for NewHdrHistogram.
+// The rotation goroutine runs until Stop is called.
+// It panics when the window is shorter than a nanosecond per slot.
+func NewWindowedHistogram(window time.Duration, slots int, lowestTrackableValue, highestTrackableValue int64, significantFigures int) (*WindowedHistogram, error) {
+	if window <= 0 || slots < 1 {
+		return nil, ErrInvalidHdrConfig
+	}
+	if window/time.Duration(slots) <= 0 {
+		panic("stats.NewWindowedHistogram(): window must be at least one nanosecond per slot")
+	}
+
+	w := &WindowedHistogram{
+		slots:  make([]*HdrHistogram, slots),
+		window: window,
+		stop:   make(chan struct{}),
+	}
+	for i := range w.slots {
+		h, err := NewHdrHistogram(lowestTrackableValue, highestTrackableValue, significantFigures)
+		if err != nil {
+			return nil, err
+		}
+		w.slots[i] = h
+	}
+
+	go w.rotateLoop(window / time.Duration(slots))
+
+	return w, nil
+}
+
+// Window returns the duration covered by the histogram
+func (w *WindowedHistogram) Window() time.Duration {
+	return w.window
+}
+
+// RecordValue records a single value in the current slot
+func (w *WindowedHistogram) RecordValue(v int64) error {
+	return w.RecordValues(v, 1)
+}
+
+// RecordValues records n occurrences of the given value in the current slot
+func (w *WindowedHistogram) RecordValues(v, n int64) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.slots[w.current].RecordValues(v, n)
+}
+
+// Snapshot returns a new histogram merging all the values recorded during the window
+func (w *WindowedHistogram) Snapshot() *HdrHistogram {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	first := w.slots[0]
+	snapshot, _ := NewHdrHistogram(first.LowestTrackableValue(), first.HighestTrackableValue(), first.SignificantFigures())
+	for _, h := range w.slots {
+		snapshot.Merge(h)
+	}
+	return snapshot
+}
+
+// ValueAtPercentile returns the value at the given percentile (0 to 100) over the window
+func (w *WindowedHistogram) ValueAtPercentile(percentile float64) int64 {
+	return w.Snapshot().ValueAtPercentile(percentile)
+}
+
+// TotalCount returns the number of values recorded during the window
+func (w *WindowedHistogram) TotalCount() int64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var total int64
+	for _, h := range w.slots {
+		total += h.TotalCount()
+	}
+	return total
+}
+
+// Rotate discards the oldest slot and makes it the current one.
+// It's called automatically on every tick but can be invoked manually.
+func (w *WindowedHistogram) Rotate() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.current = (w.current + 1) % len(w.slots)
+	w.slots[w.current].Reset()
+}
+
+// Stop terminates the rotation goroutine. Values recorded afterwards are kept indefinitely.
+func (w *WindowedHistogram) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
+
+func (w *WindowedHistogram) rotateLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.Rotate()
+		case <-w.stop:
+			return
+		}
+	}
+}
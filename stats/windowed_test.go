@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowedHistogramRotate(t *testing.T) {
+
+	w, err := NewWindowedHistogram(time.Hour, 3, 1, 1000000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	w.RecordValue(1000)
+	w.Rotate()
+	w.RecordValue(2000)
+	w.Rotate()
+	w.RecordValue(3000)
+
+	assertEqual(t, int64(3), w.TotalCount())
+	assertEqual(t, int64(1000), w.Snapshot().Min())
+	assertEqual(t, int64(3000), w.ValueAtPercentile(100))
+
+	// Oldest slot is discarded
+	w.Rotate()
+	assertEqual(t, int64(2), w.TotalCount())
+	assertEqual(t, int64(2000), w.Snapshot().Min())
+
+	w.Rotate()
+	w.Rotate()
+	assertEqual(t, int64(0), w.TotalCount())
+}
+
+func TestWindowedHistogramTicker(t *testing.T) {
+
+	w, _ := NewWindowedHistogram(50*time.Millisecond, 5, 1, 1000, 2)
+	defer w.Stop()
+
+	w.RecordValue(10)
+	assertEqual(t, int64(1), w.TotalCount())
+
+	time.Sleep(150 * time.Millisecond)
+	assertEqual(t, int64(0), w.TotalCount())
+}
+
+func TestNewWindowedHistogramWithInvalidConfig(t *testing.T) {
+
+	_, err := NewWindowedHistogram(0, 5, 1, 1000, 2)
+	assertEqual(t, ErrInvalidHdrConfig, err)
+
+	_, err = NewWindowedHistogram(time.Minute, 5, 0, 1000, 2)
+	assertEqual(t, ErrInvalidHdrConfig, err)
+}
+
+func TestNewWindowedHistogramWithTooManySlots(t *testing.T) {
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected a panic when the window is shorter than a nanosecond per slot")
+		}
+	}()
+	NewWindowedHistogram(5, 10, 1, 1000, 2)
+}
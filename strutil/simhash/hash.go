@@ -14,6 +14,8 @@ import (
 	"bytes"
 	"hash/fnv"
 	"regexp"
+	"slices"
+	"strings"
 
 	"golang.org/x/text/unicode/norm"
 )
@@ -163,15 +165,34 @@ func SetCreateFeature(nf FuncCreateFeature) WordFeatureOption {
 	return func(w *WordFeatureSet) { w.nf = nf }
 }
 
+// SetTokenizer changes how words are split, e.g. with Tokenize for Chinese or Japanese documents
+func SetTokenizer(tokenize func([]byte) [][]byte) WordFeatureOption {
+	return func(w *WordFeatureSet) { w.tokenize = tokenize }
+}
+
+// SetStopwords drops the given words, e.g. EnglishStopwords or ChineseStopwords,
+// so frequent function words don't dominate the fingerprint.
+// Words are compared lower-cased with the words of the document.
+func SetStopwords(stopwords []string) WordFeatureOption {
+	return func(w *WordFeatureSet) {
+		w.stopwords = make(map[string]struct{}, len(stopwords))
+		for _, word := range stopwords {
+			w.stopwords[strings.ToLower(word)] = struct{}{}
+		}
+	}
+}
+
 // WordFeatureSet is a feature set in which each word is a feature,
 // all equal weight.
 type WordFeatureSet struct {
-	b  []byte
-	nf FuncCreateFeature
+	b         []byte
+	nf        FuncCreateFeature
+	tokenize  func([]byte) [][]byte
+	stopwords map[string]struct{}
 }
 
 func NewWordFeatureSet(b []byte, opts ...WordFeatureOption) *WordFeatureSet {
-	fs := &WordFeatureSet{b, NewFeature}
+	fs := &WordFeatureSet{b: b, nf: NewFeature}
 	for _, opt := range opts {
 		if opt != nil {
 			opt(fs)
@@ -190,7 +211,19 @@ var unicodeBoundaries = regexp.MustCompile(`[\pL-_']+`)
 
 // Returns a []Feature representing each word in the byte slice
 func (w *WordFeatureSet) GetFeatures() []Feature {
-	return getFeatures(w.b, boundaries, w.nf)
+	var words [][]byte
+	if w.tokenize != nil {
+		words = w.tokenize(w.b)
+	} else {
+		words = boundaries.FindAll(w.b, -1)
+	}
+	if len(w.stopwords) > 0 {
+		words = slices.DeleteFunc(words, func(word []byte) bool {
+			_, ok := w.stopwords[string(word)]
+			return ok
+		})
+	}
+	return newFeatures(words, w.nf)
 }
 
 type UnicodeWordFeatureOption func(*UnicodeWordFeatureSet)
@@ -232,7 +265,11 @@ func (w *UnicodeWordFeatureSet) GetFeatures() []Feature {
 // Splits the given []byte using the given regexp, then returns a slice
 // containing a Feature constructed from each piece matched by the regexp
 func getFeatures(b []byte, r *regexp.Regexp, nf FuncCreateFeature) []Feature {
-	words := r.FindAll(b, -1)
+	return newFeatures(r.FindAll(b, -1), nf)
+}
+
+// newFeatures returns a Feature constructed from each word
+func newFeatures(words [][]byte, nf FuncCreateFeature) []Feature {
 	features := make([]Feature, len(words))
 	for i, w := range words {
 		if nf != nil {
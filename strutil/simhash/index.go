@@ -0,0 +1,162 @@
+package simhash
+
+import (
+	"slices"
+	"sync"
+)
+
+// Match is a fingerprint found by Index.Near
+type Match[K comparable] struct {
+	ID       K
+	Hash     uint64
+	Distance uint8
+}
+
+// Index finds the fingerprints within a Hamming distance of a hash without comparing every fingerprint.
+//
+// Fingerprints are split into maxDistance+1 blocks of bits and stored in one table per block.
+// Two fingerprints within maxDistance bits share at least one block, so only the fingerprints
+// found in the tables are compared.
+//
+// See: http://infolab.stanford.edu/~manku/papers/07www-duplicates.pdf
+type Index[K comparable] struct {
+	mutex       sync.RWMutex
+	maxDistance int
+	masks       []uint64
+	tables      []map[uint64][]K
+	hashes      map[K]uint64
+}
+
+// NewIndex creates an index answering queries up to maxDistance, between 0 and 63.
+// Small distances are faster since blocks are larger.
+func NewIndex[K comparable](maxDistance int) *Index[K] {
+	if maxDistance < 0 || maxDistance > 63 {
+		panic("simhash.NewIndex(): maxDistance must be between 0 and 63")
+	}
+
+	n := maxDistance + 1
+	ix := &Index[K]{
+		maxDistance: maxDistance,
+		masks:       make([]uint64, n),
+		tables:      make([]map[uint64][]K, n),
+		hashes:      map[K]uint64{},
+	}
+	// the first 64%n blocks get an extra bit
+	shift := 0
+	for i := 0; i < n; i++ {
+		size := 64 / n
+		if i < 64%n {
+			size++
+		}
+		ix.masks[i] = (1<<size - 1) << shift
+		ix.tables[i] = map[uint64][]K{}
+		shift += size
+	}
+	return ix
+}
+
+// MaxDistance returns the largest distance the index answers
+func (ix *Index[K]) MaxDistance() int {
+	return ix.maxDistance
+}
+
+// Len returns the number of fingerprints in the index
+func (ix *Index[K]) Len() int {
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+	return len(ix.hashes)
+}
+
+// Get returns the fingerprint stored for id
+func (ix *Index[K]) Get(id K) (uint64, bool) {
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+	hash, ok := ix.hashes[id]
+	return hash, ok
+}
+
+// Add stores the fingerprint of id, replacing the previous one
+func (ix *Index[K]) Add(id K, hash uint64) {
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	if old, ok := ix.hashes[id]; ok {
+		if old == hash {
+			return
+		}
+		ix.remove(id, old)
+	}
+	ix.hashes[id] = hash
+	for i, table := range ix.tables {
+		key := hash & ix.masks[i]
+		table[key] = append(table[key], id)
+	}
+}
+
+// Remove deletes the fingerprint of id
+func (ix *Index[K]) Remove(id K) {
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	if hash, ok := ix.hashes[id]; ok {
+		ix.remove(id, hash)
+	}
+}
+
+func (ix *Index[K]) remove(id K, hash uint64) {
+	delete(ix.hashes, id)
+	for i, table := range ix.tables {
+		key := hash & ix.masks[i]
+		ids := table[key]
+		if j := slices.Index(ids, id); j >= 0 {
+			ids = slices.Delete(ids, j, j+1)
+		}
+		if len(ids) == 0 {
+			delete(table, key)
+		} else {
+			table[key] = ids
+		}
+	}
+}
+
+// Near returns the fingerprints within maxDistance of hash, closest first.
+// maxDistance is reduced to the one of the index.
+func (ix *Index[K]) Near(hash uint64, maxDistance int) []Match[K] {
+	if maxDistance > ix.maxDistance {
+		maxDistance = ix.maxDistance
+	}
+	if maxDistance < 0 {
+		return nil
+	}
+
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+
+	var matches []Match[K]
+	seen := map[K]struct{}{}
+	for i, table := range ix.tables {
+		for _, id := range table[hash&ix.masks[i]] {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			found := ix.hashes[id]
+			if d := Compare(hash, found); int(d) <= maxDistance {
+				matches = append(matches, Match[K]{ID: id, Hash: found, Distance: d})
+			}
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b Match[K]) int {
+		return int(a.Distance) - int(b.Distance)
+	})
+	return matches
+}
+
+// Range calls f for every fingerprint until f returns false
+func (ix *Index[K]) Range(f func(id K, hash uint64) bool) {
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+	for id, hash := range ix.hashes {
+		if !f(id, hash) {
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package simhash
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	ix := NewIndex[int](3)
+	base := uint64(0xF0F0F0F0F0F0F0F0)
+	ix.Add(1, base)
+	ix.Add(2, base^0b111)     // distance 3
+	ix.Add(3, base^0b1111)    // distance 4
+	ix.Add(4, base^(1<<63|1)) // distance 2, in two blocks
+	ix.Add(5, ^base)
+
+	matches := ix.Near(base, 3)
+	if len(matches) != 3 || matches[0].ID != 1 || matches[1].ID != 4 || matches[2].ID != 2 {
+		t.Fatalf("unexpected matches %v", matches)
+	}
+	if matches := ix.Near(base, 10); len(matches) != 3 {
+		t.Errorf("expected the distance to be capped, got %v", matches)
+	}
+	if matches := ix.Near(base, 0); len(matches) != 1 || matches[0].Distance != 0 {
+		t.Errorf("unexpected exact matches %v", matches)
+	}
+
+	ix.Remove(1)
+	ix.Add(4, ^base)
+	if matches := ix.Near(base, 3); len(matches) != 1 || matches[0].ID != 2 || ix.Len() != 4 {
+		t.Errorf("unexpected matches after update %v", matches)
+	}
+}
+
+func TestIndexMatchesCompare(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ix := NewIndex[int](5)
+	hashes := make([]uint64, 2000)
+	for i := range hashes {
+		hashes[i] = r.Uint64()
+		if i%2 == 1 {
+			// a near duplicate of the previous hash
+			hashes[i] = hashes[i-1]
+			for j := r.Intn(7); j > 0; j-- {
+				hashes[i] ^= 1 << r.Intn(64)
+			}
+		}
+		ix.Add(i, hashes[i])
+	}
+
+	for i, hash := range hashes[:200] {
+		expected := 0
+		for _, other := range hashes {
+			if Compare(hash, other) <= 5 {
+				expected++
+			}
+		}
+		if matches := ix.Near(hash, 5); len(matches) != expected {
+			t.Errorf("%d: expected %d matches, got %d", i, expected, len(matches))
+		}
+	}
+}
+
+func BenchmarkIndexNear(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	ix := NewIndex[int](3)
+	for i := 0; i < 100000; i++ {
+		ix.Add(i, r.Uint64())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ix.Near(r.Uint64(), 3)
+	}
+}
@@ -0,0 +1,141 @@
+package simhash
+
+import (
+	"math"
+
+	"github.com/dreamsxin/go-utils/hash/siphash"
+)
+
+// MinHash computes a signature whose matching positions estimate the Jaccard similarity
+// of two feature sets, which fits set similarity better than simhash.
+//
+// Each feature is hashed once with siphash, then permuted by size universal hash functions
+// a*x+b whose coefficients derive from the seed. Only signatures of the same size and seed are comparable.
+//
+// See: https://en.wikipedia.org/wiki/MinHash
+type MinHash struct {
+	a, b []uint64
+	mins []uint64
+}
+
+// NewMinHash creates a MinHash of size hash functions with the seed 0
+func NewMinHash(size int) *MinHash {
+	return NewMinHashWithSeed(size, 0)
+}
+
+func NewMinHashWithSeed(size int, seed uint64) *MinHash {
+	if size < 1 {
+		panic("simhash.NewMinHash(): size must be a positive integer")
+	}
+	m := &MinHash{
+		a:    make([]uint64, size),
+		b:    make([]uint64, size),
+		mins: make([]uint64, size),
+	}
+	for i := range m.a {
+		seed, m.a[i] = splitmix64(seed)
+		seed, m.b[i] = splitmix64(seed)
+		// odd multipliers are permutations of uint64
+		m.a[i] |= 1
+	}
+	m.Reset()
+	return m
+}
+
+// splitmix64 returns the next state and a well mixed value
+func splitmix64(state uint64) (uint64, uint64) {
+	state += 0x9e3779b97f4a7c15
+	z := state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return state, z ^ (z >> 31)
+}
+
+// Reset empties the feature set
+func (m *MinHash) Reset() {
+	for i := range m.mins {
+		m.mins[i] = math.MaxUint64
+	}
+}
+
+// Push adds a feature to the set
+func (m *MinHash) Push(feature []byte) {
+	m.PushHash(siphash.Hash(0, 0, feature))
+}
+
+// PushHash adds an already hashed feature to the set
+func (m *MinHash) PushHash(h uint64) {
+	for i := range m.mins {
+		if v := m.a[i]*h + m.b[i]; v < m.mins[i] {
+			m.mins[i] = v
+		}
+	}
+}
+
+// Scan adds every feature returned by the scanner
+func (m *MinHash) Scan(scanner FeatureScanner) error {
+	for scanner.Scan() {
+		m.Push(scanner.Bytes())
+	}
+	return scanner.Err()
+}
+
+// Signature returns a copy of the current signature
+func (m *MinHash) Signature() []uint64 {
+	return append([]uint64(nil), m.mins...)
+}
+
+// MinHashSignature returns the signature of size hash functions for the document returned by the scanner
+func MinHashSignature(size int, scanner FeatureScanner) ([]uint64, error) {
+	m := NewMinHash(size)
+	if err := m.Scan(scanner); err != nil {
+		return nil, err
+	}
+	return m.mins, nil
+}
+
+// MinHashSimilarity estimates the Jaccard similarity of the sets of two signatures
+func MinHashSimilarity(a, b []uint64) float64 {
+	if len(a) != len(b) {
+		panic("simhash.MinHashSimilarity(): signatures must have the same size")
+	}
+	if len(a) == 0 {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// BBit keeps the lowest bits of every value of a signature, b-bit minwise hashing
+// trades some accuracy for signatures up to 64 times smaller. Use BBitSimilarity to compare them.
+func BBit(signature []uint64, bits uint) []uint64 {
+	if bits == 0 || bits > 64 {
+		panic("simhash.BBit(): bits must be between 1 and 64")
+	}
+	mask := uint64(1)<<bits - 1
+	if bits == 64 {
+		mask = math.MaxUint64
+	}
+	reduced := make([]uint64, len(signature))
+	for i, v := range signature {
+		reduced[i] = v & mask
+	}
+	return reduced
+}
+
+// BBitSimilarity estimates the Jaccard similarity of two b-bit signatures,
+// correcting the matches expected by chance between b-bit values
+func BBitSimilarity(a, b []uint64, bits uint) float64 {
+	p := MinHashSimilarity(a, b)
+	if bits >= 64 {
+		return p
+	}
+	c := math.Ldexp(1, -int(bits))
+	j := (p - c) / (1 - c)
+	return max(j, 0)
+}
@@ -0,0 +1,67 @@
+package simhash
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func wordSet(prefix string, from, to int) [][]byte {
+	var words [][]byte
+	for i := from; i < to; i++ {
+		words = append(words, []byte(fmt.Sprintf("%s%d", prefix, i)))
+	}
+	return words
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	// |A∩B| = 50, |A∪B| = 150
+	a, err := MinHashSignature(256, NewSliceScanner(wordSet("w", 0, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := MinHashSignature(256, NewSliceScanner(wordSet("w", 50, 150)))
+	c, _ := MinHashSignature(256, NewSliceScanner(wordSet("x", 0, 100)))
+
+	if s := MinHashSimilarity(a, a); s != 1 {
+		t.Errorf("expected identical sets to have similarity 1, got %f", s)
+	}
+	if s := MinHashSimilarity(a, b); math.Abs(s-1.0/3) > 0.1 {
+		t.Errorf("expected a similarity close to 0.33, got %f", s)
+	}
+	if s := MinHashSimilarity(a, c); s > 0.05 {
+		t.Errorf("expected disjoint sets to have a similarity close to 0, got %f", s)
+	}
+
+	if s := BBitSimilarity(BBit(a, 8), BBit(b, 8), 8); math.Abs(s-1.0/3) > 0.1 {
+		t.Errorf("expected a b-bit similarity close to 0.33, got %f", s)
+	}
+	if s := BBitSimilarity(BBit(a, 8), BBit(c, 8), 8); s > 0.05 {
+		t.Errorf("expected a b-bit similarity close to 0, got %f", s)
+	}
+}
+
+func TestMinHashIncremental(t *testing.T) {
+	m := NewMinHashWithSeed(64, 42)
+	for _, w := range wordSet("w", 0, 10) {
+		m.Push(w)
+	}
+	first := m.Signature()
+	// duplicates do not change the set
+	m.Push([]byte("w3"))
+	if MinHashSimilarity(first, m.Signature()) != 1 {
+		t.Error("expected duplicates to be ignored")
+	}
+	m.Reset()
+	if m.Signature()[0] != math.MaxUint64 {
+		t.Error("expected an empty signature after Reset")
+	}
+
+	other := NewMinHash(64)
+	for _, w := range wordSet("w", 0, 10) {
+		other.Push(w)
+	}
+	if MinHashSimilarity(first, other.Signature()) == 1 {
+		t.Error("expected signatures of different seeds to differ")
+	}
+}
@@ -0,0 +1,87 @@
+package simhash
+
+import (
+	"sync"
+
+	"github.com/dreamsxin/go-utils/pool/worker"
+)
+
+// DefaultChunkSize is the number of features hashed by each task of the parallel functions
+const DefaultChunkSize = 4096
+
+// ParallelSipHash returns the same value as SipHash but hashes the features on the pool,
+// chunkSize features per task, each task summing its own sign vector.
+// Features are copied since scanners may reuse their buffer. It returns the error of the scanner.
+func ParallelSipHash(pool *worker.WorkerPool, scanner FeatureScanner, chunkSize int) (uint64, error) {
+	if chunkSize < 1 {
+		chunkSize = DefaultChunkSize
+	}
+
+	var (
+		mutex sync.Mutex
+		signs [64]int64
+	)
+	group := pool.Group()
+	submit := func(chunk [][]byte) {
+		group.Submit(func() {
+			var local [64]int64
+			for _, b := range chunk {
+				addSigns(&local, b)
+			}
+			mutex.Lock()
+			for i := range signs {
+				signs[i] += local[i]
+			}
+			mutex.Unlock()
+		})
+	}
+
+	chunk := make([][]byte, 0, chunkSize)
+	for scanner.Scan() {
+		chunk = append(chunk, append([]byte(nil), scanner.Bytes()...))
+		if len(chunk) == chunkSize {
+			submit(chunk)
+			chunk = make([][]byte, 0, chunkSize)
+		}
+	}
+	if len(chunk) > 0 {
+		submit(chunk)
+	}
+	group.Wait()
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return signsFingerprint(&signs), nil
+}
+
+// ParallelVectorize returns the same vector as Vectorize, vectorizing chunkSize features per task on the pool
+func ParallelVectorize(pool *worker.WorkerPool, features []Feature, chunkSize int) Vector {
+	if chunkSize < 1 {
+		chunkSize = DefaultChunkSize
+	}
+
+	var (
+		mutex sync.Mutex
+		v     Vector
+	)
+	group := pool.Group()
+	for start := 0; start < len(features); start += chunkSize {
+		chunk := features[start:min(start+chunkSize, len(features))]
+		group.Submit(func() {
+			local := Vectorize(chunk)
+			mutex.Lock()
+			for i := range v {
+				v[i] += local[i]
+			}
+			mutex.Unlock()
+		})
+	}
+	group.Wait()
+	return v
+}
+
+// ParallelSimhash returns the same value as Simhash, see ParallelVectorize
+func ParallelSimhash(pool *worker.WorkerPool, fs FeatureSet, chunkSize int) uint64 {
+	return Fingerprint(ParallelVectorize(pool, fs.GetFeatures(), chunkSize))
+}
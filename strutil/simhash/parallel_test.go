@@ -0,0 +1,32 @@
+package simhash
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/dreamsxin/go-utils/pool/worker"
+)
+
+func TestParallel(t *testing.T) {
+	pool := worker.New(4, 100)
+	defer pool.StopAndWait()
+
+	doc := strings.Repeat("Now is the winter of our discontent made glorious summer by this sun of York ", 500)
+
+	scanner := bufio.NewScanner(strings.NewReader(doc))
+	scanner.Split(ScanByteTrigrams)
+	expected := SipHash(scanner)
+
+	scanner = bufio.NewScanner(strings.NewReader(doc))
+	scanner.Split(ScanByteTrigrams)
+	h, err := ParallelSipHash(pool, scanner, 100)
+	if err != nil || h != expected {
+		t.Errorf("expected %016x, got %016x %v", expected, h, err)
+	}
+
+	fs := NewWordFeatureSet([]byte(doc))
+	if h := ParallelSimhash(pool, fs, 64); h != Simhash(fs) {
+		t.Errorf("expected %016x, got %016x", Simhash(fs), h)
+	}
+}
@@ -0,0 +1,106 @@
+package simhash
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const indexFormatVersion = 1
+
+var errIndexFormat = errors.New("simhash: unsupported index format")
+
+// indexFile is the gob encoded content of a saved Index, the tables are rebuilt on load
+type indexFile[K comparable] struct {
+	Version     int
+	MaxDistance int
+	IDs         []K
+	Hashes      []uint64
+}
+
+// Save writes the fingerprints of the index with encoding/gob, ids must be encodable by gob
+func (ix *Index[K]) Save(w io.Writer) error {
+	ix.mutex.RLock()
+	f := indexFile[K]{
+		Version:     indexFormatVersion,
+		MaxDistance: ix.maxDistance,
+		IDs:         make([]K, 0, len(ix.hashes)),
+		Hashes:      make([]uint64, 0, len(ix.hashes)),
+	}
+	for id, hash := range ix.hashes {
+		f.IDs = append(f.IDs, id)
+		f.Hashes = append(f.Hashes, hash)
+	}
+	ix.mutex.RUnlock()
+
+	return gob.NewEncoder(w).Encode(&f)
+}
+
+// Load adds the fingerprints written by Save, the saved maximum distance is ignored
+func (ix *Index[K]) Load(r io.Reader) error {
+	f, err := readIndexFile[K](r)
+	if err != nil {
+		return err
+	}
+	for i, id := range f.IDs {
+		ix.Add(id, f.Hashes[i])
+	}
+	return nil
+}
+
+func readIndexFile[K comparable](r io.Reader) (*indexFile[K], error) {
+	var f indexFile[K]
+	if err := gob.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+	if f.Version != indexFormatVersion || len(f.IDs) != len(f.Hashes) || f.MaxDistance < 0 || f.MaxDistance > 63 {
+		return nil, fmt.Errorf("%w: version %d", errIndexFormat, f.Version)
+	}
+	return &f, nil
+}
+
+// SaveFile saves the index to a temporary file renamed over path, a crash never leaves a truncated index
+func (ix *Index[K]) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	if err := ix.Save(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadIndexFile reads an index saved by SaveFile with its maximum distance
+func LoadIndexFile[K comparable](path string) (*Index[K], error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	f, err := readIndexFile[K](bufio.NewReader(file))
+	if err != nil {
+		return nil, err
+	}
+	ix := NewIndex[K](f.MaxDistance)
+	for i, id := range f.IDs {
+		ix.Add(id, f.Hashes[i])
+	}
+	return ix, nil
+}
@@ -0,0 +1,43 @@
+package simhash
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexPersistence(t *testing.T) {
+	ix := NewIndex[string](4)
+	ix.Add("a", 0xFF00FF00FF00FF00)
+	ix.Add("b", 0xFF00FF00FF00FF01)
+	ix.Add("c", 0x0123456789ABCDEF)
+
+	path := filepath.Join(t.TempDir(), "index.gob")
+	if err := ix.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadIndexFile[string](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.MaxDistance() != 4 || loaded.Len() != 3 {
+		t.Errorf("unexpected index of %d fingerprints up to %d", loaded.Len(), loaded.MaxDistance())
+	}
+	if matches := loaded.Near(0xFF00FF00FF00FF00, 2); len(matches) != 2 || matches[0].ID != "a" {
+		t.Errorf("unexpected matches %v", matches)
+	}
+
+	var buf bytes.Buffer
+	if err := ix.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	other := NewIndex[string](1)
+	other.Add("d", 1)
+	if err := other.Load(&buf); err != nil || other.Len() != 4 {
+		t.Errorf("expected 4 fingerprints, got %d %v", other.Len(), err)
+	}
+
+	if _, err := LoadIndexFile[int](path); err == nil {
+		t.Error("expected an error loading ids of another type")
+	}
+}
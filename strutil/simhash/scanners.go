@@ -1,7 +1,9 @@
 package simhash
 
-// TODO(dgryski): channel scanner
-// TODO(dgryski): ngram scanner for bytes and words
+import (
+	"bufio"
+	"unicode/utf8"
+)
 
 // Return features one-at-a-time to be considered by SimHash.
 // This matches (partially) the scanner interface for bufio.Scanner, so those scanner can be reused here.
@@ -38,6 +40,31 @@ func (s *SliceScanner) Bytes() []byte {
 	return s.tokens[s.i-1]
 }
 
+type ChanScanner struct {
+	ch    <-chan []byte
+	token []byte
+}
+
+// NewChanScanner creates a scanner that returns the byte slices received from ch until it is closed,
+// so features produced by other goroutines are hashed as they arrive
+func NewChanScanner(ch <-chan []byte) FeatureScanner {
+	return &ChanScanner{ch: ch}
+}
+
+func (s *ChanScanner) Err() error {
+	return nil
+}
+
+func (s *ChanScanner) Scan() bool {
+	token, ok := <-s.ch
+	s.token = token
+	return ok
+}
+
+func (s *ChanScanner) Bytes() []byte {
+	return s.token
+}
+
 func ScanByteTrigrams(data []byte, atEOF bool) (advance int, token []byte, err error) {
 
 	if atEOF || len(data) < 3 {
@@ -46,3 +73,108 @@ func ScanByteTrigrams(data []byte, atEOF bool) (advance int, token []byte, err e
 
 	return 1, data[:3], nil
 }
+
+// ScanByteNGrams returns a bufio.SplitFunc returning every sequence of n bytes, like ScanByteTrigrams
+func ScanByteNGrams(n int) bufio.SplitFunc {
+	if n < 1 {
+		panic("simhash.ScanByteNGrams(): n must be a positive integer")
+	}
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if len(data) < n {
+			return 0, nil, nil
+		}
+		return 1, data[:n], nil
+	}
+}
+
+type ByteNGramScanner struct {
+	b []byte
+	n int
+	i int
+}
+
+// NewByteNGramScanner creates a scanner that returns every sequence of n bytes of b
+func NewByteNGramScanner(b []byte, n int) FeatureScanner {
+	if n < 1 {
+		panic("simhash.NewByteNGramScanner(): n must be a positive integer")
+	}
+	return &ByteNGramScanner{b: b, n: n}
+}
+
+func (s *ByteNGramScanner) Err() error {
+	return nil
+}
+
+func (s *ByteNGramScanner) Scan() bool {
+	if s.i+s.n > len(s.b) {
+		return false
+	}
+	s.i++
+	return true
+}
+
+func (s *ByteNGramScanner) Bytes() []byte {
+	return s.b[s.i-1 : s.i-1+s.n]
+}
+
+type WordNGramScanner struct {
+	words [][]byte
+	n     int
+	i     int
+	buf   []byte
+}
+
+// NewWordNGramScanner creates a scanner that returns the n-grams of words joined by a space,
+// as Shingle does. Fewer than n words make a single n-gram.
+func NewWordNGramScanner(words [][]byte, n int) FeatureScanner {
+	if n < 1 {
+		panic("simhash.NewWordNGramScanner(): n must be a positive integer")
+	}
+	if n > len(words) {
+		n = len(words)
+	}
+	return &WordNGramScanner{words: words, n: n}
+}
+
+func (s *WordNGramScanner) Err() error {
+	return nil
+}
+
+func (s *WordNGramScanner) Scan() bool {
+	if s.n == 0 || s.i+s.n > len(s.words) {
+		return false
+	}
+	s.buf = s.buf[:0]
+	for j, w := range s.words[s.i : s.i+s.n] {
+		if j > 0 {
+			s.buf = append(s.buf, ' ')
+		}
+		s.buf = append(s.buf, w...)
+	}
+	s.i++
+	return true
+}
+
+// Bytes returns the current n-gram, it is overwritten by the next call to Scan
+func (s *WordNGramScanner) Bytes() []byte {
+	return s.buf
+}
+
+// ScanRuneTrigrams is a bufio.SplitFunc returning every sequence of 3 UTF-8 characters.
+// Unlike ScanByteTrigrams it never splits a multi-byte character, invalid bytes count as one character.
+func ScanRuneTrigrams(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	end := 0
+	first := 0
+	for i := 0; i < 3; i++ {
+		if end == len(data) || (!atEOF && !utf8.FullRune(data[end:])) {
+			// wait for the rest of the character, or stop at EOF
+			return 0, nil, nil
+		}
+		_, size := utf8.DecodeRune(data[end:])
+		if i == 0 {
+			first = size
+		}
+		end += size
+	}
+	return first, data[:end], nil
+}
@@ -0,0 +1,107 @@
+package simhash
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+)
+
+func scanAll(s FeatureScanner) []string {
+	var tokens []string
+	for s.Scan() {
+		tokens = append(tokens, string(s.Bytes()))
+	}
+	return tokens
+}
+
+func TestNGramScanners(t *testing.T) {
+	words := bytes.Fields([]byte("this is a test"))
+	if tokens := scanAll(NewWordNGramScanner(words, 2)); strings.Join(tokens, "|") != "this is|is a|a test" {
+		t.Errorf("unexpected word bigrams %q", tokens)
+	}
+	if tokens := scanAll(NewWordNGramScanner(words, 9)); len(tokens) != 1 || tokens[0] != "this is a test" {
+		t.Errorf("unexpected word n-grams %q", tokens)
+	}
+	if tokens := scanAll(NewWordNGramScanner(nil, 2)); len(tokens) != 0 {
+		t.Errorf("expected no n-gram, got %q", tokens)
+	}
+
+	if tokens := scanAll(NewByteNGramScanner([]byte("abcde"), 4)); strings.Join(tokens, "|") != "abcd|bcde" {
+		t.Errorf("unexpected byte n-grams %q", tokens)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader("abcde"))
+	scanner.Split(ScanByteNGrams(2))
+	if tokens := scanAll(scanner); strings.Join(tokens, "|") != "ab|bc|cd|de" {
+		t.Errorf("unexpected split byte n-grams %q", tokens)
+	}
+}
+
+func TestChanScanner(t *testing.T) {
+	words := bytes.Fields([]byte("now is the winter of our discontent"))
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		for _, w := range words {
+			ch <- w
+		}
+	}()
+	if SipHash(NewChanScanner(ch)) != SipHash(NewSliceScanner(words)) {
+		t.Error("expected the same hash as the slice scanner")
+	}
+}
+
+func TestScanRuneTrigrams(t *testing.T) {
+	doc := "simhash 去重算法 é\xffx"
+
+	scanner := bufio.NewScanner(strings.NewReader(doc))
+	scanner.Split(ScanRuneTrigrams)
+	tokens := scanAll(scanner)
+	if len(tokens) != utf8.RuneCountInString(doc)-2 {
+		t.Fatalf("expected a trigram per character, got %q", tokens)
+	}
+	for _, token := range tokens[:len(tokens)-3] {
+		if !utf8.ValidString(token) || utf8.RuneCountInString(token) != 3 {
+			t.Errorf("unexpected trigram %q", token)
+		}
+	}
+	if tokens[4] != "ash" || tokens[7] != " 去重" || tokens[len(tokens)-1] != "é\xffx" {
+		t.Errorf("unexpected trigrams %q", tokens)
+	}
+
+	// characters split across reads give the same features
+	scanner = bufio.NewScanner(iotest.OneByteReader(strings.NewReader(doc)))
+	scanner.Split(ScanRuneTrigrams)
+	if split := scanAll(scanner); strings.Join(split, "|") != strings.Join(tokens, "|") {
+		t.Errorf("expected the same trigrams, got %q", split)
+	}
+}
+
+func TestRuneTrigramsStability(t *testing.T) {
+	hash := func(r io.Reader, split bufio.SplitFunc) uint64 {
+		scanner := bufio.NewScanner(r)
+		scanner.Split(split)
+		return SipHash(scanner)
+	}
+	doc := "今天天气很好，我们去公园散步吧"
+	if hash(strings.NewReader(doc), ScanRuneTrigrams) != hash(iotest.OneByteReader(strings.NewReader(doc)), ScanRuneTrigrams) {
+		t.Error("expected the hash not to depend on how the document is read")
+	}
+
+	// byte trigrams mix the bytes of different characters
+	scanner := bufio.NewScanner(strings.NewReader(doc))
+	scanner.Split(ScanByteTrigrams)
+	invalid := 0
+	for _, token := range scanAll(scanner) {
+		if !utf8.ValidString(token) {
+			invalid++
+		}
+	}
+	if invalid == 0 {
+		t.Error("expected byte trigrams to split characters")
+	}
+}
@@ -18,18 +18,26 @@ func SipHash(scanner FeatureScanner) uint64 {
 	var signs [64]int64
 
 	for scanner.Scan() {
-		b := scanner.Bytes()
-		h := siphash.Hash(0, 0, b)
-
-		for i := 0; i < 64; i++ {
-			negate := int(h) & 1
-			// if negate is 1, we will negate '-1', below
-			r := (-1 ^ -negate) + negate
-			signs[i] += int64(r)
-			h >>= 1
-		}
+		addSigns(&signs, scanner.Bytes())
 	}
 
+	return signsFingerprint(&signs)
+}
+
+// addSigns adds +1 to signs for each set bit of the siphash of b and -1 otherwise
+func addSigns(signs *[64]int64, b []byte) {
+	h := siphash.Hash(0, 0, b)
+
+	for i := 0; i < 64; i++ {
+		negate := int(h) & 1
+		// if negate is 1, we will negate '-1', below
+		r := (-1 ^ -negate) + negate
+		signs[i] += int64(r)
+		h >>= 1
+	}
+}
+
+func signsFingerprint(signs *[64]int64) uint64 {
 	var shash uint64
 
 	// TODO: can probably be done with SSE?
@@ -0,0 +1,26 @@
+package simhash
+
+// EnglishStopwords are frequent English function words, see SetStopwords
+var EnglishStopwords = []string{
+	"a", "about", "above", "after", "again", "against", "all", "am", "an", "and", "any", "are", "as", "at",
+	"be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+	"can", "could", "did", "do", "does", "doing", "down", "during", "each", "few", "for", "from", "further",
+	"had", "has", "have", "having", "he", "her", "here", "hers", "herself", "him", "himself", "his", "how",
+	"i", "if", "in", "into", "is", "it", "it's", "its", "itself", "just", "me", "more", "most", "my", "myself",
+	"no", "nor", "not", "now", "of", "off", "on", "once", "only", "or", "other", "our", "ours", "ourselves",
+	"out", "over", "own", "same", "she", "should", "so", "some", "such",
+	"than", "that", "the", "their", "theirs", "them", "themselves", "then", "there", "these", "they",
+	"this", "those", "through", "to", "too", "under", "until", "up", "very",
+	"was", "we", "were", "what", "when", "where", "which", "while", "who", "whom", "why", "will", "with",
+	"would", "you", "your", "yours", "yourself", "yourselves",
+}
+
+// ChineseStopwords are frequent Chinese function words, see SetStopwords.
+// With Tokenize, which splits Chinese into bigrams, single characters only match isolated characters.
+var ChineseStopwords = []string{
+	"的", "了", "和", "是", "在", "也", "就", "都", "而", "及", "与", "着", "或", "把", "被", "让", "给",
+	"对", "从", "向", "以", "之", "其", "这", "那", "我", "你", "他", "她", "它", "吗", "呢", "吧", "啊",
+	"我们", "你们", "他们", "她们", "它们", "这个", "那个", "这些", "那些", "这样", "那样", "一个", "一些",
+	"什么", "怎么", "为什么", "因为", "所以", "但是", "而且", "并且", "或者", "如果", "虽然", "然后", "还是",
+	"已经", "可以", "没有", "不是", "就是", "还有", "以及", "关于", "对于", "由于", "通过", "进行", "自己",
+}
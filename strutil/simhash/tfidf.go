@@ -0,0 +1,134 @@
+package simhash
+
+import (
+	"bytes"
+	"math"
+	"sync"
+)
+
+// DocumentFrequency counts the documents each term appears in, it is safe for concurrent use
+type DocumentFrequency struct {
+	mutex     sync.RWMutex
+	documents int
+	terms     map[string]int
+}
+
+func NewDocumentFrequency() *DocumentFrequency {
+	return &DocumentFrequency{terms: map[string]int{}}
+}
+
+// BuildDocumentFrequency counts the terms of a corpus split by tokenize, Tokenize when nil.
+// Documents are lower-cased like the feature sets do.
+func BuildDocumentFrequency(docs [][]byte, tokenize func([]byte) [][]byte) *DocumentFrequency {
+	if tokenize == nil {
+		tokenize = Tokenize
+	}
+	df := NewDocumentFrequency()
+	for _, doc := range docs {
+		df.Add(tokenize(bytes.ToLower(doc)))
+	}
+	return df
+}
+
+// Add counts the distinct terms of a document
+func (df *DocumentFrequency) Add(terms [][]byte) {
+	df.mutex.Lock()
+	defer df.mutex.Unlock()
+	df.documents++
+	seen := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		if _, ok := seen[string(term)]; ok {
+			continue
+		}
+		seen[string(term)] = struct{}{}
+		df.terms[string(term)]++
+	}
+}
+
+// Documents returns the number of documents added
+func (df *DocumentFrequency) Documents() int {
+	df.mutex.RLock()
+	defer df.mutex.RUnlock()
+	return df.documents
+}
+
+// Frequency returns the number of documents containing term
+func (df *DocumentFrequency) Frequency(term []byte) int {
+	df.mutex.RLock()
+	defer df.mutex.RUnlock()
+	return df.terms[string(term)]
+}
+
+// IDF returns the smoothed inverse document frequency ln((1+N)/(1+df))+1,
+// unknown terms get the highest value
+func (df *DocumentFrequency) IDF(term []byte) float64 {
+	df.mutex.RLock()
+	defer df.mutex.RUnlock()
+	return math.Log(float64(1+df.documents)/float64(1+df.terms[string(term)])) + 1
+}
+
+type TFIDFFeatureOption func(*TFIDFFeatureSet)
+
+func SetTFIDFCreateFeature(nf FuncCreateFeature) TFIDFFeatureOption {
+	return func(w *TFIDFFeatureSet) { w.nf = nf }
+}
+
+// SetTFIDFTokenizer changes how terms are split, it should match the one of the DocumentFrequency
+func SetTFIDFTokenizer(tokenize func([]byte) [][]byte) TFIDFFeatureOption {
+	return func(w *TFIDFFeatureSet) { w.tokenize = tokenize }
+}
+
+// SetTFIDFScale changes the factor applied to tf*idf before rounding it to an integer weight, 10 by default
+func SetTFIDFScale(scale float64) TFIDFFeatureOption {
+	return func(w *TFIDFFeatureSet) { w.scale = scale }
+}
+
+// TFIDFFeatureSet is a feature set in which each distinct term is a feature
+// weighted by its term frequency times its inverse document frequency,
+// so terms common to the whole corpus weigh less than the ones specific to the document.
+type TFIDFFeatureSet struct {
+	b        []byte
+	df       *DocumentFrequency
+	nf       FuncCreateFeature
+	tokenize func([]byte) [][]byte
+	scale    float64
+}
+
+func NewTFIDFFeatureSet(b []byte, df *DocumentFrequency, opts ...TFIDFFeatureOption) *TFIDFFeatureSet {
+	fs := &TFIDFFeatureSet{b: b, df: df, nf: NewFeature, tokenize: Tokenize, scale: 10}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(fs)
+		}
+	}
+	fs.normalize()
+	return fs
+}
+
+func (w *TFIDFFeatureSet) normalize() {
+	w.b = bytes.ToLower(w.b)
+}
+
+// Returns a []Feature with a weight of at least 1 for each distinct term
+func (w *TFIDFFeatureSet) GetFeatures() []Feature {
+	terms := w.tokenize(w.b)
+	tf := make(map[string]int, len(terms))
+	var distinct [][]byte
+	for _, term := range terms {
+		if tf[string(term)] == 0 {
+			distinct = append(distinct, term)
+		}
+		tf[string(term)]++
+	}
+
+	features := newFeatures(distinct, w.nf)
+	for i, term := range distinct {
+		idf := 1.0
+		if w.df != nil {
+			idf = w.df.IDF(term)
+		}
+		weight := int(math.Round(float64(tf[string(term)]) * idf * w.scale))
+		features[i].SetWeight(max(weight, 1))
+	}
+	return features
+}
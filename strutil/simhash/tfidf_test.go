@@ -0,0 +1,34 @@
+package simhash
+
+import "testing"
+
+func TestTFIDFFeatureSet(t *testing.T) {
+	corpus := [][]byte{
+		[]byte("the cat sat on the mat"),
+		[]byte("the dog sat on the log"),
+		[]byte("the bird flew over the house"),
+	}
+	df := BuildDocumentFrequency(corpus, nil)
+	if df.Documents() != 3 || df.Frequency([]byte("the")) != 3 || df.Frequency([]byte("sat")) != 2 {
+		t.Fatalf("unexpected document frequencies %d %d", df.Documents(), df.Frequency([]byte("the")))
+	}
+	if df.IDF([]byte("the")) >= df.IDF([]byte("cat")) || df.IDF([]byte("cat")) >= df.IDF([]byte("unknown")) {
+		t.Error("expected rarer terms to have a higher idf")
+	}
+
+	features := NewTFIDFFeatureSet([]byte("The cat and the hat"), df).GetFeatures()
+	if len(features) != 4 {
+		t.Fatalf("expected 4 distinct terms, got %d", len(features))
+	}
+	// "the" appears twice but in every document, "cat" once in one document
+	if the, cat := features[0].Weight(), features[1].Weight(); the != 20 || cat != 17 {
+		t.Errorf("unexpected weights the=%d cat=%d", the, cat)
+	}
+
+	a := Simhash(NewTFIDFFeatureSet([]byte("the cat sat on the mat"), df))
+	b := Simhash(NewTFIDFFeatureSet([]byte("the cat sat on the hat"), df))
+	c := Simhash(NewTFIDFFeatureSet([]byte("the dog flew over the log"), df))
+	if Compare(a, b) >= Compare(a, c) {
+		t.Errorf("expected similar documents to be closer, got %d and %d", Compare(a, b), Compare(a, c))
+	}
+}
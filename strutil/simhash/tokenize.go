@@ -0,0 +1,70 @@
+package simhash
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// isCJK reports whether r belongs to a script written without spaces between words
+func isCJK(r rune) bool {
+	// ー is the katakana prolonged sound mark, its script is Common
+	return r == 'ー' || unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r)
+}
+
+// Tokenize splits b on Unicode word boundaries. Han, Hiragana and Katakana runs,
+// which have no spaces between words, become overlapping character bigrams,
+// e.g. "去重算法" becomes "去重", "重算" and "算法". Tokens are sub-slices of b.
+func Tokenize(b []byte) [][]byte {
+	var (
+		tokens [][]byte
+		start  = -1
+		cjk    []int
+	)
+	flushWord := func(end int) {
+		if start >= 0 {
+			if token := bytes.TrimRight(b[start:end], "'"); len(token) > 0 {
+				tokens = append(tokens, token)
+			}
+			start = -1
+		}
+	}
+	flushCJK := func(end int) {
+		if len(cjk) == 1 {
+			tokens = append(tokens, b[cjk[0]:end])
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			stop := end
+			if i+2 < len(cjk) {
+				stop = cjk[i+2]
+			}
+			tokens = append(tokens, b[cjk[i]:stop])
+		}
+		cjk = cjk[:0]
+	}
+
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		switch {
+		case isCJK(r):
+			flushWord(i)
+			cjk = append(cjk, i)
+		case isWordRune(r) || (r == '\'' && start >= 0):
+			flushCJK(i)
+			if start < 0 {
+				start = i
+			}
+		default:
+			flushWord(i)
+			flushCJK(i)
+		}
+		i += size
+	}
+	flushWord(len(b))
+	flushCJK(len(b))
+	return tokens
+}
@@ -0,0 +1,55 @@
+package simhash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := map[string]string{
+		"Hello, world! We'll see.": "Hello|world|We'll|see",
+		"去重算法":                     "去重|重算|算法",
+		"使用simhash去重。好":            "使用|simhash|去重|好",
+		"カタカナとひらがな":                "カタ|タカ|カナ|ナと|とひ|ひら|らが|がな",
+		"한국어 문장":                   "한국어|문장",
+		"café 2024 ''":             "café|2024",
+	}
+	for in, expected := range cases {
+		var tokens []string
+		for _, token := range Tokenize([]byte(in)) {
+			tokens = append(tokens, string(token))
+		}
+		if got := strings.Join(tokens, "|"); got != expected {
+			t.Errorf("%q: expected %q, got %q", in, expected, got)
+		}
+	}
+}
+
+func TestTokenizeFeatureSet(t *testing.T) {
+	a := Simhash(NewWordFeatureSet([]byte("今天天气很好，我们去公园散步吧"), SetTokenizer(Tokenize)))
+	b := Simhash(NewWordFeatureSet([]byte("今天天气很好，我们去公园散步"), SetTokenizer(Tokenize)))
+	c := Simhash(NewWordFeatureSet([]byte("股票市场今日大幅下跌"), SetTokenizer(Tokenize)))
+	if Compare(a, b) >= Compare(a, c) {
+		t.Errorf("expected similar documents to be closer, got %d and %d", Compare(a, b), Compare(a, c))
+	}
+
+	// the default regexp only matches ASCII words
+	if n := len(NewWordFeatureSet([]byte("今天天气很好")).GetFeatures()); n != 0 {
+		t.Errorf("expected no feature, got %d", n)
+	}
+	if n := len(NewWordFeatureSet([]byte("今天天气很好"), SetTokenizer(Tokenize)).GetFeatures()); n != 5 {
+		t.Errorf("expected 5 features, got %d", n)
+	}
+}
+
+func TestStopwords(t *testing.T) {
+	features := NewWordFeatureSet([]byte("The cat is on the mat"), SetStopwords(EnglishStopwords)).GetFeatures()
+	if len(features) != 2 {
+		t.Errorf("expected cat and mat, got %d features", len(features))
+	}
+
+	features = NewWordFeatureSet([]byte("我们的 算法"), SetTokenizer(Tokenize), SetStopwords(ChineseStopwords)).GetFeatures()
+	if len(features) != 2 {
+		t.Errorf("expected 们的 and 算法, got %d features", len(features))
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dreamsxin/go-utils/lock"
 	"github.com/dreamsxin/go-utils/lock/easy"
+	"github.com/dreamsxin/go-utils/ratelimit"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -100,6 +101,63 @@ func TestRedislock(t *testing.T) {
 	waitGroup.Wait()
 }
 
+// go test -v -count=1 --run TestRedisLimiter .
+func TestRedisLimiter(t *testing.T) {
+
+	ctx := context.Background()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     "localhost:6379",
+		Password: "123456",
+		DB:       0,
+	})
+	defer rdb.Close()
+	interval := 100 * time.Millisecond
+	burst := 3
+	rl, err := lock.NewRedisLimiter(ctx, rdb, "limiter.test", float64(time.Second/interval), burst)
+	if err != nil {
+		t.Skip("redis unreachable:", err)
+	}
+	if err := rl.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer rl.Reset(ctx)
+
+	for i := 0; i < burst; i++ {
+		if !rl.Allow() {
+			t.Fatalf("expect event %d of the burst allowed", i+1)
+		}
+	}
+	delay, err := rl.Take(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay <= interval/2 || delay > interval {
+		t.Errorf("expect the event after the burst rejected for about %v, get %v", interval, delay)
+	}
+	if rl.Allow() {
+		t.Error("expect the event after the burst rejected")
+	}
+
+	start := time.Now()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < interval/2 {
+		t.Errorf("expect Wait to wait for the next slot, waited %v", waited)
+	}
+	if err := rl.WaitN(ctx, burst+1); err != ratelimit.ErrExceedsBurst {
+		t.Errorf("expect ErrExceedsBurst, get %v", err)
+	}
+
+	if err := rl.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !rl.AllowN(burst) {
+		t.Error("expect a full burst allowed after Reset")
+	}
+}
+
 // go test -v -count=1 --run TestEasyKeyLock .
 func TestEasyKeyLock(t *testing.T) {
 
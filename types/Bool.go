@@ -0,0 +1,88 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Bool accepts true/false, "1"/"0", 1/0, "yes"/"no", "on"/"off" and "y"/"n",
+// and scans tinyint and bit(1) columns
+type Bool bool
+
+// ParseBool parses the lenient boolean spellings, case-insensitively
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "", "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+func (col Bool) MarshalJSON() ([]byte, error) {
+	if col {
+		return []byte("true"), nil
+	}
+	return []byte("false"), nil
+}
+
+func (col *Bool) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		*col = false
+		return nil
+	}
+	v, err := ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*col = Bool(v)
+	return nil
+}
+
+func (col Bool) MarshalText() ([]byte, error) {
+	if col {
+		return []byte("true"), nil
+	}
+	return []byte("false"), nil
+}
+
+func (col *Bool) UnmarshalText(data []byte) error {
+	v, err := ParseBool(string(data))
+	if err != nil {
+		return err
+	}
+	*col = Bool(v)
+	return nil
+}
+
+func (col *Bool) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = false
+	case bool:
+		*col = Bool(value)
+	case int64:
+		*col = value != 0
+	case float64:
+		*col = value != 0
+	case []byte:
+		// bit(1) columns are returned as a single raw byte
+		if len(value) == 1 && value[0] <= 1 {
+			*col = value[0] == 1
+			return nil
+		}
+		return col.UnmarshalText(value)
+	case string:
+		return col.UnmarshalText([]byte(value))
+	default:
+		return fmt.Errorf("can not convert %v to bool", src)
+	}
+	return nil
+}
+
+func (col Bool) Value() (driver.Value, error) {
+	return bool(col), nil
+}
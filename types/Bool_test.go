@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBoolUnmarshalJSON(t *testing.T) {
+	cases := map[string]Bool{
+		`true`: true, `false`: false, `"true"`: true, `"false"`: false,
+		`"1"`: true, `"0"`: false, `1`: true, `0`: false,
+		`"yes"`: true, `"No"`: false, `null`: false,
+	}
+	for in, expected := range cases {
+		var b Bool
+		if err := json.Unmarshal([]byte(in), &b); err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if b != expected {
+			t.Errorf("%s: expected %v, got %v", in, expected, b)
+		}
+	}
+	var b Bool
+	if err := json.Unmarshal([]byte(`"maybe"`), &b); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestBoolScan(t *testing.T) {
+	cases := []struct {
+		src      interface{}
+		expected Bool
+	}{
+		{int64(1), true}, {int64(0), false}, {[]byte{1}, true}, {[]byte{0}, false},
+		{[]byte("1"), true}, {"false", false}, {true, true}, {nil, false},
+	}
+	for _, c := range cases {
+		var b Bool
+		if err := b.Scan(c.src); err != nil || b != c.expected {
+			t.Errorf("%v: expected %v, got %v %v", c.src, c.expected, b, err)
+		}
+	}
+}
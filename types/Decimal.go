@@ -0,0 +1,309 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var errDecimalSyntax = errors.New("invalid decimal")
+
+var bigTen = big.NewInt(10)
+
+// maxDecimalExp bounds the exponent accepted by NewDecimalFromString,
+// larger ones would make parsing or String allocate without limit
+const maxDecimalExp = 1000
+
+// Decimal is an arbitrary-precision fixed-point number (coef * 10^-scale),
+// marshaled as a JSON string so amounts never go through float64.
+// The zero value is 0.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// NewDecimal returns value * 10^-scale, e.g. NewDecimal(1050, 2) is 10.50
+func NewDecimal(value int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(value), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(value), scale: scale}
+}
+
+// NewDecimalFromString parses "123", "-1.50" or "1.2e3", exponents are limited to ±1000
+func NewDecimalFromString(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, errDecimalSyntax
+	}
+
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || e > maxDecimalExp || e < -maxDecimalExp {
+			return Decimal{}, errDecimalSyntax
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	// a sign is only allowed as the first byte, followed by digits only
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart+fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, errDecimalSyntax
+	}
+	coef, ok := new(big.Int).SetString(sign+intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, errDecimalSyntax
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale < 0 {
+		return Decimal{coef: coef.Mul(coef, pow10(int32(-scale)))}, nil
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// RequireDecimalFromString is like NewDecimalFromString but panics on invalid input
+func RequireDecimalFromString(s string) Decimal {
+	d, err := NewDecimalFromString(s)
+	if err != nil {
+		panic(fmt.Sprintf("%v: %q", err, s))
+	}
+	return d
+}
+
+// NewDecimalFromFloat converts f using its shortest decimal representation
+func NewDecimalFromFloat(f float64) Decimal {
+	d, _ := NewDecimalFromString(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+// isDigits reports whether s only holds ASCII digits, the empty string included
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) value() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d expressed with the given (larger) scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.value()
+	}
+	return new(big.Int).Mul(d.value(), pow10(scale-d.scale))
+}
+
+// quoRound divides num by den rounding half away from zero
+func quoRound(num, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Add(d2 Decimal) Decimal {
+	scale := max(d.scale, d2.scale)
+	return Decimal{coef: new(big.Int).Add(d.rescale(scale), d2.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	scale := max(d.scale, d2.scale)
+	return Decimal{coef: new(big.Int).Sub(d.rescale(scale), d2.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.value(), d2.value()), scale: d.scale + d2.scale}
+}
+
+// Div returns d / d2 rounded half away from zero to the given number of decimal places.
+// It panics if d2 is zero.
+func (d Decimal) Div(d2 Decimal, places int32) Decimal {
+	if d2.IsZero() {
+		panic("decimal division by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+	num := new(big.Int).Mul(d.value(), pow10(places+d2.scale))
+	den := new(big.Int).Mul(d2.value(), pow10(d.scale))
+	return Decimal{coef: quoRound(num, den), scale: places}
+}
+
+// Round rounds half away from zero to the given number of decimal places
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return Decimal{coef: d.rescale(places), scale: places}
+	}
+	return Decimal{coef: quoRound(d.value(), pow10(d.scale-places)), scale: places}
+}
+
+// Truncate drops the digits after the given number of decimal places
+func (d Decimal) Truncate(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	return Decimal{coef: new(big.Int).Quo(d.value(), pow10(d.scale-places)), scale: places}
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.value()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.value()), scale: d.scale}
+}
+
+// Cmp returns -1, 0 or +1 depending on whether d is less than, equal to or greater than d2
+func (d Decimal) Cmp(d2 Decimal) int {
+	scale := max(d.scale, d2.scale)
+	return d.rescale(scale).Cmp(d2.rescale(scale))
+}
+
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IntPart returns the integer part of d, truncated towards zero
+func (d Decimal) IntPart() int64 {
+	return new(big.Int).Quo(d.value(), pow10(d.scale)).Int64()
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String keeps the scale, e.g. "10.50"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// StringFixed rounds to the given number of decimal places before formatting
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places).String()
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + d.String() + "\""), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "" || s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	v, err := NewDecimalFromString(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+	v, err := NewDecimalFromString(string(data))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d *Decimal) Scan(src interface{}) error {
+	var (
+		v   Decimal
+		err error
+	)
+	switch value := src.(type) {
+	case nil:
+		v = Decimal{}
+	case []byte:
+		v, err = NewDecimalFromString(string(value))
+	case string:
+		v, err = NewDecimalFromString(value)
+	case int64:
+		v = NewDecimal(value, 0)
+	case float64:
+		v = NewDecimalFromFloat(value)
+	default:
+		return fmt.Errorf("can not convert %v to decimal", src)
+	}
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimalParseAndFormat(t *testing.T) {
+	cases := map[string]string{
+		"0":       "0",
+		"10.50":   "10.50",
+		"-0.05":   "-0.05",
+		".5":      "0.5",
+		"1.2e3":   "1200",
+		"1.25e-1": "0.125",
+	}
+	for in, expected := range cases {
+		d, err := NewDecimalFromString(in)
+		if err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if d.String() != expected {
+			t.Errorf("%s: expected %s, got %s", in, expected, d)
+		}
+	}
+	for _, in := range []string{"", "abc", "1.2.3", "--1", "1e", "1e-300000000", "1e300000000", "1e1001", ".-5", "-.-5", "1.+2", "-", "+.", "1_000"} {
+		if _, err := NewDecimalFromString(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := RequireDecimalFromString("0.1")
+	b := RequireDecimalFromString("0.2")
+
+	if s := a.Add(b).String(); s != "0.3" {
+		t.Errorf("expected 0.3, got %s", s)
+	}
+	if s := a.Sub(b).String(); s != "-0.1" {
+		t.Errorf("expected -0.1, got %s", s)
+	}
+	if s := NewDecimal(1999, 2).Mul(NewDecimal(3, 0)).String(); s != "59.97" {
+		t.Errorf("expected 59.97, got %s", s)
+	}
+	if s := NewDecimal(10, 0).Div(NewDecimal(3, 0), 2).String(); s != "3.33" {
+		t.Errorf("expected 3.33, got %s", s)
+	}
+	if s := NewDecimal(-2, 0).Div(NewDecimal(3, 0), 2).String(); s != "-0.67" {
+		t.Errorf("expected -0.67, got %s", s)
+	}
+	if s := RequireDecimalFromString("2.345").Round(2).String(); s != "2.35" {
+		t.Errorf("expected 2.35, got %s", s)
+	}
+	if s := RequireDecimalFromString("-2.345").Round(2).String(); s != "-2.35" {
+		t.Errorf("expected -2.35, got %s", s)
+	}
+	if s := RequireDecimalFromString("2.349").Truncate(2).String(); s != "2.34" {
+		t.Errorf("expected 2.34, got %s", s)
+	}
+	if !RequireDecimalFromString("1.50").Equal(RequireDecimalFromString("1.5")) {
+		t.Error("expected 1.50 to equal 1.5")
+	}
+	if RequireDecimalFromString("-7.9").IntPart() != -7 {
+		t.Error("expected integer part -7")
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" {
+		t.Errorf("expected zero value to be 0, got %s", zero)
+	}
+}
+
+func TestDecimalJSONAndSQL(t *testing.T) {
+	var v struct {
+		Amount Decimal `json:"amount"`
+		Price  Decimal `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"12.30","price":0.07}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"amount":"12.30","price":"0.07"}` {
+		t.Errorf("unexpected json %s", data)
+	}
+
+	var d Decimal
+	if err := d.Scan([]byte("99.99")); err != nil || d.String() != "99.99" {
+		t.Errorf("expected 99.99, got %s %v", d, err)
+	}
+	if value, _ := d.Value(); value != "99.99" {
+		t.Errorf("expected 99.99, got %v", value)
+	}
+}
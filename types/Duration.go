@@ -0,0 +1,131 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration accepts "1h30m", "90s", "2d12h" or a number of seconds,
+// and is stored in SQL as a bigint of milliseconds
+type Duration time.Duration
+
+// maxDays is the largest number of days a Duration can hold
+const maxDays = int64(math.MaxInt64 / (24 * time.Hour))
+
+// ParseDuration parses a Go duration string with an optional leading day component,
+// or a plain number of seconds
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		// ParseFloat accepts NaN, Inf and values out of the range of a duration
+		if math.IsNaN(seconds) || math.Abs(seconds) >= float64(math.MaxInt64)/float64(time.Second) {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return Duration(seconds * float64(time.Second)), nil
+	}
+
+	var days time.Duration
+	if before, after, found := strings.Cut(s, "d"); found {
+		n, err := strconv.ParseInt(before, 10, 64)
+		if err != nil || n > maxDays || n < -maxDays {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		if after == "" {
+			return Duration(days), nil
+		}
+		// the sign of the days applies to the rest, including "-0d2h"
+		if strings.HasPrefix(before, "-") {
+			after = "-" + after
+		}
+		s = after
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	sum := days + d
+	if (days > 0 && d > 0 && sum < 0) || (days < 0 && d < 0 && sum > 0) {
+		return 0, fmt.Errorf("duration out of range %q", s)
+	}
+	return Duration(sum), nil
+}
+
+func (col Duration) Duration() time.Duration {
+	return time.Duration(col)
+}
+
+func (col Duration) String() string {
+	return time.Duration(col).String()
+}
+
+func (col Duration) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + col.String() + "\""), nil
+}
+
+func (col *Duration) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		return nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*col = d
+	return nil
+}
+
+func (col Duration) MarshalText() ([]byte, error) {
+	return []byte(col.String()), nil
+}
+
+func (col *Duration) UnmarshalText(data []byte) error {
+	d, err := ParseDuration(string(data))
+	if err != nil {
+		return err
+	}
+	*col = d
+	return nil
+}
+
+func (col *Duration) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = 0
+	case int64:
+		*col = Duration(time.Duration(value) * time.Millisecond)
+	case []byte:
+		return col.scanString(string(value))
+	case string:
+		return col.scanString(value)
+	default:
+		return fmt.Errorf("can not convert %v to duration", src)
+	}
+	return nil
+}
+
+// scanString reads milliseconds, falling back to duration strings
+func (col *Duration) scanString(s string) error {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*col = Duration(time.Duration(ms) * time.Millisecond)
+		return nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*col = d
+	return nil
+}
+
+func (col Duration) Value() (driver.Value, error) {
+	return time.Duration(col).Milliseconds(), nil
+}
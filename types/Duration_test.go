@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"1h30m":  90 * time.Minute,
+		"90s":    90 * time.Second,
+		"90":     90 * time.Second,
+		"1.5":    1500 * time.Millisecond,
+		"2d":     48 * time.Hour,
+		"1d12h":  36 * time.Hour,
+		"-1d12h": -36 * time.Hour,
+		"-0d2h":  -2 * time.Hour,
+		"":       0,
+	}
+	for in, expected := range cases {
+		d, err := ParseDuration(in)
+		if err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if d.Duration() != expected {
+			t.Errorf("%s: expected %v, got %v", in, expected, d)
+		}
+	}
+	for _, in := range []string{"abc", "xd", "1d2", "NaN", "Inf", "-Inf", "1e300", "106752d", "106751d24h"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestDurationJSONAndSQL(t *testing.T) {
+	var v struct {
+		Timeout  Duration `json:"timeout"`
+		Interval Duration `json:"interval"`
+	}
+	if err := json.Unmarshal([]byte(`{"timeout":"1h30m","interval":30}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"timeout":"1h30m0s","interval":"30s"}` {
+		t.Errorf("unexpected json %s", data)
+	}
+
+	if value, _ := v.Interval.Value(); value != int64(30000) {
+		t.Errorf("expected 30000, got %v", value)
+	}
+	var d Duration
+	if err := d.Scan(int64(1500)); err != nil || d.Duration() != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s, got %v %v", d, err)
+	}
+	if err := d.Scan([]byte("250")); err != nil || d.Duration() != 250*time.Millisecond {
+		t.Errorf("expected 250ms, got %v %v", d, err)
+	}
+}
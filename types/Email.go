@@ -0,0 +1,92 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+// Email is a validated address, normalized with a lower case domain.
+// String redacts the local part for logs, use Address for the full value.
+type Email string
+
+// ParseEmail accepts a bare address such as "John@Example.com", display names are rejected
+func ParseEmail(s string) (Email, error) {
+	s = strings.TrimSpace(s)
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return "", fmt.Errorf("invalid email %q", s)
+	}
+	at := strings.LastIndexByte(s, '@')
+	return Email(s[:at] + strings.ToLower(s[at:])), nil
+}
+
+func (col Email) Address() string {
+	return string(col)
+}
+
+func (col Email) Domain() string {
+	return string(col[strings.LastIndexByte(string(col), '@')+1:])
+}
+
+// String returns the address with the local part masked, e.g. "j***@example.com"
+func (col Email) String() string {
+	at := strings.LastIndexByte(string(col), '@')
+	if at <= 0 {
+		return string(col)
+	}
+	_, size := utf8.DecodeRuneInString(string(col))
+	return string(col[:size]) + "***" + string(col[at:])
+}
+
+func (col Email) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(col))
+}
+
+func (col *Email) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col Email) MarshalText() ([]byte, error) {
+	return []byte(col), nil
+}
+
+func (col *Email) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = ""
+		return nil
+	}
+	v, err := ParseEmail(string(data))
+	if err != nil {
+		return err
+	}
+	*col = v
+	return nil
+}
+
+func (col Email) Value() (driver.Value, error) {
+	if col == "" {
+		return nil, nil
+	}
+	return string(col), nil
+}
+
+func (col *Email) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = ""
+		return nil
+	case []byte:
+		return col.UnmarshalText(value)
+	case string:
+		return col.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to Email", src)
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestEmail(t *testing.T) {
+	var v struct {
+		Email Email `json:"email"`
+	}
+	if err := json.Unmarshal([]byte(`{"email":" John.Doe@Example.COM "}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Email.Address() != "John.Doe@example.com" {
+		t.Errorf("unexpected address %s", v.Email.Address())
+	}
+	if s := fmt.Sprint(v.Email); s != "J***@example.com" {
+		t.Errorf("expected a redacted address, got %s", s)
+	}
+	if s := Email("éric@example.com").String(); s != "é***@example.com" {
+		t.Errorf("expected the first rune kept, got %s", s)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"email":"John.Doe@example.com"}` {
+		t.Errorf("unexpected json %s", data)
+	}
+	for _, in := range []string{"john", "John <john@example.com>", "a@b@c"} {
+		if _, err := ParseEmail(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"email":1}`), &v); err == nil {
+		t.Error("expected an error for a number")
+	}
+}
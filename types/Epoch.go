@@ -0,0 +1,99 @@
+package types
+
+import (
+	"database/sql/driver"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EpochUnit selects the precision of an Epoch
+type EpochUnit interface {
+	Unit() time.Duration
+}
+
+type (
+	EpochSecond struct{}
+	EpochMilli  struct{}
+	EpochMicro  struct{}
+	EpochNano   struct{}
+)
+
+func (EpochSecond) Unit() time.Duration { return time.Second }
+func (EpochMilli) Unit() time.Duration  { return time.Millisecond }
+func (EpochMicro) Unit() time.Duration  { return time.Microsecond }
+func (EpochNano) Unit() time.Duration   { return time.Nanosecond }
+
+// Epoch is a Unix timestamp in the precision of U, marshaled as a JSON number.
+// Unlike Jepoch it is never formatted as a datetime string.
+type Epoch[U EpochUnit] int64
+
+// JepochMilli is a Unix timestamp in milliseconds, as used by JS frontends and Kafka
+type JepochMilli = Epoch[EpochMilli]
+
+// EpochOf converts t to an Epoch in the precision of U
+func EpochOf[U EpochUnit](t time.Time) Epoch[U] {
+	if t.IsZero() {
+		return 0
+	}
+	var u U
+	return Epoch[U](t.UnixNano() / int64(u.Unit()))
+}
+
+// NewJepochMilli converts t to Unix milliseconds
+func NewJepochMilli(t time.Time) JepochMilli {
+	return EpochOf[EpochMilli](t)
+}
+
+func (col Epoch[U]) Time() time.Time {
+	var u U
+	return time.Unix(0, int64(col)*int64(u.Unit())).In(Location())
+}
+
+func (col Epoch[U]) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(col), 10)), nil
+}
+
+// UnmarshalJSON accepts numbers, quoted numbers and datetime strings
+func (col *Epoch[U]) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "" || s == "null" {
+		*col = 0
+		return nil
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*col = Epoch[U](v)
+		return nil
+	}
+	t, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*col = EpochOf[U](t)
+	return nil
+}
+
+func (col Epoch[U]) MarshalText() ([]byte, error) {
+	return col.MarshalJSON()
+}
+
+func (col *Epoch[U]) UnmarshalText(data []byte) error {
+	return col.UnmarshalJSON(data)
+}
+
+func (col Epoch[U]) Value() (driver.Value, error) {
+	return int64(col), nil
+}
+
+func (col *Epoch[U]) Scan(src interface{}) error {
+	if t, ok := src.(time.Time); ok {
+		*col = EpochOf[U](t)
+		return nil
+	}
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	*col = Epoch[U](v)
+	return nil
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJepochMilli(t *testing.T) {
+	now := time.UnixMilli(1704135845123)
+	e := NewJepochMilli(now)
+	if int64(e) != 1704135845123 || !e.Time().Equal(now) {
+		t.Errorf("unexpected %v", e)
+	}
+
+	data, _ := json.Marshal(struct{ At JepochMilli }{e})
+	if string(data) != `{"At":1704135845123}` {
+		t.Errorf("unexpected json %s", data)
+	}
+	for _, in := range []string{`1704135845123`, `"1704135845123"`} {
+		var v JepochMilli
+		if err := json.Unmarshal([]byte(in), &v); err != nil || v != e {
+			t.Errorf("%s: unexpected %v %v", in, v, err)
+		}
+	}
+
+	var v JepochMilli
+	if err := json.Unmarshal([]byte(`"2024-01-02 03:04:05"`), &v); err != nil || int64(v) != 1704135845000 {
+		t.Errorf("unexpected %v %v", v, err)
+	}
+}
+
+func TestEpochPrecision(t *testing.T) {
+	at := time.Unix(1704135845, 123456789)
+	if v := EpochOf[EpochSecond](at); v != 1704135845 {
+		t.Errorf("unexpected seconds %v", v)
+	}
+	if v := EpochOf[EpochMicro](at); v != 1704135845123456 {
+		t.Errorf("unexpected microseconds %v", v)
+	}
+
+	var v Epoch[EpochNano]
+	if err := v.Scan(at); err != nil || !v.Time().Equal(at) {
+		t.Errorf("unexpected %v %v", v, err)
+	}
+	if err := v.Scan(nil); err != nil || v != 0 {
+		t.Errorf("unexpected %v %v", v, err)
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+)
+
+// TimeFormat selects the layout of a FormattedTime, e.g.
+//
+//	type SlashDate struct{}
+//
+//	func (SlashDate) Layout() string { return "2006/01/02" }
+//
+//	Birthday types.FormattedTime[SlashDate] `json:"birthday"`
+type TimeFormat interface {
+	Layout() string
+}
+
+type (
+	FormatDateTime struct{}
+	FormatDate     struct{}
+	FormatRFC3339  struct{}
+	FormatKitchen  struct{}
+)
+
+func (FormatDateTime) Layout() string { return DefaultTimeLayout }
+func (FormatDate) Layout() string     { return DefaultDateLayout }
+func (FormatRFC3339) Layout() string  { return time.RFC3339 }
+func (FormatKitchen) Layout() string  { return time.Kitchen }
+
+// FormattedTime is a time formatted with the layout of F in the configured location,
+// so fields can use different layouts without a new type each.
+// The zero time is marshaled as null.
+type FormattedTime[F TimeFormat] time.Time
+
+func (col FormattedTime[F]) Layout() string {
+	var f F
+	return f.Layout()
+}
+
+func (col FormattedTime[F]) Time() time.Time {
+	return time.Time(col)
+}
+
+func (col FormattedTime[F]) IsZero() bool {
+	return time.Time(col).IsZero()
+}
+
+func (col FormattedTime[F]) String() string {
+	if col.IsZero() {
+		return ""
+	}
+	return time.Time(col).In(Location()).Format(col.Layout())
+}
+
+func (col FormattedTime[F]) MarshalJSON() ([]byte, error) {
+	if col.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte("\"" + col.String() + "\""), nil
+}
+
+func (col *FormattedTime[F]) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		s = ""
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col FormattedTime[F]) MarshalText() ([]byte, error) {
+	return []byte(col.String()), nil
+}
+
+// UnmarshalText tries the layout of F first, then falls back to ParseTime
+func (col *FormattedTime[F]) UnmarshalText(data []byte) error {
+	t, err := parseTime(string(data), col.Layout())
+	if err != nil {
+		return err
+	}
+	*col = FormattedTime[F](t)
+	return nil
+}
+
+func (col FormattedTime[F]) Value() (driver.Value, error) {
+	return timeValue(time.Time(col))
+}
+
+func (col *FormattedTime[F]) Scan(src interface{}) error {
+	t, err := scanTime(src, col.Layout())
+	if err != nil {
+		return err
+	}
+	*col = FormattedTime[F](t)
+	return nil
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type slashDate struct{}
+
+func (slashDate) Layout() string { return "2006/01/02" }
+
+func TestFormattedTime(t *testing.T) {
+	var v struct {
+		Birthday FormattedTime[slashDate]     `json:"birthday"`
+		Created  FormattedTime[FormatRFC3339] `json:"created"`
+		Deleted  FormattedTime[FormatDate]    `json:"deleted"`
+	}
+	if err := json.Unmarshal([]byte(`{"birthday":"1990/02/03","created":"2024-05-06 07:08:09","deleted":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Birthday.Time().Equal(time.Date(1990, 2, 3, 0, 0, 0, 0, Location())) {
+		t.Errorf("unexpected birthday %v", v.Birthday.Time())
+	}
+	data, _ := json.Marshal(v)
+	expected := `{"birthday":"1990/02/03","created":"2024-05-06T07:08:09` + time.Date(2024, 5, 6, 0, 0, 0, 0, Location()).Format("Z07:00") + `","deleted":null}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+	if err := v.Birthday.Scan("2001/12/31"); err != nil || v.Birthday.String() != "2001/12/31" {
+		t.Errorf("unexpected scan %s %v", v.Birthday, err)
+	}
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IP wraps netip.Addr, stored in SQL as text (Postgres inet values are scanned too)
+type IP struct {
+	netip.Addr
+}
+
+// CIDR wraps netip.Prefix, stored in SQL as text (Postgres cidr/inet values are scanned too)
+type CIDR struct {
+	netip.Prefix
+}
+
+func ParseIP(s string) (IP, error) {
+	s = strings.TrimSpace(s)
+	// inet columns may carry a host prefix length
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return IP{}, err
+		}
+		return IP{p.Addr()}, nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return IP{}, err
+	}
+	return IP{addr}, nil
+}
+
+// ParseCIDR accepts "10.0.0.0/8" or a single address, which becomes a /32 or /128
+func ParseCIDR(s string) (CIDR, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return CIDR{}, err
+		}
+		return CIDR{netip.PrefixFrom(addr, addr.BitLen())}, nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return CIDR{}, err
+	}
+	return CIDR{p}, nil
+}
+
+func (col IP) MarshalJSON() ([]byte, error) {
+	if !col.IsValid() {
+		return []byte("null"), nil
+	}
+	return []byte("\"" + col.String() + "\""), nil
+}
+
+func (col *IP) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "" || s == "null" {
+		*col = IP{}
+		return nil
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col *IP) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = IP{}
+		return nil
+	}
+	ip, err := ParseIP(string(data))
+	if err != nil {
+		return err
+	}
+	*col = ip
+	return nil
+}
+
+func (col *IP) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = IP{}
+		return nil
+	case []byte:
+		return col.UnmarshalText(value)
+	case string:
+		return col.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to IP", src)
+}
+
+func (col IP) Value() (driver.Value, error) {
+	if !col.IsValid() {
+		return nil, nil
+	}
+	return col.String(), nil
+}
+
+// Contains reports whether ip belongs to the network
+func (col CIDR) Contains(ip IP) bool {
+	return col.Prefix.Contains(ip.Addr)
+}
+
+func (col CIDR) MarshalJSON() ([]byte, error) {
+	if !col.IsValid() {
+		return []byte("null"), nil
+	}
+	return []byte("\"" + col.String() + "\""), nil
+}
+
+func (col *CIDR) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "" || s == "null" {
+		*col = CIDR{}
+		return nil
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col *CIDR) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = CIDR{}
+		return nil
+	}
+	c, err := ParseCIDR(string(data))
+	if err != nil {
+		return err
+	}
+	*col = c
+	return nil
+}
+
+func (col *CIDR) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = CIDR{}
+		return nil
+	case []byte:
+		return col.UnmarshalText(value)
+	case string:
+		return col.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to CIDR", src)
+}
+
+func (col CIDR) Value() (driver.Value, error) {
+	if !col.IsValid() {
+		return nil, nil
+	}
+	return col.String(), nil
+}
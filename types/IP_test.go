@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIPAndCIDR(t *testing.T) {
+	var v struct {
+		IP   IP   `json:"ip"`
+		Net  CIDR `json:"net"`
+		None IP   `json:"none"`
+	}
+	if err := json.Unmarshal([]byte(`{"ip":"10.1.2.3","net":"10.0.0.0/8","none":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Net.Contains(v.IP) || v.None.IsValid() {
+		t.Errorf("unexpected %+v", v)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"ip":"10.1.2.3","net":"10.0.0.0/8","none":null}` {
+		t.Errorf("unexpected json %s", data)
+	}
+
+	var ip IP
+	if err := ip.Scan([]byte("192.168.0.1/32")); err != nil || ip.String() != "192.168.0.1" {
+		t.Errorf("unexpected %v %v", ip, err)
+	}
+	if err := ip.Scan("not an ip"); err == nil {
+		t.Error("expected an error")
+	}
+
+	single, err := ParseCIDR("2001:db8::1")
+	if err != nil || single.String() != "2001:db8::1/128" {
+		t.Errorf("unexpected %v %v", single, err)
+	}
+	if value, _ := single.Value(); value != "2001:db8::1/128" {
+		t.Errorf("unexpected value %v", value)
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"database/sql/driver"
 	"strconv"
 	"strings"
 )
@@ -16,3 +17,29 @@ func (col *Int64) UnmarshalJSON(src []byte) error {
 	*col = Int64(data)
 	return nil
 }
+
+func (col *Int64) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(*col), 10)), nil
+}
+
+func (col *Int64) UnmarshalText(data []byte) error {
+	v, err := parseInt64(string(data))
+	if err != nil {
+		return err
+	}
+	*col = Int64(v)
+	return nil
+}
+
+func (col Int64) Value() (driver.Value, error) {
+	return int64(col), nil
+}
+
+func (col *Int64) Scan(src interface{}) error {
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	*col = Int64(v)
+	return nil
+}
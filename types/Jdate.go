@@ -1,6 +1,7 @@
 package types
 
 import (
+	"database/sql/driver"
 	"fmt"
 
 	"time"
@@ -9,19 +10,19 @@ import (
 type Jdate string
 
 func (col Jdate) MarshalCSV() (string, error) {
-	t, err := time.ParseInLocation("2006-01-02", string(col), cstZone)
+	t, err := time.ParseInLocation(DateLayout(), string(col), Location())
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("\"%s\"", t.Format("2006-01-02")), nil
+	return fmt.Sprintf("\"%s\"", t.Format(DateLayout())), nil
 }
 
 func (col Jdate) MarshalJSON() ([]byte, error) {
-	t, err := time.ParseInLocation("2006-01-02", string(col), cstZone)
+	t, err := time.ParseInLocation(DateLayout(), string(col), Location())
 	if err != nil {
 		return nil, err
 	}
-	var stamp = fmt.Sprintf("\"%s\"", t.Format("2006-01-02"))
+	var stamp = fmt.Sprintf("\"%s\"", t.Format(DateLayout()))
 	return []byte(stamp), nil
 }
 
@@ -30,10 +31,54 @@ func (col *Jdate) UnmarshalJSON(data []byte) error {
 	if s == "" {
 		return nil
 	}
-	t, err := time.ParseInLocation("2006-01-02", s, cstZone) //cstZone
+	t, err := time.ParseInLocation(DateLayout(), s, Location())
 	if err != nil {
 		return err
 	}
-	*col = Jdate(t.Format("2006-01-02"))
+	*col = Jdate(t.Format(DateLayout()))
+	return nil
+}
+
+func (col Jdate) MarshalText() ([]byte, error) {
+	if col == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(DateLayout(), string(col), Location())
+	if err != nil {
+		return nil, err
+	}
+	return []byte(t.Format(DateLayout())), nil
+}
+
+func (col *Jdate) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = ""
+		return nil
+	}
+	t, err := time.ParseInLocation(DateLayout(), string(data), Location())
+	if err != nil {
+		return err
+	}
+	*col = Jdate(t.Format(DateLayout()))
+	return nil
+}
+
+func (col Jdate) Value() (driver.Value, error) {
+	if col == "" {
+		return nil, nil
+	}
+	return string(col), nil
+}
+
+func (col *Jdate) Scan(src interface{}) error {
+	t, err := scanTime(src, DateLayout())
+	if err != nil {
+		return err
+	}
+	if t.IsZero() {
+		*col = ""
+		return nil
+	}
+	*col = Jdate(t.In(Location()).Format(DateLayout()))
 	return nil
 }
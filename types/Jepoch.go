@@ -1,6 +1,7 @@
 package types
 
 import (
+	"database/sql/driver"
 	"fmt"
 
 	"time"
@@ -9,11 +10,11 @@ import (
 type Jepoch int64
 
 func (col Jepoch) MarshalCSV() (string, error) {
-	return fmt.Sprintf("\"%s\"", time.Time(time.Unix(int64(col), 0)).In(cstZone).Format("2006-01-02 15:04:05")), nil
+	return fmt.Sprintf("\"%s\"", time.Time(time.Unix(int64(col), 0)).In(Location()).Format(TimeLayout())), nil
 }
 
 func (col Jepoch) MarshalJSON() ([]byte, error) {
-	var stamp = fmt.Sprintf("\"%s\"", time.Time(time.Unix(int64(col), 0)).In(cstZone).Format("2006-01-02 15:04:05"))
+	var stamp = fmt.Sprintf("\"%s\"", time.Time(time.Unix(int64(col), 0)).In(Location()).Format(TimeLayout()))
 	return []byte(stamp), nil
 }
 
@@ -23,11 +24,41 @@ func (col *Jepoch) UnmarshalJSON(data []byte) error {
 		*col = Jepoch(0)
 		return nil
 	}
-	//t, err := time.Parse("2006-01-02 15:04:05", s)
-	t, err := time.ParseInLocation("2006-01-02 15:04:05", s, cstZone) //cstZone
+	t, err := ParseTime(s)
 	if err != nil {
 		return err
 	}
 	*col = Jepoch(t.Unix())
 	return nil
 }
+
+func (col Jepoch) MarshalText() ([]byte, error) {
+	return []byte(time.Unix(int64(col), 0).In(Location()).Format(TimeLayout())), nil
+}
+
+// UnmarshalText accepts the same datetime strings and epochs as ParseTime
+func (col *Jepoch) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = Jepoch(0)
+		return nil
+	}
+	t, err := ParseTime(string(data))
+	if err != nil {
+		return err
+	}
+	*col = Jepoch(t.Unix())
+	return nil
+}
+
+func (col Jepoch) Value() (driver.Value, error) {
+	return int64(col), nil
+}
+
+func (col *Jepoch) Scan(src interface{}) error {
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	*col = Jepoch(v)
+	return nil
+}
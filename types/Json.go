@@ -1,11 +1,113 @@
 package types
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
 type Json string
 
+// Valid reports whether col holds valid JSON, the empty value is treated as {}
+func (col Json) Valid() bool {
+	return col == "" || json.Valid([]byte(col))
+}
+
+// Get extracts the value at a dotted path such as "user.tags.0"
+func (col Json) Get(path string) (Json, bool) {
+	v, err := decodeJSON(string(col))
+	if err != nil {
+		return "", false
+	}
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			switch node := v.(type) {
+			case map[string]interface{}:
+				child, ok := node[key]
+				if !ok {
+					return "", false
+				}
+				v = child
+			case []interface{}:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(node) {
+					return "", false
+				}
+				v = node[i]
+			default:
+				return "", false
+			}
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return Json(data), true
+}
+
+// Merge deep-merges the objects of other into col, values of other take precedence
+func (col Json) Merge(other Json) (Json, error) {
+	dst, err := decodeJSON(string(col.orEmpty()))
+	if err != nil {
+		return "", err
+	}
+	src, err := decodeJSON(string(other.orEmpty()))
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(mergeJSON(dst, src))
+	if err != nil {
+		return "", err
+	}
+	return Json(data), nil
+}
+
+// decodeJSON decodes a single JSON value, numbers are kept as json.Number so large integers are not rounded
+func decodeJSON(data string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: data after the top-level value")
+	}
+	return v, nil
+}
+
+func mergeJSON(dst, src interface{}) interface{} {
+	dstMap, ok1 := dst.(map[string]interface{})
+	srcMap, ok2 := src.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return src
+	}
+	for key, value := range srcMap {
+		if existing, ok := dstMap[key]; ok {
+			dstMap[key] = mergeJSON(existing, value)
+		} else {
+			dstMap[key] = value
+		}
+	}
+	return dstMap
+}
+
+func (col Json) orEmpty() Json {
+	if col == "" {
+		return "{}"
+	}
+	return col
+}
+
 func (col Json) MarshalJSON() ([]byte, error) {
-	s := string(col)
-	if s == "" {
-		s = "{}"
+	s := string(col.orEmpty())
+	if !json.Valid([]byte(s)) {
+		return nil, fmt.Errorf("invalid json %q", s)
 	}
 	return []byte(s), nil
 }
@@ -14,3 +116,39 @@ func (col *Json) UnmarshalJSON(data []byte) error {
 	*col = Json(data)
 	return nil
 }
+
+func (col Json) MarshalText() ([]byte, error) {
+	return col.MarshalJSON()
+}
+
+func (col *Json) UnmarshalText(data []byte) error {
+	if len(data) > 0 && !json.Valid(data) {
+		return fmt.Errorf("invalid json %q", data)
+	}
+	*col = Json(data)
+	return nil
+}
+
+func (col *Json) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = ""
+	case []byte:
+		*col = Json(value)
+	case string:
+		*col = Json(value)
+	default:
+		return fmt.Errorf("can not convert %v to json", src)
+	}
+	return nil
+}
+
+func (col Json) Value() (driver.Value, error) {
+	if col == "" {
+		return nil, nil
+	}
+	if !col.Valid() {
+		return nil, fmt.Errorf("invalid json %q", string(col))
+	}
+	return string(col), nil
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJsonGetAndMerge(t *testing.T) {
+	doc := Json(`{"user":{"name":"foo","tags":["a","b"]},"n":1}`)
+
+	cases := map[string]Json{
+		"user.name":   `"foo"`,
+		"user.tags.1": `"b"`,
+		"n":           `1`,
+	}
+	for path, expected := range cases {
+		if v, ok := doc.Get(path); !ok || v != expected {
+			t.Errorf("%s: expected %s, got %s", path, expected, v)
+		}
+	}
+	for _, path := range []string{"user.age", "user.tags.2", "n.x"} {
+		if _, ok := doc.Get(path); ok {
+			t.Errorf("%s: expected no value", path)
+		}
+	}
+
+	merged, err := doc.Merge(`{"user":{"name":"bar","age":3},"m":true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged != `{"m":true,"n":1,"user":{"age":3,"name":"bar","tags":["a","b"]}}` {
+		t.Errorf("unexpected merge %s", merged)
+	}
+
+	// integers above 2^53 must not go through float64
+	big := Json(`{"id":9007199254740993}`)
+	if v, ok := big.Get("id"); !ok || v != `9007199254740993` {
+		t.Errorf("expected the exact id, got %s", v)
+	}
+	if merged, err := big.Merge(`{"n":1}`); err != nil || merged != `{"id":9007199254740993,"n":1}` {
+		t.Errorf("expected the exact id, got %s %v", merged, err)
+	}
+	if _, err := big.Merge(`{"n":1} {}`); err == nil {
+		t.Error("expected an error for trailing data")
+	}
+}
+
+func TestJsonValidation(t *testing.T) {
+	if Json(`{"a":`).Valid() || !Json("").Valid() {
+		t.Error("unexpected validity")
+	}
+	if _, err := json.Marshal(struct{ Data Json }{Json(`{"a":`)}); err == nil {
+		t.Error("expected invalid json to fail marshaling")
+	}
+	if _, err := Json(`{"a":`).Value(); err == nil {
+		t.Error("expected invalid json to fail as a SQL value")
+	}
+	var j Json
+	if err := j.Scan([]byte(`{"a":1}`)); err != nil || j != `{"a":1}` {
+		t.Errorf("unexpected %s %v", j, err)
+	}
+}
@@ -1,37 +1,61 @@
 package types
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"strings"
 
 	"time"
 )
 
-// var cstZone = time.FixedZone("CST", 8*3600)       // 东八
-var cstZone, _ = time.LoadLocation("Asia/Shanghai")
-
-func init() {
-	time.Local = cstZone
-}
-
 type Jtime time.Time
 
 func (col Jtime) MarshalCSV() (string, error) {
-	return fmt.Sprintf("\"%s\"", time.Time(col).In(cstZone).Format("2006-01-02 15:04:05")), nil
+	return fmt.Sprintf("\"%s\"", time.Time(col).In(Location()).Format(TimeLayout())), nil
 }
 
 func (col Jtime) MarshalJSON() ([]byte, error) {
-	var stamp = fmt.Sprintf("\"%s\"", time.Time(col).In(cstZone).Format("2006-01-02 15:04:05"))
+	var stamp = fmt.Sprintf("\"%s\"", time.Time(col).In(Location()).Format(TimeLayout()))
 	return []byte(stamp), nil
 }
 
 func (col *Jtime) UnmarshalJSON(data []byte) error {
-	s, _ := stringUnmarshalJSON(data)
-	if s == "" {
+	s := strings.Trim(string(data), "\"")
+	if s == "" || s == "null" {
 		*col = Jtime(time.Now())
 		return nil
 	}
-	//t, err := time.Parse("2006-01-02 15:04:05", s)
-	t, err := time.ParseInLocation("2006-01-02 15:04:05", s, cstZone) //cstZone
+	t, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*col = Jtime(t)
+	return nil
+}
+
+func (col Jtime) MarshalText() ([]byte, error) {
+	return []byte(time.Time(col).In(Location()).Format(TimeLayout())), nil
+}
+
+// UnmarshalText leaves col untouched for empty text
+func (col *Jtime) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	t, err := ParseTime(string(data))
+	if err != nil {
+		return err
+	}
+	*col = Jtime(t)
+	return nil
+}
+
+func (col Jtime) Value() (driver.Value, error) {
+	return timeValue(time.Time(col))
+}
+
+func (col *Jtime) Scan(src interface{}) error {
+	t, err := scanTime(src, TimeLayout())
 	if err != nil {
 		return err
 	}
@@ -31,14 +31,14 @@ func (t *LocalTime) String() string {
 	if t == nil || t.IsZero() {
 		return ""
 	}
-	return time.Time(*t).Format("2006-01-02 15:04:05")
+	return time.Time(*t).In(Location()).Format(TimeLayout())
 }
 
 func (t *LocalTime) GetDate() string {
 	if t == nil || t.IsZero() {
 		return ""
 	}
-	return time.Time(*t).Format(time.DateOnly)
+	return time.Time(*t).In(Location()).Format(time.DateOnly)
 }
 
 func (t *LocalTime) IsZero() bool {
@@ -54,7 +54,7 @@ func (t *LocalTime) UnmarshalJSON(data []byte) error {
 	str := string(data)
 	//去除接收的str收尾多余的"
 	timeStr := strings.Trim(str, "\"")
-	t1, err := time.ParseInLocation("2006-01-02 15:04:05", timeStr, time.Local)
+	t1, err := ParseTime(timeStr)
 	*t = LocalTime(t1)
 	return err
 }
@@ -65,7 +65,27 @@ func (t LocalTime) MarshalJSON() ([]byte, error) {
 	if t.IsZero() {
 		return []byte("null"), nil
 	}
-	return []byte(fmt.Sprintf("\"%s\"", tTime.Format("2006-01-02 15:04:05"))), nil
+	return []byte(fmt.Sprintf("\"%s\"", tTime.In(Location()).Format(TimeLayout()))), nil
+}
+
+func (t LocalTime) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return []byte(time.Time(t).In(Location()).Format(TimeLayout())), nil
+}
+
+func (t *LocalTime) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*t = LocalTime{}
+		return nil
+	}
+	t1, err := ParseTime(string(data))
+	if err != nil {
+		return err
+	}
+	*t = LocalTime(t1)
+	return nil
 }
 
 type LocalDate time.Time
@@ -92,7 +112,7 @@ func (t *LocalDate) String() string {
 	if t == nil || t.IsZero() {
 		return ""
 	}
-	return time.Time(*t).Format("2006-01-02")
+	return time.Time(*t).In(Location()).Format(DateLayout())
 }
 
 func (t *LocalDate) IsZero() bool {
@@ -108,7 +128,7 @@ func (t *LocalDate) UnmarshalJSON(data []byte) error {
 	str := string(data)
 	//去除接收的str收尾多余的"
 	timeStr := strings.Trim(str, "\"")
-	t1, err := time.ParseInLocation("2006-01-02", timeStr, time.Local)
+	t1, err := time.ParseInLocation(DateLayout(), timeStr, Location())
 	*t = LocalDate(t1)
 	return err
 }
@@ -119,7 +139,27 @@ func (t LocalDate) MarshalJSON() ([]byte, error) {
 	if t.IsZero() {
 		return []byte("null"), nil
 	}
-	return []byte(fmt.Sprintf("\"%s\"", tTime.Format("2006-01-02"))), nil
+	return []byte(fmt.Sprintf("\"%s\"", tTime.In(Location()).Format(DateLayout()))), nil
+}
+
+func (t LocalDate) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return []byte(time.Time(t).In(Location()).Format(DateLayout())), nil
+}
+
+func (t *LocalDate) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*t = LocalDate{}
+		return nil
+	}
+	t1, err := time.ParseInLocation(DateLayout(), string(data), Location())
+	if err != nil {
+		return err
+	}
+	*t = LocalDate(t1)
+	return nil
 }
 
 // 小时
@@ -147,7 +187,7 @@ func (t *LocalHour) String() string {
 	if t == nil || t.IsZero() {
 		return ""
 	}
-	return time.Time(*t).Format("2006-01-02 15")
+	return time.Time(*t).In(Location()).Format(HourLayout())
 }
 
 func (t *LocalHour) IsZero() bool {
@@ -163,7 +203,7 @@ func (t *LocalHour) UnmarshalJSON(data []byte) error {
 	str := string(data)
 	//去除接收的str收尾多余的"
 	timeStr := strings.Trim(str, "\"")
-	t1, err := time.ParseInLocation("2006-01-02 15", timeStr, time.Local)
+	t1, err := time.ParseInLocation(HourLayout(), timeStr, Location())
 	*t = LocalHour(t1)
 	return err
 }
@@ -174,5 +214,25 @@ func (t LocalHour) MarshalJSON() ([]byte, error) {
 	if t.IsZero() {
 		return []byte("null"), nil
 	}
-	return []byte(fmt.Sprintf("\"%s\"", tTime.Format("2006-01-02 15"))), nil
+	return []byte(fmt.Sprintf("\"%s\"", tTime.In(Location()).Format(HourLayout()))), nil
+}
+
+func (t LocalHour) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return []byte(time.Time(t).In(Location()).Format(HourLayout())), nil
+}
+
+func (t *LocalHour) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*t = LocalHour{}
+		return nil
+	}
+	t1, err := time.ParseInLocation(HourLayout(), string(data), Location())
+	if err != nil {
+		return err
+	}
+	*t = LocalHour(t1)
+	return nil
 }
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLocalTimeRoundTrip(t *testing.T) {
+	// 取值不在配置时区，格式化必须先转换到 Location
+	at := time.Date(2024, 5, 6, 23, 30, 0, 0, time.UTC)
+	v := struct {
+		Time LocalTime `json:"time"`
+		Date LocalDate `json:"date"`
+		Hour LocalHour `json:"hour"`
+	}{LocalTime(at), LocalDate(at), LocalHour(at)}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := at.In(Location())
+	expected := `{"time":"` + local.Format(TimeLayout()) + `","date":"` + local.Format(DateLayout()) + `","hour":"` + local.Format(HourLayout()) + `"}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	v.Time, v.Date, v.Hour = LocalTime{}, LocalDate{}, LocalHour{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(v.Time).Equal(at) {
+		t.Errorf("expected %v, got %v", at, time.Time(v.Time))
+	}
+	if !time.Time(v.Hour).Equal(at.Truncate(time.Hour)) {
+		t.Errorf("expected %v, got %v", at.Truncate(time.Hour), time.Time(v.Hour))
+	}
+	if date := time.Time(v.Date); date.Format(DateLayout()) != local.Format(DateLayout()) {
+		t.Errorf("expected %s, got %v", local.Format(DateLayout()), date)
+	}
+
+	text, err := LocalTime(at).MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed LocalTime
+	if err := parsed.UnmarshalText(text); err != nil || !time.Time(parsed).Equal(at) {
+		t.Errorf("expected %v, got %v %v", at, time.Time(parsed), err)
+	}
+	if parsed.String() != local.Format(TimeLayout()) {
+		t.Errorf("expected %s, got %s", local.Format(TimeLayout()), parsed.String())
+	}
+}
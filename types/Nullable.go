@@ -0,0 +1,132 @@
+package types
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+)
+
+// Nullable holds a value that may be null in JSON and SQL
+type Nullable[T any] struct {
+	V     T
+	Valid bool
+}
+
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{V: v, Valid: true}
+}
+
+// NullableFromPtr returns an invalid Nullable for a nil pointer
+func NullableFromPtr[T any](v *T) Nullable[T] {
+	if v == nil {
+		return Nullable[T]{}
+	}
+	return NewNullable(*v)
+}
+
+// Ptr returns nil when the value is null
+func (col Nullable[T]) Ptr() *T {
+	if !col.Valid {
+		return nil
+	}
+	v := col.V
+	return &v
+}
+
+// OrElse returns def when the value is null
+func (col Nullable[T]) OrElse(def T) T {
+	if !col.Valid {
+		return def
+	}
+	return col.V
+}
+
+func (col Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !col.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(col.V)
+}
+
+func (col *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*col = Nullable[T]{}
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*col = NewNullable(v)
+	return nil
+}
+
+// MarshalText uses the text form of T, falling back to its JSON form. Null is empty.
+func (col Nullable[T]) MarshalText() ([]byte, error) {
+	if !col.Valid {
+		return nil, nil
+	}
+	switch v := any(col.V).(type) {
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	case string:
+		return []byte(v), nil
+	}
+	return json.Marshal(col.V)
+}
+
+// UnmarshalText treats empty text as null
+func (col *Nullable[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = Nullable[T]{}
+		return nil
+	}
+	var v T
+	switch p := any(&v).(type) {
+	case encoding.TextUnmarshaler:
+		if err := p.UnmarshalText(data); err != nil {
+			return err
+		}
+	case *string:
+		*p = string(data)
+	default:
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+	}
+	*col = NewNullable(v)
+	return nil
+}
+
+func (col *Nullable[T]) Scan(src interface{}) error {
+	if src == nil {
+		*col = Nullable[T]{}
+		return nil
+	}
+	// T handles its own conversion
+	if scanner, ok := any(&col.V).(sql.Scanner); ok {
+		if err := scanner.Scan(src); err != nil {
+			return err
+		}
+		col.Valid = true
+		return nil
+	}
+	var n sql.Null[T]
+	if err := n.Scan(src); err != nil {
+		return err
+	}
+	*col = Nullable[T]{V: n.V, Valid: n.Valid}
+	return nil
+}
+
+func (col Nullable[T]) Value() (driver.Value, error) {
+	if !col.Valid {
+		return nil, nil
+	}
+	if valuer, ok := any(col.V).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(col.V)
+}
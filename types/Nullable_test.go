@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNullableJSON(t *testing.T) {
+	var v struct {
+		Name Nullable[string] `json:"name"`
+		Age  Nullable[int]    `json:"age"`
+	}
+	if err := json.Unmarshal([]byte(`{"name":"foo","age":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Name.Valid || v.Name.V != "foo" || v.Age.Valid {
+		t.Fatalf("unexpected value %+v", v)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"name":"foo","age":null}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+}
+
+func TestNullableSQL(t *testing.T) {
+	var n Nullable[int64]
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Fatalf("expected null, got %+v %v", n, err)
+	}
+	if err := n.Scan([]byte("42")); err != nil || !n.Valid || n.V != 42 {
+		t.Fatalf("expected 42, got %+v %v", n, err)
+	}
+	if v, _ := n.Value(); v != int64(42) {
+		t.Fatalf("expected 42, got %v", v)
+	}
+	if v, _ := (Nullable[int64]{}).Value(); v != nil {
+		t.Fatalf("expected nil, got %v", v)
+	}
+
+	now := time.Now()
+	var lt Nullable[LocalTime]
+	if err := lt.Scan(now); err != nil || !lt.Valid || !time.Time(lt.V).Equal(now) {
+		t.Fatalf("expected %v, got %+v %v", now, lt, err)
+	}
+	if v, _ := lt.Value(); v != now {
+		t.Fatalf("expected %v, got %v", now, v)
+	}
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+	m.Set("z", 4)
+	m.Delete("a")
+
+	if fmt.Sprint(m.Keys()) != "[z m]" {
+		t.Errorf("unexpected keys %v", m.Keys())
+	}
+	if v, ok := m.Get("z"); !ok || v != 4 {
+		t.Errorf("unexpected value %v", v)
+	}
+	data, _ := json.Marshal(m)
+	if string(data) != `{"z":4,"m":3}` {
+		t.Errorf("unexpected json %s", data)
+	}
+}
+
+func TestOrderedMapUnmarshalJSON(t *testing.T) {
+	in := `{"sign":"x","b":{"k":1},"a":[1,2],"c":null}`
+
+	var m OrderedMap[string, json.RawMessage]
+	if err := json.Unmarshal([]byte(in), &m); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(m.Keys()) != "[sign b a c]" {
+		t.Errorf("unexpected keys %v", m.Keys())
+	}
+	data, _ := json.Marshal(m)
+	if string(data) != in {
+		t.Errorf("unexpected json %s", data)
+	}
+	if err := json.Unmarshal([]byte(`[1]`), &m); err == nil {
+		t.Error("expected an error")
+	}
+}
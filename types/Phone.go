@@ -0,0 +1,167 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// callingCodes maps the regions known to SetPhoneRegion and ParsePhone to their calling codes
+var callingCodes = map[string]string{
+	"CN": "86",
+	"HK": "852",
+	"MO": "853",
+	"TW": "886",
+	"JP": "81",
+	"KR": "82",
+	"SG": "65",
+	"IN": "91",
+	"AU": "61",
+	"US": "1",
+	"CA": "1",
+	"GB": "44",
+	"DE": "49",
+	"FR": "33",
+}
+
+// SetPhoneRegion changes the region of numbers without a "+" calling code, CN by default
+func SetPhoneRegion(region string) error {
+	region = strings.ToUpper(region)
+	if _, ok := callingCodes[region]; !ok {
+		return fmt.Errorf("unknown phone region %q", region)
+	}
+	config.Lock()
+	config.phoneRegion = region
+	config.Unlock()
+	return nil
+}
+
+// PhoneRegion returns the region of numbers without a "+" calling code
+func PhoneRegion() string {
+	config.RLock()
+	defer config.RUnlock()
+	return config.phoneRegion
+}
+
+// Phone is a validated number normalized to E.164, e.g. "+8613800138000".
+// String masks the middle digits for logs, use Number for the full value.
+type Phone string
+
+// ParsePhone normalizes s to E.164, national numbers get the calling code of region
+// (or of PhoneRegion if empty) with the trunk prefix 0 removed
+func ParsePhone(s, region string) (Phone, error) {
+	var sb strings.Builder
+	for i, r := range strings.TrimSpace(s) {
+		switch {
+		case r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		case r == '+' && i == 0:
+			sb.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("invalid phone %q", s)
+		}
+	}
+
+	number := sb.String()
+	switch {
+	case strings.HasPrefix(number, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = "+" + number[2:]
+	default:
+		if region == "" {
+			region = PhoneRegion()
+		}
+		code, ok := callingCodes[strings.ToUpper(region)]
+		if !ok {
+			return "", fmt.Errorf("unknown phone region %q", region)
+		}
+		number = "+" + code + strings.TrimPrefix(number, "0")
+	}
+
+	// E.164 allows at most 15 digits
+	if digits := len(number) - 1; digits < 7 || digits > 15 || number[1] == '0' {
+		return "", fmt.Errorf("invalid phone %q", s)
+	}
+	return Phone(number), nil
+}
+
+func (col Phone) Number() string {
+	return string(col)
+}
+
+// String keeps the calling code and the last 4 digits, e.g. "+86*******8000".
+// At least one digit is always masked, short numbers keep fewer trailing digits.
+func (col Phone) String() string {
+	if col == "" {
+		return ""
+	}
+	code := callingCode(string(col))
+	rest := string(col[1+len(code):])
+	keep := 4
+	if keep >= len(rest) {
+		keep = len(rest) / 2
+	}
+	return "+" + code + strings.Repeat("*", len(rest)-keep) + rest[len(rest)-keep:]
+}
+
+// callingCode returns the longest known calling code prefixing an E.164 number, or "" if unknown
+func callingCode(number string) string {
+	var longest string
+	for _, code := range callingCodes {
+		if len(code) > len(longest) && strings.HasPrefix(number[1:], code) {
+			longest = code
+		}
+	}
+	return longest
+}
+
+func (col Phone) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(col))
+}
+
+func (col *Phone) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col Phone) MarshalText() ([]byte, error) {
+	return []byte(col), nil
+}
+
+func (col *Phone) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = ""
+		return nil
+	}
+	v, err := ParsePhone(string(data), "")
+	if err != nil {
+		return err
+	}
+	*col = v
+	return nil
+}
+
+func (col Phone) Value() (driver.Value, error) {
+	if col == "" {
+		return nil, nil
+	}
+	return string(col), nil
+}
+
+func (col *Phone) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = ""
+		return nil
+	case []byte:
+		return col.UnmarshalText(value)
+	case string:
+		return col.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to Phone", src)
+}
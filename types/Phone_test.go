@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestPhone(t *testing.T) {
+	cases := []struct {
+		in, region, expected string
+	}{
+		{"138 0013 8000", "", "+8613800138000"},
+		{"010-12345678", "CN", "+861012345678"},
+		{"(415) 555-2671", "US", "+14155552671"},
+		{"0044 20 7946 0958", "", "+442079460958"},
+		{"+852 2123 4567", "", "+85221234567"},
+	}
+	for _, c := range cases {
+		p, err := ParsePhone(c.in, c.region)
+		if err != nil || p.Number() != c.expected {
+			t.Errorf("%q: expected %s, got %s %v", c.in, c.expected, p.Number(), err)
+		}
+	}
+	for _, in := range []string{"abc", "123", "+1234567890123456", "138-0013-8000x"} {
+		if _, err := ParsePhone(in, ""); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+
+	var p Phone
+	if err := p.Scan([]byte("13800138000")); err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(p); s != "+86*******8000" {
+		t.Errorf("expected a redacted number, got %s", s)
+	}
+	for number, expected := range map[Phone]string{
+		"+85221234567": "+852****4567",
+		"+14155552671": "+1******2671",
+		"+8521234":     "+852**34",
+		"+9991234":     "+***1234",
+	} {
+		if s := number.String(); s != expected {
+			t.Errorf("%s: expected %s, got %s", number.Number(), expected, s)
+		}
+	}
+	if err := SetPhoneRegion("xx"); err == nil {
+		t.Error("expected an error for an unknown region")
+	}
+
+	var v struct {
+		Phone Phone `json:"phone"`
+	}
+	if err := json.Unmarshal([]byte(`{"phone":"138 0013 8000"}`), &v); err != nil || v.Phone.Number() != "+8613800138000" {
+		t.Errorf("unexpected phone %s %v", v.Phone.Number(), err)
+	}
+	if data, _ := json.Marshal(v); string(data) != `{"phone":"+8613800138000"}` {
+		t.Errorf("unexpected json %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"phone":13800138000}`), &v); err == nil {
+		t.Error("expected an error for a number")
+	}
+	if err := json.Unmarshal([]byte(`{"phone":null}`), &v); err != nil || v.Phone != "" {
+		t.Errorf("expected null to clear the phone, got %s %v", v.Phone.Number(), err)
+	}
+}
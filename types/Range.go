@@ -0,0 +1,292 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errInvalidRange = errors.New("range start is after end")
+
+// DateRange is an inclusive range of days, a zero bound is open.
+// It's stored in SQL as a range string "[2024-01-01,2024-01-31]", exclusive bounds
+// such as PostgreSQL's "[2024-01-01,2024-02-01)" are converted to the inclusive days;
+// use the gorm embedded tag to store Start and End as two columns instead.
+type DateRange struct {
+	Start LocalDate `json:"start"`
+	End   LocalDate `json:"end"`
+}
+
+// TimeRange is an inclusive range of times, a zero bound is open.
+// It's stored in SQL as a range string "[2024-01-01 00:00:00,2024-01-31 23:59:59]", exclusive bounds
+// such as PostgreSQL's "[2024-01-01 00:00:00,2024-02-01 00:00:00)" are moved inward by a nanosecond
+// and written back exclusive; use the gorm embedded tag to store Start and End as two columns instead.
+type TimeRange struct {
+	Start LocalTime `json:"start"`
+	End   LocalTime `json:"end"`
+}
+
+// truncateDay returns midnight of the day of t in the configured location
+func truncateDay(t time.Time) time.Time {
+	t = t.In(Location())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Location())
+}
+
+// rangeContains checks t against inclusive bounds, zero bounds are open
+func rangeContains(start, end, t time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end))
+}
+
+// rangeOverlaps checks two inclusive ranges, zero bounds are open
+func rangeOverlaps(start1, end1, start2, end2 time.Time) bool {
+	return (end2.IsZero() || start1.IsZero() || !start1.After(end2)) &&
+		(end1.IsZero() || start2.IsZero() || !start2.After(end1))
+}
+
+// splitRange parses "[a,b]", "[a,b)", "(a,b]", "a,b" or "a~b", reporting which bounds are exclusive
+func splitRange(s string) (start, end string, startExclusive, endExclusive bool, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") {
+		startExclusive = true
+	}
+	if strings.HasSuffix(s, ")") {
+		endExclusive = true
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "]"), ")")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "["), "(")
+	sep := ","
+	if !strings.Contains(s, sep) {
+		sep = "~"
+	}
+	start, end, found := strings.Cut(s, sep)
+	if !found {
+		return "", "", false, false, fmt.Errorf("invalid range %q", s)
+	}
+	return strings.Trim(strings.TrimSpace(start), "\""), strings.Trim(strings.TrimSpace(end), "\""), startExclusive, endExclusive, nil
+}
+
+func (r DateRange) Validate() error {
+	if !r.Start.IsZero() && !r.End.IsZero() && time.Time(r.Start).After(time.Time(r.End)) {
+		return errInvalidRange
+	}
+	return nil
+}
+
+// Contains reports whether the day of t is within the range
+func (r DateRange) Contains(t time.Time) bool {
+	start, end := time.Time(r.Start), time.Time(r.End)
+	if !start.IsZero() {
+		start = truncateDay(start)
+	}
+	if !end.IsZero() {
+		end = truncateDay(end)
+	}
+	return rangeContains(start, end, truncateDay(t))
+}
+
+func (r DateRange) Overlaps(other DateRange) bool {
+	return rangeOverlaps(time.Time(r.Start), time.Time(r.End), time.Time(other.Start), time.Time(other.End))
+}
+
+// Days returns the number of days in a closed range, or 0 if a bound is open
+func (r DateRange) Days() int {
+	if r.Start.IsZero() || r.End.IsZero() {
+		return 0
+	}
+	return int(truncateDay(time.Time(r.End)).Sub(truncateDay(time.Time(r.Start))).Hours()/24) + 1
+}
+
+// MarshalJSON keeps the object form, MarshalText is only used for keys and text encoders
+func (r DateRange) MarshalJSON() ([]byte, error) {
+	type plain DateRange
+	return json.Marshal(plain(r))
+}
+
+func (r *DateRange) UnmarshalJSON(data []byte) error {
+	type plain DateRange
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := DateRange(v).Validate(); err != nil {
+		return err
+	}
+	*r = DateRange(v)
+	return nil
+}
+
+func (r DateRange) MarshalText() ([]byte, error) {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return nil, nil
+	}
+	return []byte("[" + r.Start.String() + "," + r.End.String() + "]"), nil
+}
+
+func (r *DateRange) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*r = DateRange{}
+		return nil
+	}
+	start, end, startExclusive, endExclusive, err := splitRange(string(data))
+	if err != nil {
+		return err
+	}
+	var v DateRange
+	if start, err := parseTime(start, DateLayout()); err != nil {
+		return err
+	} else {
+		if startExclusive && !start.IsZero() {
+			start = start.AddDate(0, 0, 1)
+		}
+		v.Start = LocalDate(start)
+	}
+	if end, err := parseTime(end, DateLayout()); err != nil {
+		return err
+	} else {
+		if endExclusive && !end.IsZero() {
+			end = end.AddDate(0, 0, -1)
+		}
+		v.End = LocalDate(end)
+	}
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func (r DateRange) Value() (driver.Value, error) {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return nil, nil
+	}
+	data, _ := r.MarshalText()
+	return string(data), nil
+}
+
+func (r *DateRange) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*r = DateRange{}
+		return nil
+	case []byte:
+		return r.UnmarshalText(value)
+	case string:
+		return r.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to DateRange", src)
+}
+
+func (r TimeRange) Validate() error {
+	if !r.Start.IsZero() && !r.End.IsZero() && time.Time(r.Start).After(time.Time(r.End)) {
+		return errInvalidRange
+	}
+	return nil
+}
+
+func (r TimeRange) Contains(t time.Time) bool {
+	return rangeContains(time.Time(r.Start), time.Time(r.End), t)
+}
+
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return rangeOverlaps(time.Time(r.Start), time.Time(r.End), time.Time(other.Start), time.Time(other.End))
+}
+
+// Duration returns the length of a closed range, or 0 if a bound is open
+func (r TimeRange) Duration() time.Duration {
+	if r.Start.IsZero() || r.End.IsZero() {
+		return 0
+	}
+	return time.Time(r.End).Sub(time.Time(r.Start))
+}
+
+// MarshalJSON keeps the object form, MarshalText is only used for keys and text encoders
+func (r TimeRange) MarshalJSON() ([]byte, error) {
+	type plain TimeRange
+	return json.Marshal(plain(r))
+}
+
+func (r *TimeRange) UnmarshalJSON(data []byte) error {
+	type plain TimeRange
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := TimeRange(v).Validate(); err != nil {
+		return err
+	}
+	*r = TimeRange(v)
+	return nil
+}
+
+func (r TimeRange) MarshalText() ([]byte, error) {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return nil, nil
+	}
+	// bounds a nanosecond inside a whole second came from exclusive bounds
+	open, start := "[", r.Start
+	if time.Time(start).Nanosecond() == 1 {
+		open, start = "(", LocalTime(time.Time(start).Add(-time.Nanosecond))
+	}
+	end, closing := r.End, "]"
+	if time.Time(end).Nanosecond() == 999999999 {
+		end, closing = LocalTime(time.Time(end).Add(time.Nanosecond)), ")"
+	}
+	return []byte(open + start.String() + "," + end.String() + closing), nil
+}
+
+func (r *TimeRange) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*r = TimeRange{}
+		return nil
+	}
+	start, end, startExclusive, endExclusive, err := splitRange(string(data))
+	if err != nil {
+		return err
+	}
+	var v TimeRange
+	if start, err := parseTime(start, TimeLayout()); err != nil {
+		return err
+	} else {
+		if startExclusive && !start.IsZero() {
+			start = start.Add(time.Nanosecond)
+		}
+		v.Start = LocalTime(start)
+	}
+	if end, err := parseTime(end, TimeLayout()); err != nil {
+		return err
+	} else {
+		if endExclusive && !end.IsZero() {
+			end = end.Add(-time.Nanosecond)
+		}
+		v.End = LocalTime(end)
+	}
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func (r TimeRange) Value() (driver.Value, error) {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return nil, nil
+	}
+	data, _ := r.MarshalText()
+	return string(data), nil
+}
+
+func (r *TimeRange) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*r = TimeRange{}
+		return nil
+	case []byte:
+		return r.UnmarshalText(value)
+	case string:
+		return r.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to TimeRange", src)
+}
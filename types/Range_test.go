@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateRange(t *testing.T) {
+	var r DateRange
+	if err := json.Unmarshal([]byte(`{"start":"2024-01-01","end":"2024-01-31"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Days() != 31 {
+		t.Errorf("expected 31 days, got %d", r.Days())
+	}
+	if !r.Contains(time.Date(2024, 1, 31, 23, 0, 0, 0, Location())) {
+		t.Error("expected the last day to be contained")
+	}
+	if r.Contains(time.Date(2024, 2, 1, 0, 0, 0, 0, Location())) {
+		t.Error("expected the day after the range not to be contained")
+	}
+	if !r.Overlaps(DateRange{Start: r.End}) {
+		t.Error("expected an open range starting on the last day to overlap")
+	}
+	if err := json.Unmarshal([]byte(`{"start":"2024-02-01","end":"2024-01-31"}`), &r); err == nil {
+		t.Error("expected an error for a reversed range")
+	}
+
+	value, _ := r.Value()
+	if value != "[2024-01-01,2024-01-31]" {
+		t.Errorf("unexpected value %v", value)
+	}
+	var scanned DateRange
+	if err := scanned.Scan([]byte("[2024-01-01,2024-02-01)")); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != r {
+		t.Errorf("expected %v, got %v", r, scanned)
+	}
+	if scanned.Contains(time.Date(2024, 2, 1, 0, 0, 0, 0, Location())) {
+		t.Error("expected the exclusive end not to be contained")
+	}
+	if err := scanned.Scan("(2023-12-31,2024-01-31]"); err != nil || scanned != r {
+		t.Errorf("expected %v, got %v %v", r, scanned, err)
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	var r TimeRange
+	if err := r.Scan("2024-01-01 08:00:00~2024-01-01 18:00:00"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Duration() != 10*time.Hour {
+		t.Errorf("expected 10h, got %v", r.Duration())
+	}
+	if r.Contains(time.Date(2024, 1, 1, 18, 0, 1, 0, Location())) {
+		t.Error("expected a time after the end not to be contained")
+	}
+	if r.Overlaps(TimeRange{Start: LocalTime(time.Date(2024, 1, 1, 19, 0, 0, 0, Location()))}) {
+		t.Error("expected a later open range not to overlap")
+	}
+	if err := r.Scan("2024-01-02 00:00:00,2024-01-01 00:00:00"); err == nil {
+		t.Error("expected an error for a reversed range")
+	}
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, Location())
+	for _, c := range []struct {
+		in                         string
+		containsStart, containsEnd bool
+	}{
+		{"[2024-01-01 00:00:00,2024-01-02 00:00:00]", true, true},
+		{"[2024-01-01 00:00:00,2024-01-02 00:00:00)", true, false},
+		{"(2024-01-01 00:00:00,2024-01-02 00:00:00]", false, true},
+		{"(2024-01-01 00:00:00,2024-01-02 00:00:00)", false, false},
+	} {
+		if err := r.Scan(c.in); err != nil {
+			t.Fatalf("%s: %v", c.in, err)
+		}
+		if r.Contains(day) != c.containsStart || r.Contains(day.AddDate(0, 0, 1)) != c.containsEnd {
+			t.Errorf("%s: unexpected bounds %v %v", c.in, time.Time(r.Start), time.Time(r.End))
+		}
+		if !r.Contains(day.Add(12 * time.Hour)) {
+			t.Errorf("%s: expected noon to be contained", c.in)
+		}
+		if value, _ := r.Value(); value != c.in {
+			t.Errorf("expected %s written back, got %v", c.in, value)
+		}
+	}
+	if err := r.Scan("[2024-01-01 00:00:00,)"); err != nil || !r.End.IsZero() {
+		t.Errorf("expected an open end, got %v %v", time.Time(r.End), err)
+	}
+}
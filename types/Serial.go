@@ -1,6 +1,7 @@
 package types
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"strconv"
 )
@@ -30,3 +31,29 @@ func (col *Serial) UnmarshalJSON(data []byte) error {
 	*col = Serial(v)
 	return nil
 }
+
+func (col Serial) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(col), 10)), nil
+}
+
+func (col *Serial) UnmarshalText(data []byte) error {
+	v, err := parseInt64(string(data))
+	if err != nil {
+		return err
+	}
+	*col = Serial(v)
+	return nil
+}
+
+func (col Serial) Value() (driver.Value, error) {
+	return int64(col), nil
+}
+
+func (col *Serial) Scan(src interface{}) error {
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	*col = Serial(v)
+	return nil
+}
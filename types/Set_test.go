@@ -0,0 +1,44 @@
+package types
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(2, 3, 4)
+
+	a.Add(3, 5)
+	a.Delete(5)
+	if a.Len() != 3 || !a.Has(1) || a.Has(5) {
+		t.Errorf("unexpected %v", a)
+	}
+	if s := fmt.Sprint(a.Union(b).Sorted(cmp.Compare[int])); s != "[1 2 3 4]" {
+		t.Errorf("unexpected union %s", s)
+	}
+	if s := fmt.Sprint(a.Intersect(b).Sorted(cmp.Compare[int])); s != "[2 3]" {
+		t.Errorf("unexpected intersection %s", s)
+	}
+	if s := fmt.Sprint(a.Difference(b).Sorted(cmp.Compare[int])); s != "[1]" {
+		t.Errorf("unexpected difference %s", s)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	data, _ := json.Marshal(NewSet(10, 9, 100))
+	if string(data) != `[9,10,100]` {
+		t.Errorf("unexpected json %s", data)
+	}
+	data, _ = json.Marshal(NewSet("b", "a", "c"))
+	if string(data) != `["a","b","c"]` {
+		t.Errorf("unexpected json %s", data)
+	}
+
+	var s Set[string]
+	if err := json.Unmarshal([]byte(`["x","y","x"]`), &s); err != nil || s.Len() != 2 || !s.Has("y") {
+		t.Errorf("unexpected %v %v", s, err)
+	}
+}
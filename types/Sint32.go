@@ -1,7 +1,9 @@
 package types
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -31,3 +33,36 @@ func (col *Sint32) UnmarshalJSON(data []byte) error {
 	*col = Sint32(v)
 	return nil
 }
+
+func (col Sint32) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(col), 10)), nil
+}
+
+func (col *Sint32) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = Sint32(0)
+		return nil
+	}
+	v, err := strconv.ParseInt(string(data), 10, 32)
+	if err != nil {
+		return err
+	}
+	*col = Sint32(v)
+	return nil
+}
+
+func (col Sint32) Value() (driver.Value, error) {
+	return int64(col), nil
+}
+
+func (col *Sint32) Scan(src interface{}) error {
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return fmt.Errorf("value %d out of range for Sint32", v)
+	}
+	*col = Sint32(v)
+	return nil
+}
@@ -0,0 +1,126 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Sint64 is an int64 marshaled as a JSON string, so IDs above 2^53 survive JavaScript clients.
+// Quoted and bare numbers are both accepted.
+type Sint64 int64
+
+// Suint64 is the unsigned counterpart of Sint64
+type Suint64 uint64
+
+func (col Sint64) String() string {
+	return strconv.FormatInt(int64(col), 10)
+}
+
+func (col Sint64) MarshalCSV() (string, error) {
+	return fmt.Sprintf("\"%s\"", col.String()), nil
+}
+
+func (col Sint64) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + col.String() + "\""), nil
+}
+
+func (col *Sint64) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		s = ""
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col Sint64) MarshalText() ([]byte, error) {
+	return []byte(col.String()), nil
+}
+
+func (col *Sint64) UnmarshalText(data []byte) error {
+	v, err := parseInt64(string(data))
+	if err != nil {
+		return err
+	}
+	*col = Sint64(v)
+	return nil
+}
+
+func (col Sint64) Value() (driver.Value, error) {
+	return int64(col), nil
+}
+
+func (col *Sint64) Scan(src interface{}) error {
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	*col = Sint64(v)
+	return nil
+}
+
+func (col Suint64) String() string {
+	return strconv.FormatUint(uint64(col), 10)
+}
+
+func (col Suint64) MarshalCSV() (string, error) {
+	return fmt.Sprintf("\"%s\"", col.String()), nil
+}
+
+func (col Suint64) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + col.String() + "\""), nil
+}
+
+func (col *Suint64) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		s = ""
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col Suint64) MarshalText() ([]byte, error) {
+	return []byte(col.String()), nil
+}
+
+func (col *Suint64) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*col = Suint64(0)
+		return nil
+	}
+	v, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*col = Suint64(v)
+	return nil
+}
+
+// Value fails for values above math.MaxInt64, which database/sql can't pass as int64;
+// store those in a decimal or string column
+func (col Suint64) Value() (driver.Value, error) {
+	if uint64(col) > math.MaxInt64 {
+		return nil, fmt.Errorf("value %d out of range for int64", uint64(col))
+	}
+	return int64(col), nil
+}
+
+func (col *Suint64) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return col.UnmarshalText(value)
+	case string:
+		return col.UnmarshalText([]byte(value))
+	}
+	v, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	if v < 0 {
+		return fmt.Errorf("value %d out of range for Suint64", v)
+	}
+	*col = Suint64(v)
+	return nil
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestSint64JSON(t *testing.T) {
+	var v struct {
+		ID     Sint64  `json:"id"`
+		Parent Sint64  `json:"parent"`
+		Hash   Suint64 `json:"hash"`
+	}
+	if err := json.Unmarshal([]byte(`{"id":"9007199254740993","parent":-42,"hash":18446744073709551615}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID != 9007199254740993 || v.Parent != -42 || v.Hash != math.MaxUint64 {
+		t.Errorf("unexpected values %+v", v)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"id":"9007199254740993","parent":"-42","hash":"18446744073709551615"}` {
+		t.Errorf("unexpected json %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"abc"}`), &v); err == nil {
+		t.Error("expected an error for a non numeric id")
+	}
+}
+
+func TestSuint64SQL(t *testing.T) {
+	var v Suint64
+	if err := v.Scan([]byte("18446744073709551615")); err != nil || v != math.MaxUint64 {
+		t.Errorf("expected max uint64, got %d %v", v, err)
+	}
+	if _, err := v.Value(); err == nil {
+		t.Error("expected an error for a value above max int64")
+	}
+	if err := v.Scan(int64(-1)); err == nil {
+		t.Error("expected an error for a negative value")
+	}
+}
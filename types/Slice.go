@@ -0,0 +1,184 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StringSlice is a JSON array stored in SQL as "a,b,c" (Postgres "{a,b,c}" is also scanned)
+type StringSlice []string
+
+// Int64Slice is a JSON array stored in SQL as "1,2,3" (Postgres "{1,2,3}" is also scanned)
+type Int64Slice []int64
+
+// splitList splits a comma-separated list or a Postgres array literal
+func splitList(s string) []string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		return parsePgArray(s[1 : len(s)-1])
+	}
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// parsePgArray parses the body of a one-dimensional Postgres array literal
+func parsePgArray(s string) []string {
+	var (
+		items   []string
+		sb      strings.Builder
+		quoted  bool
+		escaped bool
+		started bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case r == ',' && !quoted:
+			items = append(items, sb.String())
+			sb.Reset()
+			started = false
+		default:
+			sb.WriteRune(r)
+			started = true
+		}
+	}
+	if started || len(items) > 0 {
+		items = append(items, sb.String())
+	}
+	return items
+}
+
+func (col StringSlice) MarshalJSON() ([]byte, error) {
+	if col == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(col))
+}
+
+func (col *StringSlice) UnmarshalJSON(data []byte) error {
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		// Accept a comma-separated string as well
+		var s string
+		if json.Unmarshal(data, &s) != nil {
+			return err
+		}
+		items = splitList(s)
+	}
+	*col = items
+	return nil
+}
+
+func (col StringSlice) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(col, ",")), nil
+}
+
+func (col *StringSlice) UnmarshalText(data []byte) error {
+	*col = splitList(string(data))
+	return nil
+}
+
+func (col *StringSlice) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = nil
+	case []byte:
+		*col = splitList(string(value))
+	case string:
+		*col = splitList(value)
+	default:
+		return fmt.Errorf("can not convert %v to StringSlice", src)
+	}
+	return nil
+}
+
+func (col StringSlice) Value() (driver.Value, error) {
+	return strings.Join(col, ","), nil
+}
+
+func (col Int64Slice) MarshalJSON() ([]byte, error) {
+	if col == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]int64(col))
+}
+
+func (col *Int64Slice) UnmarshalJSON(data []byte) error {
+	var items []int64
+	if err := json.Unmarshal(data, &items); err == nil {
+		*col = items
+		return nil
+	}
+	// Accept quoted numbers and comma-separated strings as well
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		var s string
+		if json.Unmarshal(data, &s) != nil {
+			return fmt.Errorf("can not convert %s to Int64Slice", data)
+		}
+		values = splitList(s)
+	}
+	return col.parse(values)
+}
+
+func (col Int64Slice) MarshalText() ([]byte, error) {
+	items := make([]string, len(col))
+	for i, v := range col {
+		items[i] = strconv.FormatInt(v, 10)
+	}
+	return []byte(strings.Join(items, ",")), nil
+}
+
+func (col *Int64Slice) UnmarshalText(data []byte) error {
+	return col.parse(splitList(string(data)))
+}
+
+func (col *Int64Slice) parse(values []string) error {
+	if values == nil {
+		*col = nil
+		return nil
+	}
+	items := make(Int64Slice, len(values))
+	for i, v := range values {
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return err
+		}
+		items[i] = n
+	}
+	*col = items
+	return nil
+}
+
+func (col *Int64Slice) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*col = nil
+		return nil
+	case []byte:
+		return col.parse(splitList(string(value)))
+	case string:
+		return col.parse(splitList(value))
+	}
+	return fmt.Errorf("can not convert %v to Int64Slice", src)
+}
+
+func (col Int64Slice) Value() (driver.Value, error) {
+	values := make([]string, len(col))
+	for i, v := range col {
+		values[i] = strconv.FormatInt(v, 10)
+	}
+	return strings.Join(values, ","), nil
+}
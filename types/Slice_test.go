@@ -0,0 +1,55 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStringSlice(t *testing.T) {
+	var s StringSlice
+	if err := s.Scan([]byte("a,b,c")); err != nil || !reflect.DeepEqual(s, StringSlice{"a", "b", "c"}) {
+		t.Errorf("unexpected %v %v", s, err)
+	}
+	if err := s.Scan(`{plain,"with space","with,comma","with \"quote\""}`); err != nil ||
+		!reflect.DeepEqual(s, StringSlice{"plain", "with space", "with,comma", `with "quote"`}) {
+		t.Errorf("unexpected %#v %v", s, err)
+	}
+	if err := s.Scan("{}"); err != nil || len(s) != 0 {
+		t.Errorf("unexpected %#v %v", s, err)
+	}
+	if value, _ := (StringSlice{"a", "b"}).Value(); value != "a,b" {
+		t.Errorf("unexpected value %v", value)
+	}
+
+	data, _ := json.Marshal(struct{ Tags StringSlice }{})
+	if string(data) != `{"Tags":[]}` {
+		t.Errorf("unexpected json %s", data)
+	}
+	if err := json.Unmarshal([]byte(`"x,y"`), &s); err != nil || !reflect.DeepEqual(s, StringSlice{"x", "y"}) {
+		t.Errorf("unexpected %v %v", s, err)
+	}
+}
+
+func TestInt64Slice(t *testing.T) {
+	var s Int64Slice
+	if err := s.Scan("1,2,3"); err != nil || !reflect.DeepEqual(s, Int64Slice{1, 2, 3}) {
+		t.Errorf("unexpected %v %v", s, err)
+	}
+	if err := s.Scan([]byte("{4,5}")); err != nil || !reflect.DeepEqual(s, Int64Slice{4, 5}) {
+		t.Errorf("unexpected %v %v", s, err)
+	}
+	if err := s.Scan("1,x"); err == nil {
+		t.Error("expected an error")
+	}
+	if value, _ := (Int64Slice{7, 8}).Value(); value != "7,8" {
+		t.Errorf("unexpected value %v", value)
+	}
+	if err := json.Unmarshal([]byte(`["9","10"]`), &s); err != nil || !reflect.DeepEqual(s, Int64Slice{9, 10}) {
+		t.Errorf("unexpected %v %v", s, err)
+	}
+	data, _ := json.Marshal(Int64Slice{1, 2})
+	if string(data) != `[1,2]` {
+		t.Errorf("unexpected json %s", data)
+	}
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UUID is an RFC 4122 UUID, stored in SQL as its canonical string
+type UUID [16]byte
+
+// BinaryUUID is a UUID stored in SQL as 16 raw bytes (BINARY(16) columns)
+type BinaryUUID UUID
+
+// NilUUID is the all-zero UUID
+var NilUUID UUID
+
+// NewV4 returns a random UUID
+func NewV4() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return NilUUID, err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant RFC 4122
+	return u, nil
+}
+
+// NewV7 returns a time-ordered UUID starting with the Unix timestamp in milliseconds
+func NewV7() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[6:]); err != nil {
+		return NilUUID, err
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(u[:6], ms[2:])
+	u[6] = (u[6] & 0x0f) | 0x70 // version 7
+	u[8] = (u[8] & 0x3f) | 0x80 // variant RFC 4122
+	return u, nil
+}
+
+// ParseUUID accepts the canonical form, 32 hex digits, braces and the urn:uuid: prefix
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	raw := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "urn:uuid:")
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "{"), "}")
+	if len(raw) == 36 {
+		if raw[8] != '-' || raw[13] != '-' || raw[18] != '-' || raw[23] != '-' {
+			return NilUUID, fmt.Errorf("invalid UUID %q", s)
+		}
+		raw = raw[:8] + raw[9:13] + raw[14:18] + raw[19:23] + raw[24:]
+	}
+	if len(raw) != 32 {
+		return NilUUID, fmt.Errorf("invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(raw)); err != nil {
+		return NilUUID, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// MustParseUUID is like ParseUUID but panics on invalid input
+func MustParseUUID(s string) UUID {
+	u, err := ParseUUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+func (u UUID) IsNil() bool {
+	return u == NilUUID
+}
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *UUID) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*u = NilUUID
+		return nil
+	}
+	v, err := ParseUUID(string(data))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + u.String() + "\""), nil
+}
+
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		return nil
+	}
+	return u.UnmarshalText([]byte(s))
+}
+
+func (u *UUID) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*u = NilUUID
+		return nil
+	case []byte:
+		if len(value) == 16 {
+			copy(u[:], value)
+			return nil
+		}
+		return u.UnmarshalText(value)
+	case string:
+		return u.UnmarshalText([]byte(value))
+	}
+	return fmt.Errorf("can not convert %v to UUID", src)
+}
+
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+func (u BinaryUUID) String() string {
+	return UUID(u).String()
+}
+
+func (u BinaryUUID) MarshalText() ([]byte, error) {
+	return UUID(u).MarshalText()
+}
+
+func (u *BinaryUUID) UnmarshalText(data []byte) error {
+	return (*UUID)(u).UnmarshalText(data)
+}
+
+func (u BinaryUUID) MarshalJSON() ([]byte, error) {
+	return UUID(u).MarshalJSON()
+}
+
+func (u *BinaryUUID) UnmarshalJSON(data []byte) error {
+	return (*UUID)(u).UnmarshalJSON(data)
+}
+
+func (u *BinaryUUID) Scan(src interface{}) error {
+	return (*UUID)(u).Scan(src)
+}
+
+func (u BinaryUUID) Value() (driver.Value, error) {
+	return u[:], nil
+}
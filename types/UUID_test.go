@@ -0,0 +1,68 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseUUID(t *testing.T) {
+	expected := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for _, in := range []string{
+		expected,
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"6ba7b8109dad11d180b400c04fd430c8",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	} {
+		u, err := ParseUUID(in)
+		if err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if u.String() != expected {
+			t.Errorf("%s: expected %s, got %s", in, expected, u)
+		}
+	}
+	for _, in := range []string{"", "6ba7b810-9dad-11d1-80b4", "6ba7b810x9dad-11d1-80b4-00c04fd430c8", "zba7b8109dad11d180b400c04fd430c8"} {
+		if _, err := ParseUUID(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	v4, err := NewV4()
+	if err != nil || v4.Version() != 4 || v4[8]&0xc0 != 0x80 {
+		t.Fatalf("unexpected v4 %s %v", v4, err)
+	}
+	a, _ := NewV7()
+	time.Sleep(2 * time.Millisecond)
+	b, _ := NewV7()
+	if a.Version() != 7 || a.String() >= b.String() {
+		t.Errorf("expected ordered v7 UUIDs, got %s %s", a, b)
+	}
+}
+
+func TestUUIDJSONAndSQL(t *testing.T) {
+	u := MustParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	data, _ := json.Marshal(map[UUID]UUID{u: u})
+	if string(data) != `{"6ba7b810-9dad-11d1-80b4-00c04fd430c8":"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}` {
+		t.Errorf("unexpected json %s", data)
+	}
+	var decoded UUID
+	if err := json.Unmarshal([]byte(`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`), &decoded); err != nil || decoded != u {
+		t.Errorf("expected %s, got %s %v", u, decoded, err)
+	}
+
+	var scanned UUID
+	if err := scanned.Scan(u[:]); err != nil || scanned != u {
+		t.Errorf("expected %s, got %s %v", u, scanned, err)
+	}
+	if err := scanned.Scan(nil); err != nil || !scanned.IsNil() {
+		t.Errorf("expected nil UUID, got %s %v", scanned, err)
+	}
+	if value, _ := BinaryUUID(u).Value(); len(value.([]byte)) != 16 {
+		t.Errorf("expected 16 bytes, got %v", value)
+	}
+}
@@ -1,7 +1,12 @@
 package types
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type H map[string]interface{}
@@ -12,3 +17,92 @@ func stringUnmarshalJSON(b []byte) (s string, err error) {
 	}
 	return s, nil
 }
+
+// scanInt64 converts a database value to int64, NULL becomes 0
+func scanInt64(src interface{}) (int64, error) {
+	switch value := src.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return value, nil
+	case float64:
+		return int64(value), nil
+	case bool:
+		if value {
+			return 1, nil
+		}
+		return 0, nil
+	case []byte:
+		return parseInt64(string(value))
+	case string:
+		return parseInt64(value)
+	case time.Time:
+		return value.Unix(), nil
+	}
+	return 0, fmt.Errorf("can not convert %v to int64", src)
+}
+
+func parseInt64(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// scanTime converts a database value to time.Time, NULL becomes the zero time
+func scanTime(src interface{}, layout string) (time.Time, error) {
+	switch value := src.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return value, nil
+	case []byte:
+		return parseTime(string(value), layout)
+	case string:
+		return parseTime(value, layout)
+	case int64:
+		return time.Unix(value, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("can not convert %v to timestamp", src)
+}
+
+func parseTime(s string, layout string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(layout, s, Location()); err == nil {
+		return t, nil
+	}
+	return ParseTime(s)
+}
+
+// ParseTime tries the time layout, then every parse layout in the configured location,
+// then a Unix epoch in seconds or milliseconds
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := Location()
+	if t, err := time.ParseInLocation(TimeLayout(), s, loc); err == nil {
+		return t, nil
+	}
+	for _, layout := range ParseLayouts() {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if epoch, err := strconv.ParseInt(s, 10, 64); err == nil {
+		// 13 位时间戳为毫秒
+		if epoch > 1e12 || epoch < -1e12 {
+			return time.UnixMilli(epoch).In(loc), nil
+		}
+		return time.Unix(epoch, 0).In(loc), nil
+	}
+	return time.Time{}, fmt.Errorf("can not parse %q as time", s)
+}
+
+// timeValue maps the zero time to NULL
+func timeValue(t time.Time) (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t, nil
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScanNullToZero(t *testing.T) {
+	jt := Jtime(time.Now())
+	jd := Jdate("2024-01-02")
+	je := Jepoch(1)
+	si := Sint32(1)
+	se := Serial(1)
+	in := Int64(1)
+	for _, scanner := range []interface{ Scan(interface{}) error }{&jt, &jd, &je, &si, &se, &in} {
+		if err := scanner.Scan(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !time.Time(jt).IsZero() || jd != "" || je != 0 || si != 0 || se != 0 || in != 0 {
+		t.Errorf("expected zero values, got %v %v %v %v %v %v", jt, jd, je, si, se, in)
+	}
+}
+
+func TestScanValues(t *testing.T) {
+	var jt Jtime
+	if err := jt.Scan([]byte("2024-01-02 03:04:05")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := jt.Value(); v.(time.Time).Format(DefaultTimeLayout) != "2024-01-02 03:04:05" {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	var jd Jdate
+	if err := jd.Scan(time.Date(2024, 1, 2, 0, 0, 0, 0, Location())); err != nil || jd != "2024-01-02" {
+		t.Errorf("expected 2024-01-02, got %v %v", jd, err)
+	}
+	if err := jd.Scan("2024-01-03 10:00:00"); err != nil || jd != "2024-01-03" {
+		t.Errorf("expected 2024-01-03, got %v %v", jd, err)
+	}
+
+	var se Serial
+	if err := se.Scan([]byte("9007199254740993")); err != nil || se != 9007199254740993 {
+		t.Errorf("expected 9007199254740993, got %v %v", se, err)
+	}
+	var si Sint32
+	if err := si.Scan(int64(1) << 40); err == nil {
+		t.Error("expected an out of range error")
+	}
+}
+
+func TestParseTimeLayouts(t *testing.T) {
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 0, Location())
+	for _, in := range []string{
+		"2024-01-02 03:04:05",
+		"2024-01-02T03:04:05",
+		expected.Format(time.RFC3339),
+		expected.UTC().Format(time.RFC3339Nano),
+		"1704135845",
+		"1704135845000",
+	} {
+		var jt Jtime
+		if err := jt.UnmarshalJSON([]byte(`"` + in + `"`)); err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if !time.Time(jt).Equal(expected) {
+			t.Errorf("%s: expected %v, got %v", in, expected, time.Time(jt))
+		}
+	}
+
+	var lt LocalTime
+	if err := lt.UnmarshalJSON([]byte("1704135845")); err != nil || !time.Time(lt).Equal(expected) {
+		t.Errorf("expected %v, got %v %v", expected, time.Time(lt), err)
+	}
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,87 @@
+// Package bsontypes stores the time types of package types as BSON datetimes, kept apart so that
+// package types does not depend on the MongoDB driver.
+//
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(bsontypes.NewRegistry()))
+package bsontypes
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/dreamsxin/go-utils/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+var (
+	tTime      = reflect.TypeOf(time.Time{})
+	tLocalTime = reflect.TypeOf(types.LocalTime{})
+	tLocalDate = reflect.TypeOf(types.LocalDate{})
+	tJtime     = reflect.TypeOf(types.Jtime{})
+)
+
+// NewRegistry returns the default BSON registry with the codecs of LocalTime, LocalDate and Jtime
+func NewRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	Register(registry)
+	return registry
+}
+
+// Register adds the codecs of LocalTime, LocalDate and Jtime to registry.
+// Zero times are stored as null, and null or undefined values are read as zero times.
+func Register(registry *bsoncodec.Registry) {
+	for _, t := range []reflect.Type{tLocalTime, tLocalDate, tJtime} {
+		registry.RegisterTypeEncoder(t, bsoncodec.ValueEncoderFunc(encodeTime))
+		registry.RegisterTypeDecoder(t, bsoncodec.ValueDecoderFunc(decodeTime))
+	}
+}
+
+func encodeTime(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || !val.Type().ConvertibleTo(tTime) {
+		return bsoncodec.ValueEncoderError{
+			Name:     "TimeEncodeValue",
+			Types:    []reflect.Type{tLocalTime, tLocalDate, tJtime},
+			Received: val,
+		}
+	}
+	t := val.Convert(tTime).Interface().(time.Time)
+	if t.IsZero() {
+		return vw.WriteNull()
+	}
+	return vw.WriteDateTime(t.UnixMilli())
+}
+
+func decodeTime(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || !tTime.ConvertibleTo(val.Type()) {
+		return bsoncodec.ValueDecoderError{
+			Name:     "TimeDecodeValue",
+			Types:    []reflect.Type{tLocalTime, tLocalDate, tJtime},
+			Received: val,
+		}
+	}
+
+	var t time.Time
+	switch vr.Type() {
+	case bsontype.DateTime:
+		ms, err := vr.ReadDateTime()
+		if err != nil {
+			return err
+		}
+		t = time.UnixMilli(ms).In(types.Location())
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("bsontypes: cannot decode %v into a %s", vr.Type(), val.Type())
+	}
+	val.Set(reflect.ValueOf(t).Convert(val.Type()))
+	return nil
+}
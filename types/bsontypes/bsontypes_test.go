@@ -0,0 +1,52 @@
+package bsontypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dreamsxin/go-utils/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type model struct {
+	CreatedAt types.LocalTime `bson:"created_at"`
+	Birthday  types.LocalDate `bson:"birthday"`
+	UpdatedAt types.Jtime     `bson:"updated_at"`
+	DeletedAt types.LocalTime `bson:"deleted_at"`
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	now := time.Date(2024, 3, 1, 12, 30, 45, 123000000, time.UTC)
+	in := model{
+		CreatedAt: types.LocalTime(now),
+		Birthday:  types.LocalDate(time.Date(2000, 1, 2, 0, 0, 0, 0, types.Location())),
+		UpdatedAt: types.Jtime(now),
+	}
+
+	data, err := bson.MarshalWithRegistry(registry, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := bson.Raw(data)
+	if at, ok := raw.Lookup("created_at").TimeOK(); !ok || !at.Equal(now) {
+		t.Errorf("expect created_at stored as a datetime, get %v", raw.Lookup("created_at"))
+	}
+	if _, ok := raw.Lookup("deleted_at").TimeOK(); ok {
+		t.Error("expect a zero time stored as null")
+	}
+
+	var out model
+	if err := bson.UnmarshalWithRegistry(registry, data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(out.CreatedAt).Equal(now) || !time.Time(out.UpdatedAt).Equal(now) {
+		t.Errorf("expect %v, get %v and %v", now, time.Time(out.CreatedAt), time.Time(out.UpdatedAt))
+	}
+	if got := out.Birthday.String(); got != "2000-01-02" {
+		t.Errorf("expect 2000-01-02, get %s", got)
+	}
+	if !out.DeletedAt.IsZero() {
+		t.Errorf("expect a zero time, get %v", time.Time(out.DeletedAt))
+	}
+}
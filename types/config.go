@@ -0,0 +1,128 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// Default time layouts, the default location is Asia/Shanghai
+const (
+	DefaultTimeLayout = "2006-01-02 15:04:05"
+	DefaultDateLayout = "2006-01-02"
+	DefaultHourLayout = "2006-01-02 15"
+)
+
+// DefaultParseLayouts are tried in order when the configured layout does not match
+var DefaultParseLayouts = []string{
+	time.RFC3339Nano,
+	DefaultTimeLayout,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006/01/02 15:04:05",
+	DefaultDateLayout,
+}
+
+var config = struct {
+	sync.RWMutex
+	location     *time.Location
+	timeLayout   string
+	dateLayout   string
+	hourLayout   string
+	parseLayouts []string
+	phoneRegion  string
+}{
+	location:     loadLocation("Asia/Shanghai"),
+	timeLayout:   DefaultTimeLayout,
+	dateLayout:   DefaultDateLayout,
+	hourLayout:   DefaultHourLayout,
+	parseLayouts: DefaultParseLayouts,
+	phoneRegion:  "CN",
+}
+
+// loadLocation falls back to a fixed UTC+8 zone when tzdata is unavailable
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("CST", 8*3600) // UTC+8
+	}
+	return loc
+}
+
+// SetLocation changes the location used to parse and format times, Asia/Shanghai by default
+func SetLocation(loc *time.Location) {
+	if loc == nil {
+		return
+	}
+	config.Lock()
+	config.location = loc
+	config.Unlock()
+}
+
+// Location returns the location used to parse and format times
+func Location() *time.Location {
+	config.RLock()
+	defer config.RUnlock()
+	return config.location
+}
+
+// SetTimeLayout changes the layout of Jtime, Jepoch and LocalTime
+func SetTimeLayout(layout string) {
+	config.Lock()
+	config.timeLayout = layout
+	config.Unlock()
+}
+
+// TimeLayout returns the layout of Jtime, Jepoch and LocalTime
+func TimeLayout() string {
+	config.RLock()
+	defer config.RUnlock()
+	return config.timeLayout
+}
+
+// SetDateLayout changes the layout of Jdate and LocalDate
+func SetDateLayout(layout string) {
+	config.Lock()
+	config.dateLayout = layout
+	config.Unlock()
+}
+
+// DateLayout returns the layout of Jdate and LocalDate
+func DateLayout() string {
+	config.RLock()
+	defer config.RUnlock()
+	return config.dateLayout
+}
+
+// SetHourLayout changes the layout of LocalHour
+func SetHourLayout(layout string) {
+	config.Lock()
+	config.hourLayout = layout
+	config.Unlock()
+}
+
+// HourLayout returns the layout of LocalHour
+func HourLayout() string {
+	config.RLock()
+	defer config.RUnlock()
+	return config.hourLayout
+}
+
+// SetParseLayouts changes the fallback layouts tried by ParseTime
+func SetParseLayouts(layouts ...string) {
+	config.Lock()
+	config.parseLayouts = append([]string(nil), layouts...)
+	config.Unlock()
+}
+
+// ParseLayouts returns the fallback layouts tried by ParseTime
+func ParseLayouts() []string {
+	config.RLock()
+	defer config.RUnlock()
+	return append([]string(nil), config.parseLayouts...)
+}
+
+// OverrideLocal sets time.Local to the configured location.
+// It affects every package of the process and is no longer done by default.
+func OverrideLocal() {
+	time.Local = Location()
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// GORM data types, GormDBDataType is only implemented where the column type depends on the dialect
+
+func (Jtime) GormDataType() string            { return string(schema.Time) }
+func (LocalTime) GormDataType() string        { return string(schema.Time) }
+func (LocalHour) GormDataType() string        { return string(schema.Time) }
+func (FormattedTime[F]) GormDataType() string { return string(schema.Time) }
+func (LocalDate) GormDataType() string        { return "date" }
+func (Jdate) GormDataType() string            { return "date" }
+
+func (Jepoch) GormDataType() string   { return string(schema.Int) }
+func (Epoch[U]) GormDataType() string { return string(schema.Int) }
+func (Duration) GormDataType() string { return string(schema.Int) }
+func (Serial) GormDataType() string   { return string(schema.Int) }
+func (Int64) GormDataType() string    { return string(schema.Int) }
+func (Sint64) GormDataType() string   { return string(schema.Int) }
+func (Suint64) GormDataType() string  { return string(schema.Uint) }
+func (Sint32) GormDataType() string   { return string(schema.Int) }
+func (Bool) GormDataType() string     { return string(schema.Bool) }
+
+func (StringSlice) GormDataType() string { return string(schema.String) }
+func (Int64Slice) GormDataType() string  { return string(schema.String) }
+func (DateRange) GormDataType() string   { return string(schema.String) }
+func (TimeRange) GormDataType() string   { return string(schema.String) }
+func (Email) GormDataType() string       { return string(schema.String) }
+func (Phone) GormDataType() string       { return string(schema.String) }
+
+func (Decimal) GormDataType() string    { return "decimal" }
+func (Json) GormDataType() string       { return "json" }
+func (UUID) GormDataType() string       { return "uuid" }
+func (BinaryUUID) GormDataType() string { return "binary_uuid" }
+func (IP) GormDataType() string         { return "ip" }
+func (CIDR) GormDataType() string       { return "cidr" }
+
+// GormDBDataType uses the precision and scale tags, e.g. `gorm:"precision:20;scale:2"`
+func (Decimal) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	name := "decimal"
+	if db.Dialector.Name() == "postgres" || db.Dialector.Name() == "sqlite" {
+		name = "numeric"
+	}
+	switch {
+	case field.Precision > 0:
+		return fmt.Sprintf("%s(%d,%d)", name, field.Precision, field.Scale)
+	case name == "decimal":
+		return "decimal(65,30)"
+	}
+	return name
+}
+
+func (Json) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql", "sqlite":
+		return "JSON"
+	case "postgres":
+		return "JSONB"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
+	}
+	return ""
+}
+
+func (UUID) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "uuid"
+	case "sqlserver":
+		return "uniqueidentifier"
+	}
+	return "char(36)"
+}
+
+func (BinaryUUID) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "bytea"
+	case "sqlite":
+		return "blob"
+	}
+	return "binary(16)"
+}
+
+func (IP) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "inet"
+	}
+	return "varchar(45)"
+}
+
+func (CIDR) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "cidr"
+	}
+	return "varchar(49)"
+}
+
+// GormDBDataType delegates to T, GORM otherwise derives the column from the type of V
+func (col Nullable[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if dataTyper, ok := any(col.V).(interface {
+		GormDBDataType(*gorm.DB, *schema.Field) string
+	}); ok {
+		return dataTyper.GormDBDataType(db, field)
+	}
+	return ""
+}
+
+// GormValue truncates to the day so a LocalDate built from a full time still matches date columns
+func (t LocalDate) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if t.IsZero() {
+		return clause.Expr{SQL: "?", Vars: []interface{}{nil}}
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{truncateDay(time.Time(t))}}
+}
+
+// GormValue truncates to the hour
+func (t LocalHour) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if t.IsZero() {
+		return clause.Expr{SQL: "?", Vars: []interface{}{nil}}
+	}
+	tt := time.Time(t).In(Location())
+	return clause.Expr{SQL: "?", Vars: []interface{}{time.Date(tt.Year(), tt.Month(), tt.Day(), tt.Hour(), 0, 0, 0, Location())}}
+}
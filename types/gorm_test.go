@@ -0,0 +1,36 @@
+package types
+
+import (
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestGormDataTypes(t *testing.T) {
+	type model struct {
+		ID       Serial
+		Birthday LocalDate
+		Amount   Decimal `gorm:"precision:20;scale:2"`
+		Extra    Json
+		Tags     StringSlice
+		Age      Nullable[Sint32]
+	}
+	s, err := schema.Parse(&model{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]schema.DataType{
+		"ID":       schema.Int,
+		"Birthday": "date",
+		"Amount":   "decimal",
+		"Extra":    "json",
+		"Tags":     schema.String,
+		"Age":      schema.Int,
+	}
+	for name, dataType := range expected {
+		if field := s.LookUpField(name); field == nil || field.DataType != dataType {
+			t.Errorf("%s: expected %s, got %+v", name, dataType, field)
+		}
+	}
+}
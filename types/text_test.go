@@ -0,0 +1,75 @@
+package types
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, Location())
+	serial, int64v := Serial(42), Int64(7)
+	cases := []struct {
+		in  encoding.TextMarshaler
+		out encoding.TextUnmarshaler
+	}{
+		{Jtime(now), new(Jtime)},
+		{Jdate("2024-05-06"), new(Jdate)},
+		{Jepoch(now.Unix()), new(Jepoch)},
+		{LocalTime(now), new(LocalTime)},
+		{LocalDate(time.Date(2024, 5, 6, 0, 0, 0, 0, Location())), new(LocalDate)},
+		{LocalHour(time.Date(2024, 5, 6, 7, 0, 0, 0, Location())), new(LocalHour)},
+		{serial, new(Serial)},
+		{&int64v, new(Int64)},
+		{Sint32(-3), new(Sint32)},
+		{RequireDecimalFromString("12.30"), new(Decimal)},
+		{NewJepochMilli(now), new(JepochMilli)},
+		{Json(`{"a":1}`), new(Json)},
+		{NewNullable(5), new(Nullable[int])},
+		{NewNullable("x"), new(Nullable[string])},
+		{StringSlice{"a", "b"}, new(StringSlice)},
+		{Int64Slice{1, 2}, new(Int64Slice)},
+		{DateRange{Start: LocalDate(now)}, new(DateRange)},
+	}
+	for _, c := range cases {
+		text, err := c.in.MarshalText()
+		if err != nil {
+			t.Fatalf("%T: %v", c.in, err)
+		}
+		if err := c.out.UnmarshalText(text); err != nil {
+			t.Fatalf("%T: %v", c.out, err)
+		}
+		again, _ := c.out.(encoding.TextMarshaler).MarshalText()
+		if string(again) != string(text) {
+			t.Errorf("%T: expected %q, got %q", c.in, text, again)
+		}
+	}
+}
+
+func TestTextMapKeys(t *testing.T) {
+	m := map[Jdate]int{"2024-05-06": 1}
+	data, err := json.Marshal(m)
+	if err != nil || string(data) != `{"2024-05-06":1}` {
+		t.Fatalf("unexpected json %s %v", data, err)
+	}
+
+	var decoded map[Decimal]int
+	if err := json.Unmarshal([]byte(`{"1.50":2}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range decoded {
+		if k.String() != "1.50" || v != 2 {
+			t.Errorf("unexpected entry %s=%d", k, v)
+		}
+	}
+
+	var r DateRange
+	data, _ = json.Marshal(DateRange{Start: LocalDate(time.Date(2024, 1, 1, 0, 0, 0, 0, Location()))})
+	if string(data) != `{"start":"2024-01-01","end":null}` {
+		t.Errorf("expected the object form, got %s", data)
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+}